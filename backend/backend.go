@@ -15,6 +15,18 @@
 // Package backend implements Zero Knowledge Proof systems: it consumes circuit compiled with gnark/frontend.
 package backend
 
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
 // ID represent a unique ID for a proving scheme
 type ID uint16
 
@@ -40,3 +52,241 @@ func (id ID) String() string {
 		return "unknown"
 	}
 }
+
+// ProverPhase identifies a step of a proof computation, as reported to a ProgressFunc
+type ProverPhase uint8
+
+const (
+	PhaseSolve       ProverPhase = iota // solving the constraint system
+	PhaseH                              // computing the quotient polynomial H (FFTs)
+	PhaseMSMA                           // groth16: multi-exponentiation computing [A]1
+	PhaseMSMB                           // groth16: multi-exponentiations computing [B]1 and [B]2
+	PhaseMSMK                           // groth16: multi-exponentiations computing [C]1 (the private part K and H)
+	PhaseCommitments                    // plonk: KZG commitments to l, r, o, z and the parts of H
+	PhaseOpenings                       // plonk: KZG opening proofs
+)
+
+// String returns the string representation of a prover phase
+func (p ProverPhase) String() string {
+	switch p {
+	case PhaseSolve:
+		return "solve"
+	case PhaseH:
+		return "H"
+	case PhaseMSMA:
+		return "MSM A"
+	case PhaseMSMB:
+		return "MSM B"
+	case PhaseMSMK:
+		return "MSM K"
+	case PhaseCommitments:
+		return "commitments"
+	case PhaseOpenings:
+		return "openings"
+	default:
+		return "unknown"
+	}
+}
+
+// ProgressFunc is called by the provers each time a phase of a proof computation is completed,
+// with the time elapsed since the beginning of the computation.
+//
+// Phases computed concurrently may complete in any order; calls to a ProgressFunc are never concurrent.
+type ProgressFunc func(phase ProverPhase, elapsed time.Duration)
+
+// ProverOption defines option for altering the behaviour of the prover in Prove, ProveContext,
+// ReadAndProve and IsSolved methods. See the functions returning instances of this type for
+// the implemented options.
+type ProverOption func(*ProverConfig) error
+
+// ProverConfig is the configuration of the prover, with the options applied
+type ProverConfig struct {
+	Force        bool         // complete the proof computation even if the witness doesn't solve the constraint system
+	LoggerOut    io.Writer    // output of the circuit logs (cs.Println, cs.Printf, cs.PrintIf); logs are not printed if nil
+	Logger       Logger       // receives the circuit logs instead of LoggerOut, if not nil
+	NbTasks      int          // number of goroutines used by the multi-exponentiations
+	NbTasksSet   bool         // NbTasks was set by WithNbTasks, and is not adjusted by the prover heuristics
+	RandomSource io.Reader    // source of the prover randomness
+	Progress     ProgressFunc // called at the end of each phase of the proof computation, if not nil
+	Diagnostics  bool         // the solver reports all the unsatisfied constraints, see WithDiagnostics
+}
+
+// NewProverConfig returns the default ProverConfig with the options opts applied
+//
+// By default, solver errors are not ignored, logs are printed on os.Stdout, the multi-exponentiations
+// use runtime.NumCPU() goroutines and the randomness is read from crypto/rand.Reader.
+func NewProverConfig(opts ...ProverOption) (ProverConfig, error) {
+	config := ProverConfig{
+		LoggerOut:    os.Stdout,
+		NbTasks:      runtime.NumCPU(),
+		RandomSource: rand.Reader,
+	}
+	for _, opt := range opts {
+		if err := opt(&config); err != nil {
+			return ProverConfig{}, err
+		}
+	}
+	return config, nil
+}
+
+// IgnoreSolverError is a prover option that indicates that the prover should complete,
+// even if the witness doesn't solve the constraint system. In that case, the prover
+// outputs an invalid proof, which is useful for test and benchmarking purposes.
+func IgnoreSolverError() ProverOption {
+	return func(config *ProverConfig) error {
+		config.Force = true
+		return nil
+	}
+}
+
+// WithLoggerOutput is a prover option that sets the output of the circuit logs (cs.Println, cs.Printf, cs.PrintIf)
+// printed when solving the constraint system. If w is nil, logs are not printed.
+//
+// Each log is printed on a line, prefixed by its location in the circuit (file.go:line).
+func WithLoggerOutput(w io.Writer) ProverOption {
+	return func(config *ProverConfig) error {
+		config.LoggerOut = w
+		return nil
+	}
+}
+
+// Logger receives the circuit logs (cs.Println, cs.Printf, cs.PrintIf) when solving a constraint system,
+// see WithLogger
+type Logger interface {
+	// Log is called for each log of the circuit once the constraint system is solved (or the solver failed),
+	// in the order of the circuit; caller is the location of the log in the circuit (file.go:line), msg
+	// the formatted message, and values the values of the variables of the log, nil for the wires not solved
+	Log(caller, msg string, values []*big.Int)
+}
+
+// LoggerFunc is an adapter to use a function as a Logger
+type LoggerFunc func(caller, msg string, values []*big.Int)
+
+// Log calls f(caller, msg, values)
+func (f LoggerFunc) Log(caller, msg string, values []*big.Int) {
+	f(caller, msg, values)
+}
+
+// WithLogger is a prover option that sets a Logger receiving the circuit logs, for instance to capture
+// them in tests; the logs are then not printed on the logger output (see WithLoggerOutput)
+func WithLogger(l Logger) ProverOption {
+	return func(config *ProverConfig) error {
+		config.Logger = l
+		return nil
+	}
+}
+
+// WithNbTasks is a prover option that sets the number of goroutines used by the multi-exponentiations
+// (see ecc.MultiExpConfig)
+func WithNbTasks(nbTasks int) ProverOption {
+	return func(config *ProverConfig) error {
+		if nbTasks < 1 {
+			return errors.New("number of tasks must be at least 1")
+		}
+		config.NbTasks = nbTasks
+		config.NbTasksSet = true
+		return nil
+	}
+}
+
+// WithRandomSource is a prover option that sets the source of the prover randomness
+//
+// The zero-knowledge property of the proofs relies on this randomness: r must be a cryptographically
+// secure random source, unless the proofs are computed for testing purposes.
+func WithRandomSource(r io.Reader) ProverOption {
+	return func(config *ProverConfig) error {
+		if r == nil {
+			return errors.New("random source must not be nil")
+		}
+		config.RandomSource = r
+		return nil
+	}
+}
+
+// WithProgress is a prover option that sets a function called at the end of each phase of
+// the proof computation (see ProgressFunc)
+func WithProgress(f ProgressFunc) ProverOption {
+	return func(config *ProverConfig) error {
+		config.Progress = f
+		return nil
+	}
+}
+
+// WithDiagnostics is a prover option that sets the solver in diagnostic mode: it doesn't stop at the first
+// unsatisfied constraint, and returns a *UnsatisfiedConstraintsError listing all the unsatisfied constraints,
+// with their debug info (source location in the circuit and resolved wire values). The source location of
+// the computational constraints is recorded if the circuit is compiled with frontend.WithDebugInfo.
+//
+// In diagnostic mode, the constraints are solved sequentially (opt.NbTasks is ignored by the solver).
+func WithDiagnostics() ProverOption {
+	return func(config *ProverConfig) error {
+		config.Diagnostics = true
+		return nil
+	}
+}
+
+// UnsatisfiedConstraint describes a constraint that is not satisfied by the solution of a constraint system
+type UnsatisfiedConstraint struct {
+	ID    int    // index of the constraint in the constraint system
+	Debug string // debug info of the constraint: source location in the circuit and resolved wire values
+}
+
+// UnsatisfiedConstraintsError is returned by the solver in diagnostic mode (see WithDiagnostics),
+// it lists all the constraints that are not satisfied by the solution, in the order of the solver
+type UnsatisfiedConstraintsError struct {
+	Err         error // error returned by the solver for an unsatisfied constraint, outside of diagnostic mode
+	Constraints []UnsatisfiedConstraint
+}
+
+func (e *UnsatisfiedConstraintsError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %d unsatisfied constraint(s)", e.Err, len(e.Constraints))
+	for _, c := range e.Constraints {
+		fmt.Fprintf(&sb, "\nconstraint #%d: %s", c.ID, c.Debug)
+	}
+	return sb.String()
+}
+
+// Unwrap returns e.Err, such that errors.Is(e, ErrUnsatisfiedConstraint) holds
+// for the ErrUnsatisfiedConstraint of the curve's constraint system
+func (e *UnsatisfiedConstraintsError) Unwrap() error {
+	return e.Err
+}
+
+// SetupOption defines option for altering the behaviour of groth16.Setup and plonk.NewSRS.
+// See the functions returning instances of this type for the implemented options.
+type SetupOption func(*SetupConfig) error
+
+// SetupConfig is the configuration of the setup, with the options applied
+type SetupConfig struct {
+	RandomSource io.Reader // source of the setup randomness (the toxic waste)
+}
+
+// NewSetupConfig returns the default SetupConfig with the options opts applied
+//
+// By default, the randomness is read from crypto/rand.Reader.
+func NewSetupConfig(opts ...SetupOption) (SetupConfig, error) {
+	config := SetupConfig{
+		RandomSource: rand.Reader,
+	}
+	for _, opt := range opts {
+		if err := opt(&config); err != nil {
+			return SetupConfig{}, err
+		}
+	}
+	return config, nil
+}
+
+// WithSetupRandomSource is a setup option that sets the source of the setup randomness
+//
+// The soundness of the proofs relies on this randomness being secret: r must be a cryptographically
+// secure random source, unless the keys are generated for testing purposes.
+func WithSetupRandomSource(r io.Reader) SetupOption {
+	return func(config *SetupConfig) error {
+		if r == nil {
+			return errors.New("random source must not be nil")
+		}
+		config.RandomSource = r
+		return nil
+	}
+}
@@ -0,0 +1,393 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package circom provides interoperability with circom and snarkjs.
+//
+// It reads and writes rank-1 constraint systems in the iden3 .r1cs binary format, and witnesses in the
+// iden3 .wtns binary format (see https://github.com/iden3/binfileformats).
+//
+// A gnark R1CS (compiled with backend.GROTH16) can be exported with FromCS and R1CS.WriteTo.
+// A circom circuit can be imported with ReadR1CS, compiled for gnark's groth16 or plonk backends with
+// R1CS.Compile, and its witness (computed by the circom witness generator) can be read with ReadWitness and
+// assigned with R1CS.Assign:
+//
+// 	r1cs, _ := circom.ReadR1CS(r1csFile)
+// 	ccs, _ := r1cs.Compile(backend.GROTH16)
+// 	pk, vk, _ := groth16.Setup(ccs)
+//
+// 	wtns, _ := circom.ReadWitness(wtnsFile)
+// 	witness, _ := r1cs.Assign(wtns)
+// 	proof, _ := groth16.Prove(ccs, pk, witness)
+// 	err := groth16.Verify(proof, vk, witness)
+//
+// Wires
+//
+// circom and gnark order the wires the same way: the constant wire ONE, then the public wires, then the secret
+// wires and finally the internal wires. Since the circom witness contains the values of all the wires, the imported
+// constraint system has no internal wires: circom's outputs and public inputs are gnark public variables,
+// and circom's private inputs and internal signals are gnark secret variables.
+package circom
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	frbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	frbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	frbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	frbn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	frbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	frbw6672 "github.com/consensys/gnark-crypto/ecc/bw6-672/fr"
+	frbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	backend_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/cs"
+	backend_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/cs"
+	backend_bls24315 "github.com/consensys/gnark/internal/backend/bls24-315/cs"
+	backend_bn254 "github.com/consensys/gnark/internal/backend/bn254/cs"
+	backend_bw6633 "github.com/consensys/gnark/internal/backend/bw6-633/cs"
+	backend_bw6672 "github.com/consensys/gnark/internal/backend/bw6-672/cs"
+	backend_bw6761 "github.com/consensys/gnark/internal/backend/bw6-761/cs"
+	"github.com/consensys/gnark/internal/backend/compiled"
+)
+
+var (
+	// ErrUnsupportedField is returned when the prime field of a circom file doesn't match any supported curve
+	ErrUnsupportedField = errors.New("prime field doesn't match the scalar field of a supported curve")
+
+	// ErrUnsupportedCS is returned when exporting a constraint system which is not a rank-1 constraint system
+	ErrUnsupportedCS = errors.New("only rank-1 constraint systems (compiled with backend.GROTH16) can be exported")
+)
+
+// curves supported by gnark, in the order they are matched against a prime field
+var curves = []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BLS24_315, ecc.BW6_633, ecc.BW6_672}
+
+// Term is a term of a linear combination: Coeff * wire[Wire]
+type Term struct {
+	Wire  uint32
+	Coeff big.Int
+}
+
+// LinearCombination is a sum of terms
+type LinearCombination []Term
+
+// Constraint is a rank-1 constraint A * B - C == 0
+type Constraint struct {
+	A, B, C LinearCombination
+}
+
+// R1CS is a rank-1 constraint system, as described by the iden3 .r1cs format
+//
+// Wire 0 is the constant wire ONE, followed by NbPubOut public outputs, NbPubIn public inputs,
+// NbPrvIn private inputs, and the internal wires.
+type R1CS struct {
+	Prime       *big.Int // prime field the constraints are defined over
+	NbWires     uint32
+	NbPubOut    uint32
+	NbPubIn     uint32
+	NbPrvIn     uint32
+	NbLabels    uint64
+	Constraints []Constraint
+	WireToLabel []uint64 // for each wire, the id of the circom signal it was built from
+}
+
+// NbPublic returns the number of public wires, excluding the constant wire ONE
+func (r1cs *R1CS) NbPublic() int {
+	return int(r1cs.NbPubOut + r1cs.NbPubIn)
+}
+
+// CurveID returns the curve whose scalar field matches r1cs.Prime
+func (r1cs *R1CS) CurveID() (ecc.ID, error) {
+	return curveID(r1cs.Prime)
+}
+
+// FromCS returns the circom representation of a rank-1 constraint system compiled with backend.GROTH16
+//
+// gnark public variables are exported as circom public inputs, secret variables as private inputs,
+// and internal variables as internal wires.
+func FromCS(ccs frontend.CompiledConstraintSystem) (*R1CS, error) {
+	var (
+		r1cs   *compiled.R1CS
+		coeffs []big.Int
+	)
+
+	switch _r1cs := ccs.(type) {
+	case *backend_bn254.R1CS:
+		r1cs = &_r1cs.R1CS
+		coeffs = make([]big.Int, len(_r1cs.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			_r1cs.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+	case *backend_bls12377.R1CS:
+		r1cs = &_r1cs.R1CS
+		coeffs = make([]big.Int, len(_r1cs.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			_r1cs.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+	case *backend_bls12381.R1CS:
+		r1cs = &_r1cs.R1CS
+		coeffs = make([]big.Int, len(_r1cs.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			_r1cs.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+	case *backend_bw6761.R1CS:
+		r1cs = &_r1cs.R1CS
+		coeffs = make([]big.Int, len(_r1cs.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			_r1cs.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+	case *backend_bls24315.R1CS:
+		r1cs = &_r1cs.R1CS
+		coeffs = make([]big.Int, len(_r1cs.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			_r1cs.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+	case *backend_bw6633.R1CS:
+		r1cs = &_r1cs.R1CS
+		coeffs = make([]big.Int, len(_r1cs.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			_r1cs.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+	case *backend_bw6672.R1CS:
+		r1cs = &_r1cs.R1CS
+		coeffs = make([]big.Int, len(_r1cs.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			_r1cs.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+	default:
+		return nil, ErrUnsupportedCS
+	}
+
+	prime, err := modulus(ccs.CurveID())
+	if err != nil {
+		return nil, err
+	}
+
+	nbWires := r1cs.NbPublicVariables + r1cs.NbSecretVariables + r1cs.NbInternalVariables
+	res := R1CS{
+		Prime:       prime,
+		NbWires:     uint32(nbWires),
+		NbPubIn:     uint32(r1cs.NbPublicVariables - 1), // - 1 for ONE_WIRE
+		NbPrvIn:     uint32(r1cs.NbSecretVariables),
+		NbLabels:    uint64(nbWires),
+		Constraints: make([]Constraint, len(r1cs.Constraints)),
+		WireToLabel: make([]uint64, nbWires),
+	}
+	for i := 0; i < nbWires; i++ {
+		res.WireToLabel[i] = uint64(i)
+	}
+
+	// gnark wires are already ordered as [ONE_WIRE | public | secret | internal]
+	toLinearCombination := func(l compiled.LinearExpression) LinearCombination {
+		values := make(map[uint32]*big.Int, len(l))
+		for _, t := range l {
+			coeffID, wireID, _ := t.Unpack()
+			v, ok := values[uint32(wireID)]
+			if !ok {
+				v = new(big.Int)
+				values[uint32(wireID)] = v
+			}
+			v.Add(v, &coeffs[coeffID]).Mod(v, res.Prime)
+		}
+		lc := make(LinearCombination, 0, len(values))
+		for wireID, v := range values {
+			if v.Sign() == 0 {
+				continue
+			}
+			lc = append(lc, Term{Wire: wireID})
+			lc[len(lc)-1].Coeff.Set(v)
+		}
+		sort.Slice(lc, func(i, j int) bool { return lc[i].Wire < lc[j].Wire })
+		return lc
+	}
+
+	for i, r1c := range r1cs.Constraints {
+		res.Constraints[i] = Constraint{
+			A: toLinearCombination(r1c.L),
+			B: toLinearCombination(r1c.R),
+			C: toLinearCombination(r1c.O),
+		}
+	}
+
+	return &res, nil
+}
+
+// Compile returns a gnark constraint system equivalent to r1cs, for the curve whose scalar field is r1cs.Prime
+//
+// With backend.GROTH16, each circom constraint is a constraint of the resulting R1CS. With backend.PLONK,
+// the circuit returned by r1cs.Circuit() is compiled.
+func (r1cs *R1CS) Compile(zkpID backend.ID) (frontend.CompiledConstraintSystem, error) {
+	curve, err := r1cs.CurveID()
+	if err != nil {
+		return nil, err
+	}
+	if err := r1cs.check(); err != nil {
+		return nil, err
+	}
+
+	switch zkpID {
+	case backend.GROTH16:
+		return r1cs.toR1CS(curve)
+	case backend.PLONK:
+		return frontend.Compile(curve, backend.PLONK, r1cs.Circuit())
+	default:
+		return nil, fmt.Errorf("unsupported backend %s", zkpID)
+	}
+}
+
+// toR1CS builds the gnark R1CS; all the wires are inputs, so all the constraints are assertions
+func (r1cs *R1CS) toR1CS(curve ecc.ID) (frontend.CompiledConstraintSystem, error) {
+	nbPublic := r1cs.NbPublic() + 1 // + 1 for ONE_WIRE
+
+	res := compiled.R1CS{
+		NbPublicVariables:    nbPublic,
+		NbSecretVariables:    int(r1cs.NbWires) - nbPublic,
+		NbConstraints:        len(r1cs.Constraints),
+		Constraints:          make([]compiled.R1C, len(r1cs.Constraints)),
+		DebugInfo:            make([]compiled.LogEntry, len(r1cs.Constraints)),
+		ConstraintsDebugInfo: make([]int, len(r1cs.Constraints)),
+	}
+
+	// same coefficient table layout than the frontend: 0, 1, 2, -1 and then the other coefficients
+	coeffs := make([]big.Int, 4)
+	coeffs[compiled.CoeffIdOne].SetUint64(1)
+	coeffs[compiled.CoeffIdTwo].SetUint64(2)
+	coeffs[compiled.CoeffIdMinusOne].Sub(r1cs.Prime, &coeffs[compiled.CoeffIdOne])
+	coeffsIDs := make(map[string]int)
+	for i := range coeffs {
+		coeffsIDs[coeffs[i].Text(16)] = i
+	}
+	coeffID := func(b *big.Int) int {
+		key := b.Text(16)
+		if id, ok := coeffsIDs[key]; ok {
+			return id
+		}
+		coeffs = append(coeffs, *new(big.Int).Set(b))
+		coeffsIDs[key] = len(coeffs) - 1
+		return len(coeffs) - 1
+	}
+
+	toLinearExpression := func(lc LinearCombination) compiled.LinearExpression {
+		l := make(compiled.LinearExpression, len(lc))
+		for i := 0; i < len(lc); i++ {
+			visibility := compiled.Secret
+			if int(lc[i].Wire) < nbPublic {
+				visibility = compiled.Public
+			}
+			l[i] = compiled.Pack(int(lc[i].Wire), coeffID(&lc[i].Coeff), visibility)
+		}
+		return l
+	}
+
+	for i, c := range r1cs.Constraints {
+		res.Constraints[i] = compiled.R1C{
+			L: toLinearExpression(c.A),
+			R: toLinearExpression(c.B),
+			O: toLinearExpression(c.C),
+		}
+		res.DebugInfo[i] = compiled.LogEntry{Format: fmt.Sprintf("circom constraint #%d is not satisfied", i)}
+		res.ConstraintsDebugInfo[i] = i
+	}
+
+	switch curve {
+	case ecc.BN254:
+		return backend_bn254.NewR1CS(res, coeffs), nil
+	case ecc.BLS12_377:
+		return backend_bls12377.NewR1CS(res, coeffs), nil
+	case ecc.BLS12_381:
+		return backend_bls12381.NewR1CS(res, coeffs), nil
+	case ecc.BW6_761:
+		return backend_bw6761.NewR1CS(res, coeffs), nil
+	case ecc.BLS24_315:
+		return backend_bls24315.NewR1CS(res, coeffs), nil
+	case ecc.BW6_633:
+		return backend_bw6633.NewR1CS(res, coeffs), nil
+	case ecc.BW6_672:
+		return backend_bw6672.NewR1CS(res, coeffs), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", curve)
+	}
+}
+
+// check ensures the wires referenced in the constraints exist and the coefficients are reduced
+func (r1cs *R1CS) check() error {
+	if uint64(r1cs.NbPubOut)+uint64(r1cs.NbPubIn)+uint64(r1cs.NbPrvIn)+1 > uint64(r1cs.NbWires) { // + 1 for ONE_WIRE
+		return fmt.Errorf("invalid number of wires: %d public outputs, %d public inputs and %d private inputs, but %d wires", r1cs.NbPubOut, r1cs.NbPubIn, r1cs.NbPrvIn, r1cs.NbWires)
+	}
+	checkLC := func(i int, lc LinearCombination) error {
+		for _, t := range lc {
+			if t.Wire >= r1cs.NbWires {
+				return fmt.Errorf("constraint #%d: wire %d out of range (%d wires)", i, t.Wire, r1cs.NbWires)
+			}
+			if t.Coeff.Sign() < 0 || t.Coeff.Cmp(r1cs.Prime) >= 0 {
+				return fmt.Errorf("constraint #%d: coefficient is not reduced modulo the prime field", i)
+			}
+		}
+		return nil
+	}
+	for i, c := range r1cs.Constraints {
+		if err := checkLC(i, c.A); err != nil {
+			return err
+		}
+		if err := checkLC(i, c.B); err != nil {
+			return err
+		}
+		if err := checkLC(i, c.C); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func curveID(prime *big.Int) (ecc.ID, error) {
+	if prime == nil {
+		return ecc.UNKNOWN, ErrUnsupportedField
+	}
+	for _, curve := range curves {
+		q, err := modulus(curve)
+		if err != nil {
+			return ecc.UNKNOWN, err
+		}
+		if q.Cmp(prime) == 0 {
+			return curve, nil
+		}
+	}
+	return ecc.UNKNOWN, ErrUnsupportedField
+}
+
+// modulus returns the modulus of the scalar field of curveID
+func modulus(curveID ecc.ID) (*big.Int, error) {
+	switch curveID {
+	case ecc.BN254:
+		return frbn254.Modulus(), nil
+	case ecc.BLS12_377:
+		return frbls12377.Modulus(), nil
+	case ecc.BLS12_381:
+		return frbls12381.Modulus(), nil
+	case ecc.BW6_761:
+		return frbw6761.Modulus(), nil
+	case ecc.BLS24_315:
+		return frbls24315.Modulus(), nil
+	case ecc.BW6_672:
+		return frbw6672.Modulus(), nil
+	case ecc.BW6_633:
+		return frbw6633.Modulus(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", curveID)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circom
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	x3 := cs.Mul(circuit.X, circuit.X, circuit.X)
+	cs.AssertIsEqual(circuit.Y, cs.Add(x3, circuit.X, 5))
+	return nil
+}
+
+func term(wire uint32, coeff int64) Term {
+	t := Term{Wire: wire}
+	t.Coeff.SetInt64(coeff)
+	return t
+}
+
+// sampleR1CS returns the R1CS of a circom circuit with one public output (out), two private inputs (x, y)
+// and two internal signals (t, w):
+//
+// 	t <== x * y
+// 	out <== (t + 5) * x
+// 	w <== 3 * y
+func sampleR1CS() *R1CS {
+	return &R1CS{
+		Prime:    fr.Modulus(),
+		NbWires:  6,
+		NbPubOut: 1,
+		NbPrvIn:  2,
+		NbLabels: 6,
+		Constraints: []Constraint{
+			{A: LinearCombination{term(2, 1)}, B: LinearCombination{term(3, 1)}, C: LinearCombination{term(4, 1)}},
+			{A: LinearCombination{term(0, 5), term(4, 1)}, B: LinearCombination{term(2, 1)}, C: LinearCombination{term(1, 1)}},
+			{A: LinearCombination{term(0, 3)}, B: LinearCombination{term(3, 1)}, C: LinearCombination{term(5, 1)}},
+		},
+		WireToLabel: []uint64{0, 1, 2, 3, 4, 5},
+	}
+}
+
+func sampleWitness(values ...int64) *Witness {
+	wtns := &Witness{Prime: fr.Modulus(), Values: make([]big.Int, len(values))}
+	for i, v := range values {
+		wtns.Values[i].SetInt64(v)
+	}
+	return wtns
+}
+
+func TestR1CSSerialization(t *testing.T) {
+	assert := require.New(t)
+
+	r1cs := sampleR1CS()
+
+	var buf bytes.Buffer
+	_, err := r1cs.WriteTo(&buf)
+	assert.NoError(err)
+	encoded := buf.Bytes()
+	assert.Equal(r1csMagic, string(encoded[:4]))
+
+	decoded, err := ReadR1CS(bytes.NewReader(encoded))
+	assert.NoError(err)
+
+	var reencoded bytes.Buffer
+	_, err = decoded.WriteTo(&reencoded)
+	assert.NoError(err)
+	assert.Equal(encoded, reencoded.Bytes())
+
+	// truncated files are rejected
+	_, err = ReadR1CS(bytes.NewReader(encoded[:len(encoded)-1]))
+	assert.Error(err)
+
+	r1cs.Prime = big.NewInt(101)
+	_, err = r1cs.WriteTo(&buf)
+	assert.ErrorIs(err, ErrUnsupportedField)
+}
+
+func TestWitnessSerialization(t *testing.T) {
+	assert := require.New(t)
+
+	wtns := sampleWitness(1, 51, 3, 4, 12, 12)
+
+	var buf bytes.Buffer
+	_, err := wtns.WriteTo(&buf)
+	assert.NoError(err)
+
+	decoded, err := ReadWitness(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(len(wtns.Values), len(decoded.Values))
+	for i := range wtns.Values {
+		assert.Equal(0, wtns.Values[i].Cmp(&decoded.Values[i]))
+	}
+
+	// a .r1cs file is not a witness
+	buf.Reset()
+	_, err = sampleR1CS().WriteTo(&buf)
+	assert.NoError(err)
+	_, err = ReadWitness(&buf)
+	assert.Error(err)
+}
+
+func TestImportGroth16(t *testing.T) {
+	assert := require.New(t)
+	groth16Assert := groth16.NewAssert(t)
+
+	r1cs := sampleR1CS()
+	ccs, err := r1cs.Compile(backend.GROTH16)
+	assert.NoError(err)
+	assert.Equal(len(r1cs.Constraints), ccs.GetNbConstraints())
+
+	good, err := r1cs.Assign(sampleWitness(1, 51, 3, 4, 12, 12))
+	assert.NoError(err)
+	groth16Assert.ProverSucceeded(ccs, good)
+
+	bad, err := r1cs.Assign(sampleWitness(1, 52, 3, 4, 12, 12))
+	assert.NoError(err)
+	groth16Assert.ProverFailed(ccs, bad)
+
+	_, err = r1cs.Assign(sampleWitness(1, 51, 3, 4, 12))
+	assert.Error(err)
+
+	_, err = r1cs.Compile(backend.UNKNOWN)
+	assert.Error(err)
+
+	// unknown curves are reported, not panicked on
+	_, err = r1cs.toR1CS(ecc.UNKNOWN)
+	assert.Error(err)
+	_, err = modulus(ecc.UNKNOWN)
+	assert.Error(err)
+}
+
+func TestImportPlonk(t *testing.T) {
+	assert := require.New(t)
+
+	r1cs := sampleR1CS()
+	ccs, err := r1cs.Compile(backend.PLONK)
+	assert.NoError(err)
+
+	srs, err := plonk.NewSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+
+	witness, err := r1cs.Assign(sampleWitness(1, 51, 3, 4, 12, 12))
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, witness)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, witness))
+
+	bad, err := r1cs.Assign(sampleWitness(1, 51, 3, 4, 13, 12))
+	assert.NoError(err)
+	_, err = plonk.Prove(ccs, pk, bad)
+	assert.Error(err)
+}
+
+func TestExport(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit cubicCircuit
+	ccs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	assert.NoError(err)
+
+	r1cs, err := FromCS(ccs)
+	assert.NoError(err)
+	assert.Equal(uint32(1), r1cs.NbPubIn)
+	assert.Equal(uint32(1), r1cs.NbPrvIn)
+	assert.Equal(ccs.GetNbConstraints(), len(r1cs.Constraints))
+
+	var buf bytes.Buffer
+	_, err = r1cs.WriteTo(&buf)
+	assert.NoError(err)
+
+	decoded, err := ReadR1CS(&buf)
+	assert.NoError(err)
+
+	// the imported circuit can be proven with the wire values of the original circuit: x = 3, y = 35
+	// and internal wires x², x³
+	imported, err := decoded.Compile(backend.GROTH16)
+	assert.NoError(err)
+	witness, err := decoded.Assign(sampleWitness(1, 35, 3, 9, 27))
+	assert.NoError(err)
+	groth16.NewAssert(t).ProverSucceeded(imported, witness)
+
+	// sparse constraint systems can't be exported
+	sparse, err := frontend.Compile(ecc.BN254, backend.PLONK, &circuit)
+	assert.NoError(err)
+	_, err = FromCS(sparse)
+	assert.ErrorIs(err, ErrUnsupportedCS)
+}
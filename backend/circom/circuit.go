@@ -0,0 +1,125 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circom
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Circuit is a gnark circuit equivalent to a circom R1CS
+//
+// Public holds the circom public outputs and inputs, and Secret all the other wires (private inputs
+// and internal signals), except the constant wire ONE.
+type Circuit struct {
+	Public []frontend.Variable `gnark:",public"`
+	Secret []frontend.Variable
+	r1cs   *R1CS
+}
+
+// Circuit returns a gnark circuit whose constraints are the constraints of r1cs
+//
+// It can be used as a template for the witness (see Assign), or compiled with frontend.Compile.
+func (r1cs *R1CS) Circuit() *Circuit {
+	nbPublic := r1cs.NbPublic()
+	return &Circuit{
+		Public: make([]frontend.Variable, nbPublic),
+		Secret: make([]frontend.Variable, int(r1cs.NbWires)-nbPublic-1),
+		r1cs:   r1cs,
+	}
+}
+
+// Assign returns a witness for r1cs, assigned from the wire values computed by circom
+func (r1cs *R1CS) Assign(wtns *Witness) (*Circuit, error) {
+	if wtns.Prime == nil || wtns.Prime.Cmp(r1cs.Prime) != 0 {
+		return nil, fmt.Errorf("witness and constraint system are not defined over the same prime field")
+	}
+	if len(wtns.Values) != int(r1cs.NbWires) {
+		return nil, fmt.Errorf("invalid witness size, got %d, expected %d", len(wtns.Values), r1cs.NbWires)
+	}
+	if !wtns.Values[0].IsUint64() || wtns.Values[0].Uint64() != 1 {
+		return nil, fmt.Errorf("invalid witness: wire 0 must be 1")
+	}
+
+	witness := r1cs.Circuit()
+	for i := 0; i < len(witness.Public); i++ {
+		witness.Public[i].Assign(&wtns.Values[1+i])
+	}
+	for i := 0; i < len(witness.Secret); i++ {
+		witness.Secret[i].Assign(&wtns.Values[1+len(witness.Public)+i])
+	}
+	return witness, nil
+}
+
+// Define declares the circuit constraints
+//
+// For each circom constraint A * B == C, if A or B is a constant, A * B is a linear expression and
+// a single assertion is recorded; otherwise the product is computed (one constraint) and asserted equal to C.
+func (circuit *Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	if circuit.r1cs == nil {
+		return fmt.Errorf("circuit was not built from a circom R1CS (see R1CS.Circuit)")
+	}
+
+	wire := func(id uint32) frontend.Variable {
+		if int(id) <= len(circuit.Public) {
+			return circuit.Public[id-1]
+		}
+		return circuit.Secret[int(id)-len(circuit.Public)-1]
+	}
+
+	// constant returns the value of lc and true if lc only references the wire ONE
+	constant := func(lc LinearCombination) (big.Int, bool) {
+		var res big.Int
+		for i := 0; i < len(lc); i++ {
+			if lc[i].Wire != 0 {
+				return res, false
+			}
+			res.Add(&res, &lc[i].Coeff)
+		}
+		return res, true
+	}
+
+	linearExpression := func(lc LinearCombination) frontend.Variable {
+		terms := make([]interface{}, 0, len(lc)+2)
+		for i := 0; i < len(lc); i++ {
+			if lc[i].Wire == 0 {
+				terms = append(terms, lc[i].Coeff)
+			} else {
+				terms = append(terms, cs.Mul(lc[i].Coeff, wire(lc[i].Wire)))
+			}
+		}
+		for len(terms) < 2 {
+			terms = append(terms, 0)
+		}
+		return cs.Add(terms[0], terms[1], terms[2:]...)
+	}
+
+	for _, c := range circuit.r1cs.Constraints {
+		var ab frontend.Variable
+		if a, ok := constant(c.A); ok {
+			ab = cs.Mul(a, linearExpression(c.B))
+		} else if b, ok := constant(c.B); ok {
+			ab = cs.Mul(b, linearExpression(c.A))
+		} else {
+			ab = cs.Mul(linearExpression(c.A), linearExpression(c.B))
+		}
+		cs.AssertIsEqual(ab, linearExpression(c.C))
+	}
+
+	return nil
+}
@@ -0,0 +1,332 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// iden3 binary file format
+//
+// 	[magic (4 bytes) | version (uint32) | nbSections (uint32) | sections...]
+//
+// where each section is
+//
+// 	[type (uint32) | size (uint64) | content (size bytes)]
+//
+// integers and field elements are encoded in little endian; field elements are in regular (non-Montgomery) form,
+// on n8 bytes.
+const (
+	r1csMagic   = "r1cs"
+	r1csVersion = 1
+
+	r1csSectionHeader      = 1
+	r1csSectionConstraints = 2
+	r1csSectionWireToLabel = 3
+	r1csSectionCustomGates = 4
+)
+
+// ReadR1CS decodes a constraint system in the iden3 .r1cs binary format
+func ReadR1CS(r io.Reader) (*R1CS, error) {
+	sections, err := readSections(r, r1csMagic, r1csVersion)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sections[r1csSectionCustomGates]; ok {
+		return nil, errors.New("custom gates are not supported")
+	}
+
+	header, ok := sections[r1csSectionHeader]
+	if !ok {
+		return nil, errors.New("missing header section")
+	}
+	var res R1CS
+	d := decoder{r: header}
+	n8 := d.uint32()
+	res.Prime = d.element(n8)
+	res.NbWires = d.uint32()
+	res.NbPubOut = d.uint32()
+	res.NbPubIn = d.uint32()
+	res.NbPrvIn = d.uint32()
+	res.NbLabels = d.uint64()
+	nbConstraints := d.uint32()
+	if d.err != nil {
+		return nil, fmt.Errorf("header section: %w", d.err)
+	}
+	if _, err := res.CurveID(); err != nil {
+		return nil, err
+	}
+
+	constraints, ok := sections[r1csSectionConstraints]
+	if !ok {
+		return nil, errors.New("missing constraints section")
+	}
+	d = decoder{r: constraints}
+	readLC := func() LinearCombination {
+		nbTerms := d.uint32()
+		if d.err != nil || uint64(nbTerms) > uint64(constraints.Len()) {
+			d.fail(io.ErrUnexpectedEOF)
+			return nil
+		}
+		lc := make(LinearCombination, nbTerms)
+		for i := range lc {
+			lc[i].Wire = d.uint32()
+			if v := d.element(n8); v != nil {
+				lc[i].Coeff.Set(v)
+			}
+		}
+		return lc
+	}
+	if uint64(nbConstraints) > uint64(constraints.Len()) {
+		return nil, fmt.Errorf("constraints section: %w", io.ErrUnexpectedEOF)
+	}
+	res.Constraints = make([]Constraint, nbConstraints)
+	for i := range res.Constraints {
+		res.Constraints[i].A = readLC()
+		res.Constraints[i].B = readLC()
+		res.Constraints[i].C = readLC()
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("constraints section: %w", d.err)
+	}
+
+	if wireToLabel, ok := sections[r1csSectionWireToLabel]; ok {
+		if uint64(wireToLabel.Len()) != 8*uint64(res.NbWires) {
+			return nil, errors.New("wire to label section: invalid size")
+		}
+		d = decoder{r: wireToLabel}
+		res.WireToLabel = make([]uint64, res.NbWires)
+		for i := range res.WireToLabel {
+			res.WireToLabel[i] = d.uint64()
+		}
+	}
+
+	if err := res.check(); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// WriteTo encodes r1cs in the iden3 .r1cs binary format
+func (r1cs *R1CS) WriteTo(w io.Writer) (int64, error) {
+	if _, err := r1cs.CurveID(); err != nil {
+		return 0, err
+	}
+	n8 := fieldSize(r1cs.Prime)
+
+	var header bytes.Buffer
+	e := encoder{w: &header}
+	e.uint32(n8)
+	e.element(r1cs.Prime, n8)
+	e.uint32(r1cs.NbWires)
+	e.uint32(r1cs.NbPubOut)
+	e.uint32(r1cs.NbPubIn)
+	e.uint32(r1cs.NbPrvIn)
+	e.uint64(r1cs.NbLabels)
+	e.uint32(uint32(len(r1cs.Constraints)))
+
+	var constraints bytes.Buffer
+	e = encoder{w: &constraints}
+	writeLC := func(lc LinearCombination) {
+		e.uint32(uint32(len(lc)))
+		for i := range lc {
+			e.uint32(lc[i].Wire)
+			e.element(&lc[i].Coeff, n8)
+		}
+	}
+	for _, c := range r1cs.Constraints {
+		writeLC(c.A)
+		writeLC(c.B)
+		writeLC(c.C)
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	sections := [][]byte{header.Bytes(), constraints.Bytes()}
+	if len(r1cs.WireToLabel) != 0 {
+		var wireToLabel bytes.Buffer
+		e = encoder{w: &wireToLabel}
+		for _, label := range r1cs.WireToLabel {
+			e.uint64(label)
+		}
+		sections = append(sections, wireToLabel.Bytes())
+	}
+
+	return writeSections(w, r1csMagic, r1csVersion, sections)
+}
+
+// readSections reads an iden3 binary file and returns its sections, indexed by type
+func readSections(r io.Reader, magic string, version uint32) (map[uint32]*bytes.Reader, error) {
+	d := decoder{r: r}
+	var m [4]byte
+	d.read(m[:])
+	fileVersion := d.uint32()
+	nbSections := d.uint32()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if string(m[:]) != magic {
+		return nil, fmt.Errorf("invalid magic number, expected %q", magic)
+	}
+	if fileVersion != version {
+		return nil, fmt.Errorf("unsupported version %d, expected %d", fileVersion, version)
+	}
+
+	sections := make(map[uint32]*bytes.Reader)
+	for i := uint32(0); i < nbSections; i++ {
+		sectionType := d.uint32()
+		size := d.uint64()
+		if d.err != nil {
+			return nil, d.err
+		}
+		if _, ok := sections[sectionType]; ok {
+			return nil, fmt.Errorf("duplicate section %d", sectionType)
+		}
+		var content bytes.Buffer
+		if _, err := io.CopyN(&content, r, int64(size)); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		sections[sectionType] = bytes.NewReader(content.Bytes())
+	}
+	return sections, nil
+}
+
+// writeSections writes an iden3 binary file; sections types are 1, 2, ... in order
+func writeSections(w io.Writer, magic string, version uint32, sections [][]byte) (int64, error) {
+	var buf bytes.Buffer
+	e := encoder{w: &buf}
+	e.write([]byte(magic))
+	e.uint32(version)
+	e.uint32(uint32(len(sections)))
+	for i, section := range sections {
+		e.uint32(uint32(i + 1))
+		e.uint64(uint64(len(section)))
+		e.write(section)
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+	return buf.WriteTo(w)
+}
+
+// fieldSize returns the number of bytes used to encode an element of the prime field (a multiple of 8)
+func fieldSize(prime *big.Int) uint32 {
+	return uint32((prime.BitLen()+63)/64) * 8
+}
+
+// decoder reads little endian values and keeps the first error
+type decoder struct {
+	r   io.Reader
+	err error
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *decoder) read(buf []byte) {
+	if d.err != nil {
+		return
+	}
+	if _, err := io.ReadFull(d.r, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		d.fail(err)
+	}
+}
+
+func (d *decoder) uint32() uint32 {
+	var buf [4]byte
+	d.read(buf[:])
+	return binary.LittleEndian.Uint32(buf[:])
+}
+
+func (d *decoder) uint64() uint64 {
+	var buf [8]byte
+	d.read(buf[:])
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// element reads a little endian field element on n8 bytes
+func (d *decoder) element(n8 uint32) *big.Int {
+	if n8 == 0 || n8 > 128 || n8%8 != 0 {
+		d.fail(fmt.Errorf("invalid field element size %d", n8))
+	}
+	if d.err != nil {
+		return nil
+	}
+	buf := make([]byte, n8)
+	d.read(buf)
+	reverse(buf)
+	return new(big.Int).SetBytes(buf)
+}
+
+// encoder writes little endian values and keeps the first error
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *encoder) write(buf []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(buf)
+}
+
+func (e *encoder) uint32(v uint32) {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	e.write(buf[:])
+}
+
+func (e *encoder) uint64(v uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	e.write(buf[:])
+}
+
+// element writes a little endian field element on n8 bytes
+func (e *encoder) element(v *big.Int, n8 uint32) {
+	if v.Sign() < 0 || uint32(len(v.Bytes())) > n8 {
+		if e.err == nil {
+			e.err = fmt.Errorf("%s doesn't fit on %d bytes", v.String(), n8)
+		}
+		return
+	}
+	buf := make([]byte, n8)
+	v.FillBytes(buf)
+	reverse(buf)
+	e.write(buf)
+}
+
+func reverse(buf []byte) {
+	for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+const (
+	wtnsMagic   = "wtns"
+	wtnsVersion = 2
+
+	wtnsSectionHeader = 1
+	wtnsSectionValues = 2
+)
+
+// Witness holds the values of all the wires of a circom circuit, as computed by the circom witness generator
+type Witness struct {
+	Prime  *big.Int  // prime field the values are defined over
+	Values []big.Int // Values[0] is the constant wire ONE
+}
+
+// ReadWitness decodes a witness in the iden3 .wtns binary format
+func ReadWitness(r io.Reader) (*Witness, error) {
+	sections, err := readSections(r, wtnsMagic, wtnsVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	header, ok := sections[wtnsSectionHeader]
+	if !ok {
+		return nil, errors.New("missing header section")
+	}
+	var res Witness
+	d := decoder{r: header}
+	n8 := d.uint32()
+	res.Prime = d.element(n8)
+	nbValues := d.uint32()
+	if d.err != nil {
+		return nil, fmt.Errorf("header section: %w", d.err)
+	}
+	if _, err := curveID(res.Prime); err != nil {
+		return nil, err
+	}
+
+	values, ok := sections[wtnsSectionValues]
+	if !ok {
+		return nil, errors.New("missing values section")
+	}
+	if uint64(values.Len()) != uint64(nbValues)*uint64(n8) {
+		return nil, errors.New("values section: invalid size")
+	}
+	d = decoder{r: values}
+	res.Values = make([]big.Int, nbValues)
+	for i := range res.Values {
+		if v := d.element(n8); v != nil {
+			res.Values[i].Set(v)
+		}
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("values section: %w", d.err)
+	}
+
+	return &res, nil
+}
+
+// WriteTo encodes the witness in the iden3 .wtns binary format
+func (wtns *Witness) WriteTo(w io.Writer) (int64, error) {
+	if _, err := curveID(wtns.Prime); err != nil {
+		return 0, err
+	}
+	n8 := fieldSize(wtns.Prime)
+
+	var header bytes.Buffer
+	e := encoder{w: &header}
+	e.uint32(n8)
+	e.element(wtns.Prime, n8)
+	e.uint32(uint32(len(wtns.Values)))
+
+	var values bytes.Buffer
+	e = encoder{w: &values, err: e.err}
+	for i := range wtns.Values {
+		e.element(&wtns.Values[i], n8)
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	return writeSections(w, wtnsMagic, wtnsVersion, [][]byte{header.Bytes(), values.Bytes()})
+}
@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 	backend_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/cs"
 	witness_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/witness"
@@ -144,44 +145,52 @@ func (assert *Assert) SolvingFailed(r1cs frontend.CompiledConstraintSystem, witn
 
 // IsSolved attempts to solve the constraint system with provided witness
 // returns nil if it succeeds, error otherwise.
-func IsSolved(r1cs frontend.CompiledConstraintSystem, witness frontend.Circuit) error {
+//
+// Only the solver options of opts are used (see backend.WithLoggerOutput, backend.WithNbTasks
+// and backend.WithDiagnostics).
+func IsSolved(r1cs frontend.CompiledConstraintSystem, witness frontend.Circuit, opts ...backend.ProverOption) error {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return err
+	}
+
 	switch _r1cs := r1cs.(type) {
 	case *backend_bls12377.R1CS:
 		w := witness_bls12377.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return _r1cs.IsSolved(w)
+		return _r1cs.IsSolved(w, opt)
 	case *backend_bls12381.R1CS:
 		w := witness_bls12381.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return _r1cs.IsSolved(w)
+		return _r1cs.IsSolved(w, opt)
 	case *backend_bn254.R1CS:
 		w := witness_bn254.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return _r1cs.IsSolved(w)
+		return _r1cs.IsSolved(w, opt)
 	case *backend_bw6761.R1CS:
 		w := witness_bw6761.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return _r1cs.IsSolved(w)
+		return _r1cs.IsSolved(w, opt)
 	case *backend_bls24315.R1CS:
 		w := witness_bls24315.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return _r1cs.IsSolved(w)
+		return _r1cs.IsSolved(w, opt)
 	case *backend_bw6672.R1CS:
 		w := witness_bw6672.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return _r1cs.IsSolved(w)
+		return _r1cs.IsSolved(w, opt)
 
 
 	case *backend_bw6633.R1CS:
@@ -189,7 +198,7 @@ func IsSolved(r1cs frontend.CompiledConstraintSystem, witness frontend.Circuit)
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return _r1cs.IsSolved(w)
+		return _r1cs.IsSolved(w, opt)
 
 	default:
 		panic("unrecognized R1CS curve type")
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 	backend_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/cs"
 	witness_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/witness"
@@ -20,19 +21,19 @@ func Fuzz(data []byte) int {
 		ccs, nbAssertions := frontend.CsFuzzed(data, curveID)
 		_, s, p := ccs.GetNbVariables()
 		wSize := s + p - 1
-		ccs.SetLoggerOutput(nil)
+		opt, _ := backend.NewProverConfig(backend.WithLoggerOutput(nil))
 		switch _r1cs := ccs.(type) {
 		case *backend_bls12381.R1CS:
 			w := make(witness_bls12381.Witness, wSize)
 			// make w random
-			err := _r1cs.IsSolved(w)
+			err := _r1cs.IsSolved(w, opt)
 			if nbAssertions == 0 && err != nil && !strings.Contains(err.Error(), "couldn't solve computational constraint") {
 				panic("no assertions, yet solving resulted in an error.")
 			}
 		case *backend_bn254.R1CS:
 			w := make(witness_bn254.Witness, wSize)
 			// make w random
-			err := _r1cs.IsSolved(w)
+			err := _r1cs.IsSolved(w, opt)
 			if nbAssertions == 0 && err != nil && !strings.Contains(err.Error(), "couldn't solve computational constraint") {
 				panic("no assertions, yet solving resulted in an error.")
 			}
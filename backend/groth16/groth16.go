@@ -20,10 +20,12 @@
 package groth16
 
 import (
+	"context"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 	backend_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/cs"
 	backend_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/cs"
@@ -62,8 +64,18 @@ type groth16Object interface {
 // Proof represents a Groth16 proof generated by groth16.Prove
 //
 // it's underlying implementation is curve specific (see gnark/internal/backend)
+//
+// ExportSnarkJS and ImportSnarkJS are implemented for BN254 and BLS12-381 and will return an error with other curves
 type Proof interface {
 	groth16Object
+
+	// ExportSnarkJS writes the Proof in the snarkjs proof.json format
+	// this will return an error if not supported on the CurveID()
+	ExportSnarkJS(w io.Writer) error
+
+	// ImportSnarkJS reads a Proof in the snarkjs proof.json format
+	// this will return an error if not supported on the CurveID()
+	ImportSnarkJS(r io.Reader) error
 }
 
 // ProvingKey represents a Groth16 ProvingKey
@@ -86,6 +98,8 @@ type ProvingKey interface {
 // it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
 //
 // ExportSolidity is implemented for BN254 and will return an error with other curves
+//
+// ExportSnarkJS and ImportSnarkJS are implemented for BN254 and BLS12-381 and will return an error with other curves
 type VerifyingKey interface {
 	groth16Object
 
@@ -102,6 +116,14 @@ type VerifyingKey interface {
 	// this will return an error if not supported on the CurveID()
 	ExportSolidity(w io.Writer) error
 
+	// ExportSnarkJS writes the VerifyingKey in the snarkjs verification_key.json format
+	// this will return an error if not supported on the CurveID()
+	ExportSnarkJS(w io.Writer) error
+
+	// ImportSnarkJS reads a VerifyingKey in the snarkjs verification_key.json format
+	// this will return an error if not supported on the CurveID()
+	ImportSnarkJS(r io.Reader) error
+
 	IsDifferent(interface{}) bool
 }
 
@@ -214,13 +236,24 @@ func ReadAndVerify(proof Proof, vk VerifyingKey, publicWitness io.Reader) error
 
 // Prove runs the groth16.Prove algorithm.
 //
-// If force flag is set, executes all the prover computations, even if the witness is invalid
+// The prover is configured with opts (see backend.ProverOption). In particular, if backend.IgnoreSolverError()
+// is set, it executes all the prover computations, even if the witness is invalid
 // (in which case it will produce an invalid proof)
-func Prove(r1cs frontend.CompiledConstraintSystem, pk ProvingKey, witness frontend.Circuit, force ...bool) (Proof, error) {
+func Prove(r1cs frontend.CompiledConstraintSystem, pk ProvingKey, witness frontend.Circuit, opts ...backend.ProverOption) (Proof, error) {
+	return ProveContext(context.Background(), r1cs, pk, witness, opts...)
+}
+
+// ProveContext behaves like Prove, but stops the computation and returns ctx.Err() if ctx is done
+// before the proof is computed. ctx is checked by the solver and between the FFTs and multi-exponentiations;
+// one which has already started runs to completion before ProveContext returns.
+//
+// If a backend.WithProgress option is set, its function is called at the end of each phase of the computation
+// (backend.PhaseSolve, backend.PhaseH, backend.PhaseMSMA, backend.PhaseMSMB and backend.PhaseMSMK).
+func ProveContext(ctx context.Context, r1cs frontend.CompiledConstraintSystem, pk ProvingKey, witness frontend.Circuit, opts ...backend.ProverOption) (Proof, error) {
 
-	_force := false
-	if len(force) > 0 {
-		_force = force[0]
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
 	}
 
 	switch _r1cs := r1cs.(type) {
@@ -229,44 +262,44 @@ func Prove(r1cs frontend.CompiledConstraintSystem, pk ProvingKey, witness fronte
 		if err := w.FromFullAssignment(witness); err != nil {
 			return nil, err
 		}
-		return groth16_bls12377.Prove(_r1cs, pk.(*groth16_bls12377.ProvingKey), w, _force)
+		return groth16_bls12377.ProveContext(ctx, _r1cs, pk.(*groth16_bls12377.ProvingKey), w, opt)
 	case *backend_bls12381.R1CS:
 		w := witness_bls12381.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return nil, err
 		}
-		return groth16_bls12381.Prove(_r1cs, pk.(*groth16_bls12381.ProvingKey), w, _force)
+		return groth16_bls12381.ProveContext(ctx, _r1cs, pk.(*groth16_bls12381.ProvingKey), w, opt)
 	case *backend_bn254.R1CS:
 		w := witness_bn254.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return nil, err
 		}
-		return groth16_bn254.Prove(_r1cs, pk.(*groth16_bn254.ProvingKey), w, _force)
+		return groth16_bn254.ProveContext(ctx, _r1cs, pk.(*groth16_bn254.ProvingKey), w, opt)
 	case *backend_bw6761.R1CS:
 		w := witness_bw6761.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return nil, err
 		}
-		return groth16_bw6761.Prove(_r1cs, pk.(*groth16_bw6761.ProvingKey), w, _force)
+		return groth16_bw6761.ProveContext(ctx, _r1cs, pk.(*groth16_bw6761.ProvingKey), w, opt)
 	case *backend_bls24315.R1CS:
 		w := witness_bls24315.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return nil, err
 		}
-		return groth16_bls24315.Prove(_r1cs, pk.(*groth16_bls24315.ProvingKey), w, _force)
+		return groth16_bls24315.ProveContext(ctx, _r1cs, pk.(*groth16_bls24315.ProvingKey), w, opt)
 	case *backend_bw6672.R1CS:
 		w := witness_bw6672.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return nil, err
 		}
-		return groth16_bw6672.Prove(_r1cs, pk.(*groth16_bw6672.ProvingKey), w, _force)
+		return groth16_bw6672.ProveContext(ctx, _r1cs, pk.(*groth16_bw6672.ProvingKey), w, opt)
 
 	case *backend_bw6633.R1CS:
 		w := witness_bw6633.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return nil, err
 		}
-		return groth16_bw6633.Prove(_r1cs, pk.(*groth16_bw6633.ProvingKey), w, _force)
+		return groth16_bw6633.ProveContext(ctx, _r1cs, pk.(*groth16_bw6633.ProvingKey), w, opt)
 	default:
 		panic("unrecognized R1CS curve type")
 	}
@@ -275,10 +308,10 @@ func Prove(r1cs frontend.CompiledConstraintSystem, pk ProvingKey, witness fronte
 // ReadAndProve behaves like Prove, , except witness is read from a io.Reader
 // witness must be encoded following the binary serialization protocol described in
 // gnark/backend/witness package
-func ReadAndProve(r1cs frontend.CompiledConstraintSystem, pk ProvingKey, witness io.Reader, force ...bool) (Proof, error) {
-	_force := false
-	if len(force) > 0 {
-		_force = force[0]
+func ReadAndProve(r1cs frontend.CompiledConstraintSystem, pk ProvingKey, witness io.Reader, opts ...backend.ProverOption) (Proof, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
 	}
 
 	_, nbSecret, nbPublic := r1cs.GetNbVariables()
@@ -290,44 +323,44 @@ func ReadAndProve(r1cs frontend.CompiledConstraintSystem, pk ProvingKey, witness
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		return groth16_bls12377.Prove(_r1cs, pk.(*groth16_bls12377.ProvingKey), w, _force)
+		return groth16_bls12377.Prove(_r1cs, pk.(*groth16_bls12377.ProvingKey), w, opt)
 	case *backend_bls12381.R1CS:
 		w := witness_bls12381.Witness{}
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		return groth16_bls12381.Prove(_r1cs, pk.(*groth16_bls12381.ProvingKey), w, _force)
+		return groth16_bls12381.Prove(_r1cs, pk.(*groth16_bls12381.ProvingKey), w, opt)
 	case *backend_bn254.R1CS:
 		w := witness_bn254.Witness{}
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		return groth16_bn254.Prove(_r1cs, pk.(*groth16_bn254.ProvingKey), w, _force)
+		return groth16_bn254.Prove(_r1cs, pk.(*groth16_bn254.ProvingKey), w, opt)
 	case *backend_bw6761.R1CS:
 		w := witness_bw6761.Witness{}
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		return groth16_bw6761.Prove(_r1cs, pk.(*groth16_bw6761.ProvingKey), w, _force)
+		return groth16_bw6761.Prove(_r1cs, pk.(*groth16_bw6761.ProvingKey), w, opt)
 	case *backend_bls24315.R1CS:
 		w := witness_bls24315.Witness{}
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		return groth16_bls24315.Prove(_r1cs, pk.(*groth16_bls24315.ProvingKey), w, _force)
+		return groth16_bls24315.Prove(_r1cs, pk.(*groth16_bls24315.ProvingKey), w, opt)
 	case *backend_bw6672.R1CS:
 		w := witness_bw6672.Witness{}
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		return groth16_bw6672.Prove(_r1cs, pk.(*groth16_bw6672.ProvingKey), w, _force)
+		return groth16_bw6672.Prove(_r1cs, pk.(*groth16_bw6672.ProvingKey), w, opt)
 
 	case *backend_bw6633.R1CS:
 		w := witness_bw6633.Witness{}
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		return groth16_bw6633.Prove(_r1cs, pk.(*groth16_bw6633.ProvingKey), w, _force)
+		return groth16_bw6633.Prove(_r1cs, pk.(*groth16_bw6633.ProvingKey), w, opt)
 	default:
 		panic("unrecognized R1CS curve type")
 	}
@@ -341,48 +374,56 @@ func ReadAndProve(r1cs frontend.CompiledConstraintSystem, pk ProvingKey, witness
 //
 // Two main solutions to this deployment issues are: running the Setup through a MPC (multi party computation)
 // or using a ZKP backend like PLONK where the per-circuit Setup is deterministic.
-func Setup(r1cs frontend.CompiledConstraintSystem) (ProvingKey, VerifyingKey, error) {
+//
+// The randomness is read from crypto/rand.Reader, unless a backend.WithSetupRandomSource option is set
+// (for example, to generate reproducible keys in tests).
+func Setup(r1cs frontend.CompiledConstraintSystem, opts ...backend.SetupOption) (ProvingKey, VerifyingKey, error) {
+
+	opt, err := backend.NewSetupConfig(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	switch _r1cs := r1cs.(type) {
 	case *backend_bls12377.R1CS:
 		var pk groth16_bls12377.ProvingKey
 		var vk groth16_bls12377.VerifyingKey
-		if err := groth16_bls12377.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bls12377.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
 	case *backend_bls12381.R1CS:
 		var pk groth16_bls12381.ProvingKey
 		var vk groth16_bls12381.VerifyingKey
-		if err := groth16_bls12381.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bls12381.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
 	case *backend_bn254.R1CS:
 		var pk groth16_bn254.ProvingKey
 		var vk groth16_bn254.VerifyingKey
-		if err := groth16_bn254.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bn254.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
 	case *backend_bw6761.R1CS:
 		var pk groth16_bw6761.ProvingKey
 		var vk groth16_bw6761.VerifyingKey
-		if err := groth16_bw6761.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bw6761.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
 	case *backend_bls24315.R1CS:
 		var pk groth16_bls24315.ProvingKey
 		var vk groth16_bls24315.VerifyingKey
-		if err := groth16_bls24315.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bls24315.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
 	case *backend_bw6672.R1CS:
 		var pk groth16_bw6672.ProvingKey
 		var vk groth16_bw6672.VerifyingKey
-		if err := groth16_bw6672.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bw6672.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
@@ -390,7 +431,7 @@ func Setup(r1cs frontend.CompiledConstraintSystem) (ProvingKey, VerifyingKey, er
 	case *backend_bw6633.R1CS:
 		var pk groth16_bw6633.ProvingKey
 		var vk groth16_bw6633.VerifyingKey
-		if err := groth16_bw6633.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bw6633.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
@@ -0,0 +1,277 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+type proveContextCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *proveContextCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	x := circuit.X
+	for i := 0; i < 100; i++ {
+		x = cs.Mul(x, x)
+	}
+	cs.AssertIsEqual(circuit.Y, x)
+	return nil
+}
+
+func TestProveContext(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit, witness proveContextCircuit
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	assert.NoError(err)
+	pk, vk, err := Setup(r1cs)
+	assert.NoError(err)
+
+	witness.X.Assign(2)
+	witness.Y.Assign(0) // not the right value
+	_, err = Prove(r1cs, pk, &witness)
+	assert.Error(err)
+
+	// with force, the proof is computed, and all the phases are reported
+	var phases []backend.ProverPhase
+	var last time.Duration
+	progress := func(phase backend.ProverPhase, elapsed time.Duration) {
+		assert.True(elapsed >= last, "elapsed time must not decrease")
+		last = elapsed
+		phases = append(phases, phase)
+	}
+	proof, err := ProveContext(context.Background(), r1cs, pk, &witness, backend.WithProgress(progress), backend.IgnoreSolverError())
+	assert.NoError(err)
+	assert.Error(Verify(proof, vk, &witness))
+	assert.Equal(backend.PhaseSolve, phases[0])
+	assert.Equal(backend.PhaseH, phases[1])
+	assert.ElementsMatch([]backend.ProverPhase{backend.PhaseMSMA, backend.PhaseMSMB, backend.PhaseMSMK}, phases[2:])
+
+	// a cancelled context stops the computation, even with force
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	phases = nil
+	_, err = ProveContext(ctx, r1cs, pk, &witness, backend.WithProgress(progress), backend.IgnoreSolverError())
+	assert.Equal(context.Canceled, err)
+	assert.Empty(phases)
+
+	// the context is checked between the phases
+	ctx, cancel = context.WithCancel(context.Background())
+	_, err = ProveContext(ctx, r1cs, pk, &witness, backend.WithProgress(func(phase backend.ProverPhase, elapsed time.Duration) {
+		if phase == backend.PhaseSolve {
+			cancel()
+		}
+	}), backend.IgnoreSolverError())
+	assert.Equal(context.Canceled, err)
+}
+
+type printCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *printCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.Println("x =", circuit.X)
+	cs.AssertIsEqual(circuit.Y, cs.Mul(circuit.X, circuit.X))
+	return nil
+}
+
+func TestProveOptions(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit, witness printCircuit
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	assert.NoError(err)
+	pk, vk, err := Setup(r1cs)
+	assert.NoError(err)
+	witness.X.Assign(3)
+	witness.Y.Assign(9)
+
+	// the circuit logs are printed on the logger output
+	var logs bytes.Buffer
+	proof, err := Prove(r1cs, pk, &witness, backend.WithLoggerOutput(&logs), backend.WithNbTasks(1))
+	assert.NoError(err)
+	assert.NoError(Verify(proof, vk, &witness))
+	assert.True(strings.HasSuffix(logs.String(), "x = 3\n"), logs.String())
+
+	// the same random source produces the same proof
+	var proofs [2]bytes.Buffer
+	for i := range proofs {
+		proof, err := Prove(r1cs, pk, &witness, backend.WithLoggerOutput(nil), backend.WithRandomSource(rand.New(rand.NewSource(42))))
+		assert.NoError(err)
+		assert.NoError(Verify(proof, vk, &witness))
+		_, err = proof.WriteTo(&proofs[i])
+		assert.NoError(err)
+	}
+	assert.Equal(proofs[0].Bytes(), proofs[1].Bytes())
+
+	// invalid options
+	_, err = Prove(r1cs, pk, &witness, backend.WithNbTasks(0))
+	assert.Error(err)
+	_, err = Prove(r1cs, pk, &witness, backend.WithRandomSource(nil))
+	assert.Error(err)
+}
+
+func TestDeterministicSetup(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit, witness printCircuit
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	assert.NoError(err)
+	witness.X.Assign(3)
+	witness.Y.Assign(9)
+
+	// the same seed produces the same keys
+	var vks [2]bytes.Buffer
+	for i := range vks {
+		pk, vk, err := Setup(r1cs, backend.WithSetupRandomSource(rand.New(rand.NewSource(42))))
+		assert.NoError(err)
+		proof, err := Prove(r1cs, pk, &witness, backend.WithLoggerOutput(nil))
+		assert.NoError(err)
+		assert.NoError(Verify(proof, vk, &witness))
+		_, err = vk.WriteTo(&vks[i])
+		assert.NoError(err)
+	}
+	assert.Equal(vks[0].Bytes(), vks[1].Bytes())
+
+	_, _, err = Setup(r1cs, backend.WithSetupRandomSource(nil))
+	assert.Error(err)
+}
+
+func TestSolveWires(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit, good, bad printCircuit
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit, frontend.WithDebugInfo())
+	assert.NoError(err)
+	good.X.Assign(3)
+	good.Y.Assign(9)
+	bad.X.Assign(4)
+	bad.Y.Assign(9)
+	solveWires := func(w frontend.Circuit) (backend.Wires, error) {
+		var buf bytes.Buffer
+		_, err := witness.WriteFullTo(&buf, ecc.BN254, w)
+		assert.NoError(err)
+		return r1cs.SolveWires(&buf, backend.WithLoggerOutput(nil))
+	}
+
+	// wires = [ONE_WIRE | public | secret | internal]
+	wires, err := solveWires(&good)
+	assert.NoError(err)
+	assert.Len(wires, 4)
+	for i, expected := range []struct{ label, value string }{{"ONE_WIRE", "1"}, {"Y", "9"}, {"X", "3"}, {"", "9"}} {
+		assert.Equal(i, wires[i].ID)
+		assert.True(wires[i].Solved)
+		assert.Equal(expected.value, wires[i].Value)
+		if expected.label != "" {
+			assert.Equal(expected.label, wires[i].Label)
+		}
+	}
+	// the internal wire is labeled by the call site of cs.Mul
+	assert.Contains(wires[3].Label, "printCircuit).Define")
+	assert.Contains(wires[3].Label, "prove_test.go")
+
+	// the wires are returned with the unsatisfied constraints, if any
+	badWires, err := solveWires(&bad)
+	var unsatisfied *backend.UnsatisfiedConstraintsError
+	assert.True(errors.As(err, &unsatisfied), "expected an UnsatisfiedConstraintsError")
+	assert.Len(badWires, 4)
+	assert.Equal("16", badWires[3].Value)
+	diff := badWires.Diff(wires)
+	assert.Len(diff, 2)
+	assert.Equal("X", diff[0].Label)
+	assert.Equal(3, diff[1].ID)
+
+	// exports
+	var buf bytes.Buffer
+	assert.NoError(wires.WriteJSON(&buf))
+	var decoded backend.Wires
+	assert.NoError(json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(wires, decoded)
+
+	buf.Reset()
+	assert.NoError(wires.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(lines, 5)
+	assert.Equal("id,label,solved,value", lines[0])
+	assert.Equal("1,Y,true,9", lines[2])
+}
+
+type printfCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *printfCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.Printf("x = %d (%#x), %d%%", circuit.X, circuit.X, 100)
+	cs.PrintIf(cs.IsZero(circuit.X, curveID), "x is zero")
+	cs.PrintIf(circuit.X, "x^2 = %v", cs.Mul(circuit.X, circuit.X))
+	cs.AssertIsEqual(circuit.Y, cs.Mul(circuit.X, circuit.X))
+	return nil
+}
+
+func TestPrintf(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit, witness printfCircuit
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	assert.NoError(err)
+	witness.X.Assign(255)
+	witness.Y.Assign(65025)
+
+	// the logger receives the caller, the message and the values of the printed logs
+	type log struct {
+		caller, msg string
+		values      []*big.Int
+	}
+	var logs []log
+	logger := backend.LoggerFunc(func(caller, msg string, values []*big.Int) {
+		logs = append(logs, log{caller, msg, values})
+	})
+	assert.NoError(IsSolved(r1cs, &witness, backend.WithLogger(logger)))
+	assert.Len(logs, 2)
+
+	assert.True(strings.HasPrefix(logs[0].caller, "prove_test.go:"), logs[0].caller)
+	assert.Equal("x = 255 (0xff), 100%", logs[0].msg)
+	assert.Len(logs[0].values, 2)
+	assert.Equal(int64(255), logs[0].values[0].Int64())
+
+	assert.Equal("x^2 = 65025", logs[1].msg)
+	assert.Len(logs[1].values, 1)
+	assert.NotEqual(logs[0].caller, logs[1].caller)
+
+	// without logger, the logs are printed on the logger output, prefixed by their caller
+	var out bytes.Buffer
+	assert.NoError(IsSolved(r1cs, &witness, backend.WithLoggerOutput(&out)))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(lines, 2)
+	assert.Equal(logs[0].caller+" x = 255 (0xff), 100%", lines[0])
+}
@@ -0,0 +1,311 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+// the points are multiples of the generators: [α]1 = 2.G1, [β]2 = 3.G2, [γ]2 = G2, [δ]2 = 5.G2, IC = [G1, 7.G1],
+// and the proof is A = 11.G1, B = 13.G2, C = 17.G1
+var snarkJSVectors = []struct {
+	curveID ecc.ID
+	vk      string
+	proof   string
+}{
+	{
+		ecc.BN254,
+		`{
+ "protocol": "groth16",
+ "curve": "bn128",
+ "nPublic": 1,
+ "vk_alpha_1": [
+  "1368015179489954701390400359078579693043519447331113978918064868415326638035",
+  "9918110051302171585080402603319702774565515993150576347155970296011118125764",
+  "1"
+ ],
+ "vk_beta_2": [
+  [
+   "2725019753478801796453339367788033689375851816420509565303521482350756874229",
+   "7273165102799931111715871471550377909735733521218303035754523677688038059653"
+  ],
+  [
+   "2512659008974376214222774206987427162027254181373325676825515531566330959255",
+   "957874124722006818841961785324909313781880061366718538693995380805373202866"
+  ],
+  [
+   "1",
+   "0"
+  ]
+ ],
+ "vk_gamma_2": [
+  [
+   "10857046999023057135944570762232829481370756359578518086990519993285655852781",
+   "11559732032986387107991004021392285783925812861821192530917403151452391805634"
+  ],
+  [
+   "8495653923123431417604973247489272438418190587263600148770280649306958101930",
+   "4082367875863433681332203403145435568316851327593401208105741076214120093531"
+  ],
+  [
+   "1",
+   "0"
+  ]
+ ],
+ "vk_delta_2": [
+  [
+   "20954117799226682825035885491234530437475518021362091509513177301640194298072",
+   "4540444681147253467785307942530223364530218361853237193970751657229138047649"
+  ],
+  [
+   "21508930868448350162258892668132814424284302804699005394342512102884055673846",
+   "11631839690097995216017572651900167465857396346217730511548857041925508482915"
+  ],
+  [
+   "1",
+   "0"
+  ]
+ ],
+ "IC": [
+  [
+   "1",
+   "2",
+   "1"
+  ],
+  [
+   "10415861484417082502655338383609494480414113902179649885744799961447382638712",
+   "10196215078179488638353184030336251401353352596818396260819493263908881608606",
+   "1"
+  ]
+ ]
+}`,
+		`{
+ "pi_a": [
+  "19033251874843656108471242320417533909414939332036131356573128480367742634479",
+  "20792135454608030201903199625673964159744755218442260092768620403349374102584",
+  "1"
+ ],
+ "pi_b": [
+  [
+   "16137324789686743234629608741537369181251990815455155257427276976918350071287",
+   "280672898440571232725436467950720547829638241593507531241322547969961007057"
+  ],
+  [
+   "12136420650226457477690750437223209427924916790606163705631661913973995426040",
+   "17641806683785498955878869918183868440783188556637975525088932771694068429840"
+  ],
+  [
+   "1",
+   "0"
+  ]
+ ],
+ "pi_c": [
+  "12852522211178622728088728121177131998585782282560100422041774753646305409836",
+  "15918672909255108529698304535345707578139606904951176064731093256171019744261",
+  "1"
+ ],
+ "protocol": "groth16",
+ "curve": "bn128"
+}`,
+	},
+	{
+		ecc.BLS12_381,
+		`{
+ "protocol": "groth16",
+ "curve": "bls12381",
+ "nPublic": 1,
+ "vk_alpha_1": [
+  "838589206289216005799424730305866328161735431124665289961769162861615689790485775997575391185127590486775437397838",
+  "3450209970729243429733164009999191867485184320918914219895632678707687208996709678363578245114137957452475385814312",
+  "1"
+ ],
+ "vk_beta_2": [
+  [
+   "2795155019138475430256695697248607867022196082692926850257941893956680503583886174445899854256891620515274933186478",
+   "1418901263980595683832511076652430035654903023556505873032297534993731256453342997202098832403658787934376638965468"
+  ],
+  [
+   "1713408536894110516522969272885192173669900392782465197506312048399987681703463801235485042423756235640603447122066",
+   "1376945178829045108008380835987620979304438294788415956605678509674588356753313865659068546846109894276784773457993"
+  ],
+  [
+   "1",
+   "0"
+  ]
+ ],
+ "vk_gamma_2": [
+  [
+   "352701069587466618187139116011060144890029952792775240219908644239793785735715026873347600343865175952761926303160",
+   "3059144344244213709971259814753781636986470325476647558659373206291635324768958432433509563104347017837885763365758"
+  ],
+  [
+   "1985150602287291935568054521177171638300868978215655730859378665066344726373823718423869104263333984641494340347905",
+   "927553665492332455747201965776037880757740193453592970025027978793976877002675564980949289727957565575433344219582"
+  ],
+  [
+   "1",
+   "0"
+  ]
+ ],
+ "vk_delta_2": [
+  [
+   "626266753989782654150694692036924390988881741494970156941802666795495657949695370746846269655547812403809070556808",
+   "151216712330486580381289676720993530468452734725315418939914686037671894984472908062266534423934877412152819881174"
+  ],
+  [
+   "3957221353860521190838035852656308152792962079075169227140436352788803481025497873165648235984294733156170881957140",
+   "1417335358548100222817200951198539764927940191545220572034594310270669391308385540419375417621016275437603665889670"
+  ],
+  [
+   "1",
+   "0"
+  ]
+ ],
+ "IC": [
+  [
+   "3685416753713387016781088315183077757961620795782546409894578378688607592378376318836054947676345821548104185464507",
+   "1339506544944476473020471379941921221584933875938349620426543736416511423956333506472724655353366534992391756441569",
+   "1"
+  ],
+  [
+   "3872473689207892378470335395114902631176541028916158626161662840934315241539439160301564344905260612642783644023991",
+   "2547806390474846378491145127515427451279430889101277169890334737406180277792171092197824251632631671609860505999900",
+   "1"
+  ]
+ ]
+}`,
+		`{
+ "pi_a": [
+  "152387348683924138328143764814868516652582147878375891005399726039073598211013784035034571365338571582701764549205",
+  "665105738604193407187869466118276726708407579576722424320519765435543092874091633788813503861572804644225114385040",
+  "1"
+ ],
+ "pi_b": [
+  [
+   "3252076017274388828622206671408818438213289001940341914418279427784465318301872975623180501880263191632504244190844",
+   "1841883482796017079242142842524547888057977530191501791390042013476763099333665410882950048153668780302164146534839"
+  ],
+  [
+   "1273244322205217843543516352362729518657343388696189606977243725921842761758473696942399421873896920394247371666785",
+   "1564127983934854383754547755111056955147077478070638342150656335179719100287080554942256734310728569978219171548341"
+  ],
+  [
+   "1",
+   "0"
+  ]
+ ],
+ "pi_c": [
+  "2554578984795809389406742762531213667129156914300623539610309295267298841918230057559069890154170007284001879797562",
+  "2256486915525078853507266138939339615416680485211198544640452004247787899478628339805267040206555429583605814625334",
+  "1"
+ ],
+ "protocol": "groth16",
+ "curve": "bls12381"
+}`,
+	},
+}
+
+type snarkJSCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *snarkJSCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(circuit.Y, cs.Mul(circuit.X, circuit.X))
+	return nil
+}
+
+func TestSnarkJSVectors(t *testing.T) {
+	assert := require.New(t)
+
+	for _, v := range snarkJSVectors {
+		vk := NewVerifyingKey(v.curveID)
+		assert.NoError(vk.ImportSnarkJS(strings.NewReader(v.vk)))
+		assert.Equal(1, vk.NbPublicWitness())
+
+		var buf bytes.Buffer
+		assert.NoError(vk.ExportSnarkJS(&buf))
+		assert.JSONEq(v.vk, buf.String())
+
+		proof := NewProof(v.curveID)
+		assert.NoError(proof.ImportSnarkJS(strings.NewReader(v.proof)))
+
+		buf.Reset()
+		assert.NoError(proof.ExportSnarkJS(&buf))
+		assert.JSONEq(v.proof, buf.String())
+
+		// points must be given in affine coordinates (z == 1)
+		invalid := strings.Replace(v.proof, `"1"`, `"2"`, 1)
+		assert.Error(NewProof(v.curveID).ImportSnarkJS(strings.NewReader(invalid)))
+
+		// the curve name must match
+		other := snarkJSVectors[0]
+		if other.curveID == v.curveID {
+			other = snarkJSVectors[1]
+		}
+		assert.Error(NewVerifyingKey(v.curveID).ImportSnarkJS(strings.NewReader(other.vk)))
+	}
+
+	// snarkjs only supports BN254 and BLS12-381
+	assert.Error(NewVerifyingKey(ecc.BW6_761).ImportSnarkJS(strings.NewReader(snarkJSVectors[0].vk)))
+}
+
+func TestSnarkJSRoundTrip(t *testing.T) {
+	assert := require.New(t)
+
+	for _, curveID := range []ecc.ID{ecc.BN254, ecc.BLS12_381} {
+		var circuit snarkJSCircuit
+		r1cs, err := frontend.Compile(curveID, backend.GROTH16, &circuit)
+		assert.NoError(err)
+
+		pk, vk, err := Setup(r1cs)
+		assert.NoError(err)
+
+		var w snarkJSCircuit
+		w.X.Assign(3)
+		w.Y.Assign(9)
+		proof, err := Prove(r1cs, pk, &w)
+		assert.NoError(err)
+
+		// export verification_key.json, proof.json and public.json
+		var vkJSON, proofJSON, publicJSON bytes.Buffer
+		assert.NoError(vk.ExportSnarkJS(&vkJSON))
+		assert.NoError(proof.ExportSnarkJS(&proofJSON))
+		assert.NoError(witness.WritePublicSnarkJS(&publicJSON, curveID, &w))
+		assert.JSONEq(`["9"]`, publicJSON.String())
+
+		// import them back and verify
+		importedVK := NewVerifyingKey(curveID)
+		assert.NoError(importedVK.ImportSnarkJS(&vkJSON))
+		importedProof := NewProof(curveID)
+		assert.NoError(importedProof.ImportSnarkJS(&proofJSON))
+		var publicWitness snarkJSCircuit
+		assert.NoError(witness.ReadPublicSnarkJS(&publicJSON, curveID, &publicWitness))
+
+		assert.NoError(Verify(importedProof, importedVK, &publicWitness))
+
+		var wrongWitness snarkJSCircuit
+		assert.NoError(witness.ReadPublicSnarkJS(strings.NewReader(`["10"]`), curveID, &wrongWitness))
+		assert.Error(Verify(importedProof, importedVK, &wrongWitness))
+	}
+}
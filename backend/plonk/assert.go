@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 
 	cs_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/cs"
@@ -105,44 +106,52 @@ func (assert *Assert) SolvingFailed(ccs frontend.CompiledConstraintSystem, witne
 
 // IsSolved attempts to solve the constraint system with provided witness
 // returns nil if it succeeds, error otherwise.
-func IsSolved(ccs frontend.CompiledConstraintSystem, witness frontend.Circuit) error {
+//
+// Only the solver options of opts are used (see backend.WithLoggerOutput, backend.WithNbTasks
+// and backend.WithDiagnostics).
+func IsSolved(ccs frontend.CompiledConstraintSystem, witness frontend.Circuit, opts ...backend.ProverOption) error {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return err
+	}
+
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
 		w := witness_bn254.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return tccs.IsSolved(w)
+		return tccs.IsSolved(w, opt)
 	case *cs_bls12381.SparseR1CS:
 		w := witness_bls12381.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return tccs.IsSolved(w)
+		return tccs.IsSolved(w, opt)
 	case *cs_bls12377.SparseR1CS:
 		w := witness_bls12377.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return tccs.IsSolved(w)
+		return tccs.IsSolved(w, opt)
 	case *cs_bw6761.SparseR1CS:
 		w := witness_bw6761.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return tccs.IsSolved(w)
+		return tccs.IsSolved(w, opt)
 	case *cs_bls24315.SparseR1CS:
 		w := witness_bls24315.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return tccs.IsSolved(w)
+		return tccs.IsSolved(w, opt)
 	case *cs_bw6672.SparseR1CS:
 		w := witness_bw6672.Witness{}
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return tccs.IsSolved(w)
+		return tccs.IsSolved(w, opt)
 
 
 	case *cs_bw6633.SparseR1CS:
@@ -150,7 +159,7 @@ func IsSolved(ccs frontend.CompiledConstraintSystem, witness frontend.Circuit) e
 		if err := w.FromFullAssignment(witness); err != nil {
 			return err
 		}
-		return tccs.IsSolved(w)
+		return tccs.IsSolved(w, opt)
 
 	default:
 		panic("unknown constraint system type")
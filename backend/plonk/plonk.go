@@ -20,11 +20,13 @@
 package plonk
 
 import (
+	"context"
 	"crypto/rand"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 
 	cs_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/cs"
@@ -98,7 +100,14 @@ type VerifyingKey interface {
 
 // NewSRS uses ccs nb variables and nb constraints to initialize a kzg srs
 // note that this method is here for convenience only: in production, a SRS generated through MPC should be used.
-func NewSRS(ccs frontend.CompiledConstraintSystem) (kzg.SRS, error) {
+//
+// The randomness is read from crypto/rand.Reader, unless a backend.WithSetupRandomSource option is set.
+func NewSRS(ccs frontend.CompiledConstraintSystem, opts ...backend.SetupOption) (kzg.SRS, error) {
+
+	opt, err := backend.NewSetupConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	nbConstraints := ccs.GetNbConstraints()
 	internal, secret, public := ccs.GetNbVariables()
@@ -111,44 +120,44 @@ func NewSRS(ccs frontend.CompiledConstraintSystem) (kzg.SRS, error) {
 
 	switch ccs.(type) {
 	case *cs_bn254.SparseR1CS:
-		alpha, err := rand.Int(rand.Reader, fr_bn254.Modulus())
+		alpha, err := rand.Int(opt.RandomSource, fr_bn254.Modulus())
 		if err != nil {
 			return nil, err
 		}
 		return kzg_bn254.NewSRS(kzgSize, alpha)
 	case *cs_bls12381.SparseR1CS:
-		alpha, err := rand.Int(rand.Reader, fr_bls12381.Modulus())
+		alpha, err := rand.Int(opt.RandomSource, fr_bls12381.Modulus())
 		if err != nil {
 			return nil, err
 		}
 		return kzg_bls12381.NewSRS(kzgSize, alpha)
 	case *cs_bls12377.SparseR1CS:
-		alpha, err := rand.Int(rand.Reader, fr_bls12377.Modulus())
+		alpha, err := rand.Int(opt.RandomSource, fr_bls12377.Modulus())
 		if err != nil {
 			return nil, err
 		}
 		return kzg_bls12377.NewSRS(kzgSize, alpha)
 	case *cs_bw6761.SparseR1CS:
-		alpha, err := rand.Int(rand.Reader, fr_bw6761.Modulus())
+		alpha, err := rand.Int(opt.RandomSource, fr_bw6761.Modulus())
 		if err != nil {
 			return nil, err
 		}
 		return kzg_bw6761.NewSRS(kzgSize, alpha)
 	case *cs_bw6672.SparseR1CS:
-		alpha, err := rand.Int(rand.Reader, fr_bw6672.Modulus())
+		alpha, err := rand.Int(opt.RandomSource, fr_bw6672.Modulus())
 		if err != nil {
 			return nil, err
 		}
 		return kzg_bw6672.NewSRS(kzgSize, alpha)
 
 	case *cs_bw6633.SparseR1CS:
-		alpha, err := rand.Int(rand.Reader, fr_bw6633.Modulus())
+		alpha, err := rand.Int(opt.RandomSource, fr_bw6633.Modulus())
 		if err != nil {
 			return nil, err
 		}
 		return kzg_bw6633.NewSRS(kzgSize, alpha)
 	case *cs_bls24315.SparseR1CS:
-		alpha, err := rand.Int(rand.Reader, fr_bls24315.Modulus())
+		alpha, err := rand.Int(opt.RandomSource, fr_bls24315.Modulus())
 		if err != nil {
 			return nil, err
 		}
@@ -183,7 +192,24 @@ func Setup(ccs frontend.CompiledConstraintSystem, kzgSRS kzg.SRS) (ProvingKey, V
 }
 
 // Prove generates PLONK proof from a circuit, associated preprocessed public data, and the witness
-func Prove(ccs frontend.CompiledConstraintSystem, pk ProvingKey, fullWitness frontend.Circuit) (Proof, error) {
+//
+// The prover is configured with opts (see backend.ProverOption)
+func Prove(ccs frontend.CompiledConstraintSystem, pk ProvingKey, fullWitness frontend.Circuit, opts ...backend.ProverOption) (Proof, error) {
+	return ProveContext(context.Background(), ccs, pk, fullWitness, opts...)
+}
+
+// ProveContext behaves like Prove, but stops the computation and returns ctx.Err() if ctx is done
+// before the proof is computed. ctx is checked by the solver and between the FFTs and multi-exponentiations;
+// one which has already started runs to completion before ProveContext returns.
+//
+// If a backend.WithProgress option is set, its function is called at the end of each phase of the computation
+// (backend.PhaseSolve, backend.PhaseH, backend.PhaseCommitments and backend.PhaseOpenings).
+func ProveContext(ctx context.Context, ccs frontend.CompiledConstraintSystem, pk ProvingKey, fullWitness frontend.Circuit, opts ...backend.ProverOption) (Proof, error) {
+
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
@@ -191,41 +217,41 @@ func Prove(ccs frontend.CompiledConstraintSystem, pk ProvingKey, fullWitness fro
 		if err := w.FromFullAssignment(fullWitness); err != nil {
 			return nil, err
 		}
-		return plonk_bn254.Prove(tccs, pk.(*plonk_bn254.ProvingKey), w)
+		return plonk_bn254.ProveContext(ctx, tccs, pk.(*plonk_bn254.ProvingKey), w, opt)
 
 	case *cs_bls12381.SparseR1CS:
 		w := witness_bls12381.Witness{}
 		if err := w.FromFullAssignment(fullWitness); err != nil {
 			return nil, err
 		}
-		return plonk_bls12381.Prove(tccs, pk.(*plonk_bls12381.ProvingKey), w)
+		return plonk_bls12381.ProveContext(ctx, tccs, pk.(*plonk_bls12381.ProvingKey), w, opt)
 
 	case *cs_bls12377.SparseR1CS:
 		w := witness_bls12377.Witness{}
 		if err := w.FromFullAssignment(fullWitness); err != nil {
 			return nil, err
 		}
-		return plonk_bls12377.Prove(tccs, pk.(*plonk_bls12377.ProvingKey), w)
+		return plonk_bls12377.ProveContext(ctx, tccs, pk.(*plonk_bls12377.ProvingKey), w, opt)
 
 	case *cs_bw6761.SparseR1CS:
 		w := witness_bw6761.Witness{}
 		if err := w.FromFullAssignment(fullWitness); err != nil {
 			return nil, err
 		}
-		return plonk_bw6761.Prove(tccs, pk.(*plonk_bw6761.ProvingKey), w)
+		return plonk_bw6761.ProveContext(ctx, tccs, pk.(*plonk_bw6761.ProvingKey), w, opt)
 
 	case *cs_bls24315.SparseR1CS:
 		w := witness_bls24315.Witness{}
 		if err := w.FromFullAssignment(fullWitness); err != nil {
 			return nil, err
 		}
-		return plonk_bls24315.Prove(tccs, pk.(*plonk_bls24315.ProvingKey), w)
+		return plonk_bls24315.ProveContext(ctx, tccs, pk.(*plonk_bls24315.ProvingKey), w, opt)
 	case *cs_bw6672.SparseR1CS:
 		w := witness_bw6672.Witness{}
 		if err := w.FromFullAssignment(fullWitness); err != nil {
 			return nil, err
 		}
-		return plonk_bw6672.Prove(tccs, pk.(*plonk_bw6672.ProvingKey), w)
+		return plonk_bw6672.ProveContext(ctx, tccs, pk.(*plonk_bw6672.ProvingKey), w, opt)
 
 
 	case *cs_bw6633.SparseR1CS:
@@ -233,7 +259,7 @@ func Prove(ccs frontend.CompiledConstraintSystem, pk ProvingKey, fullWitness fro
 		if err := w.FromFullAssignment(fullWitness); err != nil {
 			return nil, err
 		}
-		return plonk_bw6633.Prove(tccs, pk.(*plonk_bw6633.ProvingKey), w)
+		return plonk_bw6633.ProveContext(ctx, tccs, pk.(*plonk_bw6633.ProvingKey), w, opt)
 
 	default:
 		panic("unrecognized R1CS curve type")
@@ -402,8 +428,41 @@ func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
 	return vk
 }
 
+// NewKZGSRS instantiates a curve-typed KZG SRS and returns an interface
+// This function exists for serialization purposes
+func NewKZGSRS(curveID ecc.ID) kzg.SRS {
+	var srs kzg.SRS
+	switch curveID {
+	case ecc.BN254:
+		srs = &kzg_bn254.SRS{}
+	case ecc.BLS12_377:
+		srs = &kzg_bls12377.SRS{}
+	case ecc.BLS12_381:
+		srs = &kzg_bls12381.SRS{}
+	case ecc.BW6_761:
+		srs = &kzg_bw6761.SRS{}
+	case ecc.BW6_633:
+		srs = &kzg_bw6633.SRS{}
+	case ecc.BW6_672:
+		srs = &kzg_bw6672.SRS{}
+	case ecc.BLS24_315:
+		srs = &kzg_bls24315.SRS{}
+	default:
+		panic("not implemented")
+	}
+
+	return srs
+}
+
 // ReadAndProve generates PLONK proof from a circuit, associated proving key, and the full witness
-func ReadAndProve(ccs frontend.CompiledConstraintSystem, pk ProvingKey, witness io.Reader) (Proof, error) {
+//
+// The prover is configured with opts (see backend.ProverOption)
+func ReadAndProve(ccs frontend.CompiledConstraintSystem, pk ProvingKey, witness io.Reader, opts ...backend.ProverOption) (Proof, error) {
+
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	_, nbSecret, nbPublic := ccs.GetNbVariables()
 	expectedSize := (nbSecret + nbPublic)
@@ -415,7 +474,7 @@ func ReadAndProve(ccs frontend.CompiledConstraintSystem, pk ProvingKey, witness
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		proof, err := plonk_bn254.Prove(tccs, _pk, w)
+		proof, err := plonk_bn254.Prove(tccs, _pk, w, opt)
 		if err != nil {
 			return proof, err
 		}
@@ -427,7 +486,7 @@ func ReadAndProve(ccs frontend.CompiledConstraintSystem, pk ProvingKey, witness
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		proof, err := plonk_bls12381.Prove(tccs, _pk, w)
+		proof, err := plonk_bls12381.Prove(tccs, _pk, w, opt)
 		if err != nil {
 			return proof, err
 		}
@@ -439,7 +498,7 @@ func ReadAndProve(ccs frontend.CompiledConstraintSystem, pk ProvingKey, witness
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		proof, err := plonk_bls12377.Prove(tccs, _pk, w)
+		proof, err := plonk_bls12377.Prove(tccs, _pk, w, opt)
 		if err != nil {
 			return proof, err
 		}
@@ -450,7 +509,7 @@ func ReadAndProve(ccs frontend.CompiledConstraintSystem, pk ProvingKey, witness
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		proof, err := plonk_bw6672.Prove(tccs, _pk, w)
+		proof, err := plonk_bw6672.Prove(tccs, _pk, w, opt)
 		if err != nil {
 			return proof, err
 		}
@@ -463,7 +522,7 @@ func ReadAndProve(ccs frontend.CompiledConstraintSystem, pk ProvingKey, witness
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		proof, err := plonk_bw6633.Prove(tccs, _pk, w)
+		proof, err := plonk_bw6633.Prove(tccs, _pk, w, opt)
 		if err != nil {
 			return proof, err
 		}
@@ -475,7 +534,7 @@ func ReadAndProve(ccs frontend.CompiledConstraintSystem, pk ProvingKey, witness
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		proof, err := plonk_bw6761.Prove(tccs, _pk, w)
+		proof, err := plonk_bw6761.Prove(tccs, _pk, w, opt)
 		if err != nil {
 			return proof, err
 		}
@@ -487,7 +546,7 @@ func ReadAndProve(ccs frontend.CompiledConstraintSystem, pk ProvingKey, witness
 		if _, err := w.LimitReadFrom(witness, expectedSize); err != nil {
 			return nil, err
 		}
-		proof, err := plonk_bls24315.Prove(tccs, _pk, w)
+		proof, err := plonk_bls24315.Prove(tccs, _pk, w, opt)
 		if err != nil {
 			return proof, err
 		}
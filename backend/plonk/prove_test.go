@@ -0,0 +1,142 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonk
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+type proveContextCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *proveContextCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	x := circuit.X
+	for i := 0; i < 100; i++ {
+		x = cs.Mul(x, x)
+	}
+	cs.AssertIsEqual(circuit.Y, x)
+	return nil
+}
+
+func TestProveContext(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit, witness proveContextCircuit
+	ccs, err := frontend.Compile(ecc.BN254, backend.PLONK, &circuit)
+	assert.NoError(err)
+	srs, err := NewSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := Setup(ccs, srs)
+	assert.NoError(err)
+
+	// y = x**(2**100)
+	witness.X.Assign(1)
+	witness.Y.Assign(1)
+
+	var phases []backend.ProverPhase
+	var last time.Duration
+	progress := func(phase backend.ProverPhase, elapsed time.Duration) {
+		assert.True(elapsed >= last, "elapsed time must not decrease")
+		last = elapsed
+		phases = append(phases, phase)
+	}
+	proof, err := ProveContext(context.Background(), ccs, pk, &witness, backend.WithProgress(progress))
+	assert.NoError(err)
+	assert.NoError(Verify(proof, vk, &witness))
+	assert.Equal([]backend.ProverPhase{backend.PhaseSolve, backend.PhaseH, backend.PhaseCommitments, backend.PhaseOpenings}, phases)
+
+	// a cancelled context stops the computation
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	phases = nil
+	_, err = ProveContext(ctx, ccs, pk, &witness, backend.WithProgress(progress))
+	assert.Equal(context.Canceled, err)
+	assert.Empty(phases)
+
+	// the context is checked between the phases
+	ctx, cancel = context.WithCancel(context.Background())
+	_, err = ProveContext(ctx, ccs, pk, &witness, backend.WithProgress(func(phase backend.ProverPhase, elapsed time.Duration) {
+		if phase == backend.PhaseSolve {
+			cancel()
+		}
+	}))
+	assert.Equal(context.Canceled, err)
+}
+
+func TestDeterministicProve(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit, witness proveContextCircuit
+	ccs, err := frontend.Compile(ecc.BN254, backend.PLONK, &circuit)
+	assert.NoError(err)
+	witness.X.Assign(1)
+	witness.Y.Assign(1)
+
+	// the same seeds produce the same SRS, keys and proof
+	var proofs [2]bytes.Buffer
+	for i := range proofs {
+		srs, err := NewSRS(ccs, backend.WithSetupRandomSource(rand.New(rand.NewSource(42))))
+		assert.NoError(err)
+		pk, vk, err := Setup(ccs, srs)
+		assert.NoError(err)
+		proof, err := Prove(ccs, pk, &witness, backend.WithRandomSource(rand.New(rand.NewSource(42))))
+		assert.NoError(err)
+		assert.NoError(Verify(proof, vk, &witness))
+		_, err = proof.WriteTo(&proofs[i])
+		assert.NoError(err)
+	}
+	assert.Equal(proofs[0].Bytes(), proofs[1].Bytes())
+}
+
+func TestSolveWires(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit, good proveContextCircuit
+	ccs, err := frontend.Compile(ecc.BN254, backend.PLONK, &circuit, frontend.WithDebugInfo())
+	assert.NoError(err)
+	good.X.Assign(1)
+	good.Y.Assign(1)
+	var buf bytes.Buffer
+	_, err = witness.WriteFullTo(&buf, ecc.BN254, &good)
+	assert.NoError(err)
+
+	// wires = [public | secret | internal]
+	wires, err := ccs.SolveWires(&buf)
+	assert.NoError(err)
+	internal, secret, public := ccs.GetNbVariables()
+	assert.Len(wires, internal+secret+public)
+	assert.Equal("Y", wires[0].Label)
+	assert.Equal("X", wires[1].Label)
+	for _, wire := range wires {
+		assert.True(wire.Solved)
+		assert.Equal("1", wire.Value)
+		if wire.ID > 1 {
+			assert.True(strings.Contains(wire.Label, "proveContextCircuit).Define"), wire.Label)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// Wire is the value of a wire of a solved constraint system
+type Wire struct {
+	ID     int    `json:"id"`     // index of the wire in the constraint system
+	Label  string `json:"label"`  // name of the input, or call site of the constraint computing the internal wire
+	Solved bool   `json:"solved"` // the solver computed the value of the wire
+	Value  string `json:"value"`  // value of the wire, in base 10; empty if the wire is not solved
+}
+
+// Wires holds the values of all the wires of a solved constraint system, ordered by ID
+// (see frontend.CompiledConstraintSystem.SolveWires)
+//
+// Labels are not unique: the internal wires computed at the same call site share the same label.
+type Wires []Wire
+
+// WriteJSON writes the wires to w as a JSON array
+func (wires Wires) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(wires)
+}
+
+// WriteCSV writes the wires to w as CSV, with a header line "id,label,solved,value"
+func (wires Wires) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "label", "solved", "value"}); err != nil {
+		return err
+	}
+	for _, wire := range wires {
+		if err := writer.Write([]string{strconv.Itoa(wire.ID), wire.Label, strconv.FormatBool(wire.Solved), wire.Value}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Diff returns the wires whose value differs in other (compared by ID), with their value in wires;
+// a wire solved in only one of wires and other differs.
+//
+// wires and other are expected to be the wires of the same constraint system, solved with two witnesses.
+func (wires Wires) Diff(other Wires) Wires {
+	var diff Wires
+	for i := 0; i < len(wires) && i < len(other); i++ {
+		if wires[i].Solved != other[i].Solved || wires[i].Value != other[i].Value {
+			diff = append(diff, wires[i])
+		}
+	}
+	return diff
+}
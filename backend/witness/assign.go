@@ -0,0 +1,346 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/backend/compiled"
+	"github.com/consensys/gnark/internal/parser"
+)
+
+// New returns a copy of circuit where the variables are assigned from values.
+//
+// circuit is used as a template and is not modified: its slices must have the expected length,
+// and it can be reused to create many witnesses.
+//
+// values is either
+// 	* a map[string]interface{}, keyed by the circuit field names (or gnark tag names), where nested
+// 	structures are maps, and slices or arrays are Go slices or arrays, following the JSON protocol
+// 	* a plain Go struct mirroring the circuit structure, with the same field names, where each frontend.Variable
+// 	is replaced by a native type
+// Variable values can be of any type supported by frontend.FromInterface (uint64, *big.Int, []byte, fr.Element, ...).
+//
+// All the variables of the circuit must be assigned, and values must not contain unknown fields.
+func New(circuit frontend.Circuit, values interface{}) (frontend.Circuit, error) {
+	if v := reflect.ValueOf(circuit); v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, fmt.Errorf("circuit must be a non-nil pointer, got %T", circuit)
+	}
+	witness := Clone(circuit)
+	Reset(witness)
+
+	a := assigner{convert: toBigInt}
+	if err := a.assign(reflect.ValueOf(witness), reflect.ValueOf(values), "", compiled.Unset); err != nil {
+		return nil, err
+	}
+	return witness, nil
+}
+
+// Reset removes all the values assigned to the variables of witness, such that it can be assigned again
+func Reset(witness frontend.Circuit) {
+	reset(reflect.ValueOf(witness))
+}
+
+// Clone returns a deep copy of circuit (assigned values included)
+//
+// circuit must be a pointer to a structure
+func Clone(circuit frontend.Circuit) frontend.Circuit {
+	src := reflect.ValueOf(circuit)
+	if src.Kind() != reflect.Ptr || src.IsNil() {
+		panic("circuit must be a non-nil pointer")
+	}
+	dst := reflect.New(src.Type().Elem())
+	deepCopy(dst.Elem(), src.Elem())
+	return dst.Interface().(frontend.Circuit)
+}
+
+func reset(tValue reflect.Value) {
+	switch tValue.Kind() {
+	case reflect.Ptr:
+		if !tValue.IsNil() {
+			reset(tValue.Elem())
+		}
+	case reflect.Struct:
+		if tValue.Type() == tVariable {
+			if tValue.CanSet() {
+				tValue.Set(reflect.Zero(tVariable))
+			}
+			return
+		}
+		for i := 0; i < tValue.NumField(); i++ {
+			if tValue.Type().Field(i).PkgPath == "" {
+				reset(tValue.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < tValue.Len(); i++ {
+			reset(tValue.Index(i))
+		}
+	case reflect.Map:
+		iter := tValue.MapRange()
+		for iter.Next() {
+			// map values are not addressable
+			val := reflect.New(tValue.Type().Elem()).Elem()
+			val.Set(iter.Value())
+			reset(val)
+			tValue.SetMapIndex(iter.Key(), val)
+		}
+	}
+}
+
+// deepCopy copies src into dst; the exported slices, arrays, maps and pointers to structures are not shared
+func deepCopy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Struct:
+		dst.Set(src)
+		if src.Type() == tVariable {
+			return
+		}
+		for i := 0; i < src.NumField(); i++ {
+			if src.Type().Field(i).PkgPath == "" {
+				deepCopy(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.Set(src)
+			return
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Ptr:
+		if src.IsNil() || src.Elem().Kind() != reflect.Struct {
+			dst.Set(src)
+			return
+		}
+		dst.Set(reflect.New(src.Type().Elem()))
+		deepCopy(dst.Elem(), src.Elem())
+	case reflect.Map:
+		if src.IsNil() {
+			dst.Set(src)
+			return
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		iter := src.MapRange()
+		for iter.Next() {
+			val := reflect.New(src.Type().Elem()).Elem()
+			deepCopy(val, iter.Value())
+			dst.SetMapIndex(iter.Key(), val)
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+// assigner walks through a circuit structure and assigns its variables from a tree of values
+type assigner struct {
+	publicOnly bool                                      // if set, only public variables are expected
+	convert    func(value interface{}) (*big.Int, error) // converts a leaf of the tree of values
+}
+
+// assign assigns the variables in tValue from the values in src
+//
+// src is either a map[string]... keyed by gnark tag names, or a struct with the same field names than tValue.
+// an invalid src means the value is missing.
+func (a *assigner) assign(tValue, src reflect.Value, name string, visibility compiled.Visibility) error {
+	for tValue.Kind() == reflect.Ptr {
+		if tValue.IsNil() {
+			return fmt.Errorf("when parsing %s: nil pointer", displayName(name))
+		}
+		tValue = tValue.Elem()
+	}
+
+	switch tValue.Kind() {
+	case reflect.Struct:
+		if tValue.Type() == tVariable {
+			return a.assignVariable(tValue, src, name, visibility)
+		}
+		src = indirect(src)
+		switch {
+		case !src.IsValid():
+			return visitFields(tValue, visibility, func(fieldName string, fieldVisibility compiled.Visibility, f reflect.Value, embedded bool) error {
+				return a.assign(f, reflect.Value{}, appendName(name, fieldName), fieldVisibility)
+			})
+		case src.Kind() == reflect.Map && src.Type().Key().Kind() == reflect.String:
+			consumed := make(map[string]struct{})
+			if err := a.assignFromMap(tValue, src, consumed, name, visibility); err != nil {
+				return err
+			}
+			iter := src.MapRange()
+			for iter.Next() {
+				if _, ok := consumed[iter.Key().String()]; !ok {
+					return fmt.Errorf("when parsing %s: unknown field %q", displayName(name), iter.Key().String())
+				}
+			}
+			return nil
+		case src.Kind() == reflect.Struct:
+			return a.assignFromStruct(tValue, src, name, visibility)
+		default:
+			return fmt.Errorf("when parsing %s: expected an object, got %s", displayName(name), src.Type())
+		}
+	case reflect.Slice, reflect.Array:
+		src = indirect(src)
+		if src.IsValid() {
+			if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+				return fmt.Errorf("when parsing %s: expected an array, got %s", displayName(name), src.Type())
+			}
+			if src.Len() != tValue.Len() {
+				return fmt.Errorf("when parsing %s: expected %d elements, got %d", displayName(name), tValue.Len(), src.Len())
+			}
+		}
+		for i := 0; i < tValue.Len(); i++ {
+			var child reflect.Value
+			if src.IsValid() {
+				child = src.Index(i)
+			}
+			if err := a.assign(tValue.Index(i), child, appendName(name, strconv.Itoa(i)), visibility); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if tValue.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("when parsing %s: only maps keyed by strings are supported", displayName(name))
+		}
+		src = indirect(src)
+		if src.IsValid() && (src.Kind() != reflect.Map || src.Type().Key().Kind() != reflect.String) {
+			return fmt.Errorf("when parsing %s: expected an object, got %s", displayName(name), src.Type())
+		}
+		iter := tValue.MapRange()
+		for iter.Next() {
+			var child reflect.Value
+			if src.IsValid() {
+				child = src.MapIndex(iter.Key().Convert(src.Type().Key()))
+			}
+			// map values are not addressable
+			val := reflect.New(tValue.Type().Elem()).Elem()
+			val.Set(iter.Value())
+			if err := a.assign(val, child, appendName(name, iter.Key().String()), visibility); err != nil {
+				return err
+			}
+			tValue.SetMapIndex(iter.Key(), val)
+		}
+		if src.IsValid() {
+			srcIter := src.MapRange()
+			for srcIter.Next() {
+				if !tValue.MapIndex(srcIter.Key().Convert(tValue.Type().Key())).IsValid() {
+					return fmt.Errorf("when parsing %s: unknown field %q", displayName(name), srcIter.Key().String())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (a *assigner) assignVariable(tValue, src reflect.Value, name string, visibility compiled.Visibility) error {
+	for src.IsValid() && src.Kind() == reflect.Interface {
+		src = src.Elem()
+	}
+	if a.publicOnly && visibility != compiled.Public {
+		if src.IsValid() {
+			return fmt.Errorf("when parsing variable %s: unexpected value for a secret variable in public witness", name)
+		}
+		return nil
+	}
+	if !src.IsValid() {
+		return fmt.Errorf("when parsing variable %s: missing assignment", name)
+	}
+	v, err := a.convert(src.Interface())
+	if err != nil {
+		return fmt.Errorf("when parsing variable %s: %v", name, err)
+	}
+	if !tValue.CanAddr() {
+		return fmt.Errorf("when parsing variable %s: variable is not addressable", name)
+	}
+	variable := tValue.Addr().Interface().(*frontend.Variable)
+	if frontend.GetAssignedValue(*variable) != nil {
+		return fmt.Errorf("when parsing variable %s: variable already assigned", name)
+	}
+	variable.Assign(v)
+	return nil
+}
+
+// assignFromMap assigns the fields of the struct tValue from the map src, and marks the keys it used in consumed.
+// embedded structures are read from the same map.
+func (a *assigner) assignFromMap(tValue, src reflect.Value, consumed map[string]struct{}, name string, visibility compiled.Visibility) error {
+	return visitFields(tValue, visibility, func(fieldName string, fieldVisibility compiled.Visibility, f reflect.Value, embedded bool) error {
+		if embedded {
+			if f.Kind() == reflect.Ptr {
+				f = f.Elem()
+			}
+			if f.Kind() != reflect.Struct || f.Type() == tVariable {
+				return fmt.Errorf("when parsing %s: embed tag is only supported on structures", displayName(name))
+			}
+			return a.assignFromMap(f, src, consumed, name, fieldVisibility)
+		}
+		child := src.MapIndex(reflect.ValueOf(fieldName).Convert(src.Type().Key()))
+		if child.IsValid() {
+			consumed[fieldName] = struct{}{}
+		}
+		return a.assign(f, child, appendName(name, fieldName), fieldVisibility)
+	})
+}
+
+// assignFromStruct assigns the fields of the struct tValue from the fields with the same name in src
+func (a *assigner) assignFromStruct(tValue, src reflect.Value, name string, visibility compiled.Visibility) error {
+	expected := make(map[string]struct{})
+	for i := 0; i < tValue.NumField(); i++ {
+		field := tValue.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fieldName, fieldVisibility, omit, err := parser.FieldInfo(field, visibility)
+		if err != nil {
+			return err
+		}
+		if omit {
+			continue
+		}
+		expected[field.Name] = struct{}{}
+		if err := a.assign(tValue.Field(i), src.FieldByName(field.Name), appendName(name, fieldName), fieldVisibility); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < src.NumField(); i++ {
+		field := src.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if _, ok := expected[field.Name]; !ok {
+			return fmt.Errorf("when parsing %s: unknown field %q", displayName(name), field.Name)
+		}
+	}
+	return nil
+}
+
+// indirect follows interfaces and pointers, and returns an invalid value if it finds a nil one
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+type nativePoint struct {
+	X uint64
+	Y *big.Int
+}
+
+type nativeJSONCircuit struct {
+	A     fr.Element
+	B     [2]uint8
+	P     nativePoint
+	Q     []nativePoint
+	Inner struct {
+		X []byte
+		Y string
+	}
+}
+
+func assertSameWitness(assert *require.Assertions, expected, got frontend.Circuit) {
+	var bExpected, bGot bytes.Buffer
+	_, err := WriteFullTo(&bExpected, ecc.BN254, expected)
+	assert.NoError(err)
+	_, err = WriteFullTo(&bGot, ecc.BN254, got)
+	assert.NoError(err)
+	assert.Equal(bExpected.Bytes(), bGot.Bytes())
+}
+
+func TestNewFromMap(t *testing.T) {
+	assert := require.New(t)
+
+	var expected jsonCircuit
+	assignJSONCircuit(&expected)
+
+	values := map[string]interface{}{
+		"a": 1,
+		"B": []uint64{2, 3},
+		"P": map[string]interface{}{"X": 4, "Y": big.NewInt(5)},
+		"Q": []interface{}{
+			map[string]interface{}{"X": 6, "Y": 7},
+			map[string]interface{}{"X": 8, "Y": 9},
+		},
+		"X": []byte{10},
+		"Y": "11",
+	}
+
+	var template jsonCircuit
+	witness, err := New(&template, values)
+	assert.NoError(err)
+	assertSameWitness(assert, &expected, witness)
+
+	// template is not modified and can be reused
+	assert.Nil(frontend.GetAssignedValue(template.A))
+	values["a"] = 2
+	_, err = New(&template, values)
+	assert.NoError(err)
+
+	// missing and unknown fields
+	delete(values, "Y")
+	_, err = New(&template, values)
+	assert.Error(err)
+	values["Y"] = 11
+	values["Z"] = 12
+	_, err = New(&template, values)
+	assert.Error(err)
+
+	// circuit must be a non-nil pointer
+	delete(values, "Z")
+	_, err = New((*jsonCircuit)(nil), values)
+	assert.Error(err)
+}
+
+func TestNewFromStruct(t *testing.T) {
+	assert := require.New(t)
+
+	var expected jsonCircuit
+	assignJSONCircuit(&expected)
+
+	var values nativeJSONCircuit
+	values.A.SetUint64(1)
+	values.B = [2]uint8{2, 3}
+	values.P = nativePoint{4, big.NewInt(5)}
+	values.Q = []nativePoint{{6, big.NewInt(7)}, {8, big.NewInt(9)}}
+	values.Inner.X = []byte{10}
+	values.Inner.Y = "11"
+
+	template := jsonCircuit{}
+	witness, err := New(&template, values)
+	assert.NoError(err)
+	assertSameWitness(assert, &expected, witness)
+
+	// slice length must match the circuit
+	values.Q = values.Q[:1]
+	_, err = New(&template, values)
+	assert.Error(err)
+}
+
+func TestCloneReset(t *testing.T) {
+	assert := require.New(t)
+
+	var witness jsonCircuit
+	assignJSONCircuit(&witness)
+
+	clone := Clone(&witness).(*jsonCircuit)
+	assertSameWitness(assert, &witness, clone)
+
+	// once reset, variables can be assigned again
+	Reset(clone)
+	assert.Nil(frontend.GetAssignedValue(clone.Q[1].Y))
+	assignJSONCircuit(clone)
+	assertSameWitness(assert, &witness, clone)
+
+	// the original witness is untouched
+	Reset(clone)
+	assert.NotNil(frontend.GetAssignedValue(witness.Q[1].Y))
+}
@@ -0,0 +1,294 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	frbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	frbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	frbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	frbn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	frbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	frbw6672 "github.com/consensys/gnark-crypto/ecc/bw6-672/fr"
+	frbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/backend/compiled"
+	"github.com/consensys/gnark/internal/parser"
+)
+
+var tVariable = reflect.TypeOf(frontend.Variable{})
+
+// WriteFullJSON encodes the full witness (public and secret variables) as a JSON object
+// keyed by the circuit field names (see JSON protocol in package documentation)
+func WriteFullJSON(w io.Writer, curveID ecc.ID, witness frontend.Circuit) error {
+	return writeJSON(w, curveID, witness, false)
+}
+
+// WritePublicJSON encodes the public part of the witness as a JSON object
+// keyed by the circuit field names (see JSON protocol in package documentation)
+func WritePublicJSON(w io.Writer, curveID ecc.ID, publicWitness frontend.Circuit) error {
+	return writeJSON(w, curveID, publicWitness, true)
+}
+
+// ReadFullJSON decodes a JSON full witness from r and assigns the values to the
+// (public and secret) variables of witness.
+//
+// The JSON object must match the circuit structure: unknown keys, missing values, arrays of wrong
+// length and values not in [0, r) (r being the modulus of the scalar field of curveID) are rejected.
+func ReadFullJSON(r io.Reader, curveID ecc.ID, witness frontend.Circuit) error {
+	return readJSON(r, curveID, witness, false)
+}
+
+// ReadPublicJSON behaves like ReadFullJSON, but only public variables are expected and assigned
+func ReadPublicJSON(r io.Reader, curveID ecc.ID, publicWitness frontend.Circuit) error {
+	return readJSON(r, curveID, publicWitness, true)
+}
+
+func writeJSON(w io.Writer, curveID ecc.ID, witness frontend.Circuit, publicOnly bool) error {
+	q, err := modulus(curveID)
+	if err != nil {
+		return err
+	}
+	tree, _, err := toJSONTree(reflect.ValueOf(witness), "", compiled.Unset, q, publicOnly)
+	if err != nil {
+		return err
+	}
+	if tree == nil {
+		tree = map[string]interface{}{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(tree)
+}
+
+func readJSON(r io.Reader, curveID ecc.ID, witness frontend.Circuit, publicOnly bool) error {
+	q, err := modulus(curveID)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return err
+	}
+	tValue := reflect.ValueOf(witness)
+	if tValue.Kind() != reflect.Ptr {
+		return errors.New("witness must be a pointer to a circuit structure")
+	}
+	a := assigner{
+		publicOnly: publicOnly,
+		convert: func(value interface{}) (*big.Int, error) {
+			return parseValue(value, q)
+		},
+	}
+	return a.assign(tValue, reflect.ValueOf(tree), "", compiled.Unset)
+}
+
+// toJSONTree builds the JSON representation of the variables in tValue.
+// the boolean is set if at least one variable was encoded
+func toJSONTree(tValue reflect.Value, name string, visibility compiled.Visibility, q *big.Int, publicOnly bool) (interface{}, bool, error) {
+	for tValue.Kind() == reflect.Ptr {
+		if tValue.IsNil() {
+			return nil, false, fmt.Errorf("when parsing %s: nil pointer", displayName(name))
+		}
+		tValue = tValue.Elem()
+	}
+
+	switch tValue.Kind() {
+	case reflect.Struct:
+		if tValue.Type() == tVariable {
+			if publicOnly && visibility != compiled.Public {
+				return nil, false, nil
+			}
+			val := frontend.GetAssignedValue(tValue.Interface().(frontend.Variable))
+			if val == nil {
+				return nil, false, fmt.Errorf("when parsing variable %s: missing assignment", name)
+			}
+			v, err := toBigInt(val)
+			if err != nil {
+				return nil, false, fmt.Errorf("when parsing variable %s: %v", name, err)
+			}
+			v.Mod(v, q)
+			return v.String(), true, nil
+		}
+		res := make(map[string]interface{})
+		found := false
+		err := visitFields(tValue, visibility, func(fieldName string, fieldVisibility compiled.Visibility, f reflect.Value, embedded bool) error {
+			child, ok, err := toJSONTree(f, appendName(name, fieldName), fieldVisibility, q, publicOnly)
+			if err != nil || !ok {
+				return err
+			}
+			found = true
+			if embedded {
+				object, ok := child.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("when parsing %s: embed tag is only supported on structures", displayName(name))
+				}
+				for k, v := range object {
+					res[k] = v
+				}
+				return nil
+			}
+			res[fieldName] = child
+			return nil
+		})
+		return res, found, err
+	case reflect.Slice, reflect.Array:
+		res := make([]interface{}, tValue.Len())
+		found := false
+		for i := 0; i < tValue.Len(); i++ {
+			child, ok, err := toJSONTree(tValue.Index(i), appendName(name, strconv.Itoa(i)), visibility, q, publicOnly)
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				found = true
+			} else if child == nil {
+				child = map[string]interface{}{}
+			}
+			res[i] = child
+		}
+		return res, found, nil
+	case reflect.Map:
+		if tValue.Type().Key().Kind() != reflect.String {
+			return nil, false, fmt.Errorf("when parsing %s: only maps keyed by strings are supported", displayName(name))
+		}
+		res := make(map[string]interface{}, tValue.Len())
+		found := false
+		iter := tValue.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			child, ok, err := toJSONTree(iter.Value(), appendName(name, key), visibility, q, publicOnly)
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				found = true
+				res[key] = child
+			}
+		}
+		return res, found, nil
+	}
+	return nil, false, nil
+}
+
+// visitFields calls handler on each exported field of the struct tValue, following the gnark struct tags
+// semantic (see parser.Tag)
+func visitFields(tValue reflect.Value, parentVisibility compiled.Visibility, handler func(name string, visibility compiled.Visibility, f reflect.Value, embedded bool) error) error {
+	for i := 0; i < tValue.NumField(); i++ {
+		field := tValue.Type().Field(i)
+		if field.PkgPath != "" {
+			continue // unexported field
+		}
+		name, visibility, omit, err := parser.FieldInfo(field, parentVisibility)
+		if err != nil {
+			return err
+		}
+		if omit {
+			continue
+		}
+		if err := handler(name, visibility, tValue.Field(i), name == ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseValue returns the big.Int represented by a JSON value: a number,
+// a decimal string or an hexadecimal string prefixed with 0x
+func parseValue(value interface{}, q *big.Int) (*big.Int, error) {
+	var s string
+	switch v := value.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	default:
+		return nil, errors.New("expected a decimal or hexadecimal string")
+	}
+	digits, base := s, 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		digits, base = s[2:], 16
+	}
+	var res big.Int
+	if _, ok := res.SetString(digits, base); !ok {
+		return nil, fmt.Errorf("invalid value %q", s)
+	}
+	if res.Sign() < 0 || res.Cmp(q) >= 0 {
+		return nil, fmt.Errorf("value %s is not in the scalar field", s)
+	}
+	return &res, nil
+}
+
+// toBigInt converts an assigned value to a big.Int, see frontend.FromInterface
+func toBigInt(value interface{}) (res *big.Int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	v := frontend.FromInterface(value)
+	return &v, nil
+}
+
+// modulus returns the modulus of the scalar field of curveID
+func modulus(curveID ecc.ID) (*big.Int, error) {
+	switch curveID {
+	case ecc.BN254:
+		return frbn254.Modulus(), nil
+	case ecc.BLS12_377:
+		return frbls12377.Modulus(), nil
+	case ecc.BLS12_381:
+		return frbls12381.Modulus(), nil
+	case ecc.BW6_761:
+		return frbw6761.Modulus(), nil
+	case ecc.BLS24_315:
+		return frbls24315.Modulus(), nil
+	case ecc.BW6_672:
+		return frbw6672.Modulus(), nil
+	case ecc.BW6_633:
+		return frbw6633.Modulus(), nil
+	default:
+		return nil, errors.New("unknown curve id")
+	}
+}
+
+func appendName(baseName, name string) string {
+	if baseName == "" {
+		return name
+	}
+	if name == "" {
+		return baseName
+	}
+	return baseName + "_" + name
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "witness"
+	}
+	return name
+}
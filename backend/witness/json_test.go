@@ -0,0 +1,206 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	X, Y frontend.Variable
+}
+
+type jsonCircuit struct {
+	A      frontend.Variable `gnark:"a,public"`
+	B      [2]frontend.Variable
+	P      point `gnark:",public"`
+	Q      [2]point
+	Inner  point             `gnark:",embed"`
+	Hidden frontend.Variable `gnark:"-"`
+}
+
+func (circuit *jsonCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	return nil
+}
+
+func assignJSONCircuit(c *jsonCircuit) {
+	c.A.Assign(1)
+	c.B[0].Assign(2)
+	c.B[1].Assign(3)
+	c.P.X.Assign(4)
+	c.P.Y.Assign(5)
+	c.Q[0].X.Assign(6)
+	c.Q[0].Y.Assign(7)
+	c.Q[1].X.Assign(8)
+	c.Q[1].Y.Assign(9)
+	c.Inner.X.Assign(10)
+	c.Inner.Y.Assign(11)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	assert := require.New(t)
+
+	curves := []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BLS24_315, ecc.BW6_633, ecc.BW6_672}
+
+	for _, curve := range curves {
+		var witness jsonCircuit
+		assignJSONCircuit(&witness)
+
+		// full witness
+		var buf bytes.Buffer
+		assert.NoError(WriteFullJSON(&buf, curve, &witness))
+
+		var decoded jsonCircuit
+		assert.NoError(ReadFullJSON(&buf, curve, &decoded))
+
+		var expected, got bytes.Buffer
+		_, err := WriteFullTo(&expected, curve, &witness)
+		assert.NoError(err)
+		_, err = WriteFullTo(&got, curve, &decoded)
+		assert.NoError(err)
+		assert.Equal(expected.Bytes(), got.Bytes(), "full witness mismatch on "+curve.String())
+
+		// public witness
+		buf.Reset()
+		assert.NoError(WritePublicJSON(&buf, curve, &witness))
+
+		var decodedPublic jsonCircuit
+		assert.NoError(ReadPublicJSON(&buf, curve, &decodedPublic))
+
+		expected.Reset()
+		got.Reset()
+		_, err = WritePublicTo(&expected, curve, &witness)
+		assert.NoError(err)
+		_, err = WritePublicTo(&got, curve, &decodedPublic)
+		assert.NoError(err)
+		assert.Equal(expected.Bytes(), got.Bytes(), "public witness mismatch on "+curve.String())
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	assert := require.New(t)
+
+	var witness jsonCircuit
+	assignJSONCircuit(&witness)
+
+	var buf bytes.Buffer
+	assert.NoError(WritePublicJSON(&buf, ecc.BN254, &witness))
+	assert.JSONEq(`{"a": "1", "P": {"X": "4", "Y": "5"}}`, buf.String())
+
+	// hex and decimal values are accepted
+	const full = `{
+		"a": "0x1", "B": ["2", "0x03"], "P": {"X": "4", "Y": "5"},
+		"Q": [{"X": "6", "Y": "7"}, {"X": "8", "Y": "9"}],
+		"X": "10", "Y": "0xb"
+	}`
+	var decoded jsonCircuit
+	assert.NoError(ReadFullJSON(strings.NewReader(full), ecc.BN254, &decoded))
+
+	var expected, got bytes.Buffer
+	_, err := WriteFullTo(&expected, ecc.BN254, &witness)
+	assert.NoError(err)
+	_, err = WriteFullTo(&got, ecc.BN254, &decoded)
+	assert.NoError(err)
+	assert.Equal(expected.Bytes(), got.Bytes())
+}
+
+func TestJSONInvalid(t *testing.T) {
+	assert := require.New(t)
+
+	invalid := map[string]string{
+		"missing value":     `{"a": "1", "B": ["2", "3"], "P": {"X": "4"}, "Q": [{"X": "6", "Y": "7"}, {"X": "8", "Y": "9"}], "X": "10", "Y": "11"}`,
+		"unknown field":     `{"a": "1", "B": ["2", "3"], "P": {"X": "4", "Y": "5"}, "Q": [{"X": "6", "Y": "7"}, {"X": "8", "Y": "9"}], "X": "10", "Y": "11", "Hidden": "1"}`,
+		"wrong array size":  `{"a": "1", "B": ["2"], "P": {"X": "4", "Y": "5"}, "Q": [{"X": "6", "Y": "7"}, {"X": "8", "Y": "9"}], "X": "10", "Y": "11"}`,
+		"out of range":      `{"a": "-1", "B": ["2", "3"], "P": {"X": "4", "Y": "5"}, "Q": [{"X": "6", "Y": "7"}, {"X": "8", "Y": "9"}], "X": "10", "Y": "11"}`,
+		"modulus":           `{"a": "21888242871839275222246405745257275088548364400416034343698204186575808495617", "B": ["2", "3"], "P": {"X": "4", "Y": "5"}, "Q": [{"X": "6", "Y": "7"}, {"X": "8", "Y": "9"}], "X": "10", "Y": "11"}`,
+		"invalid hex value": `{"a": "0xg", "B": ["2", "3"], "P": {"X": "4", "Y": "5"}, "Q": [{"X": "6", "Y": "7"}, {"X": "8", "Y": "9"}], "X": "10", "Y": "11"}`,
+		"object for array":  `{"a": "1", "B": {"X": "2"}, "P": {"X": "4", "Y": "5"}, "Q": [{"X": "6", "Y": "7"}, {"X": "8", "Y": "9"}], "X": "10", "Y": "11"}`,
+	}
+
+	for name, data := range invalid {
+		var decoded jsonCircuit
+		assert.Error(ReadFullJSON(strings.NewReader(data), ecc.BN254, &decoded), name)
+	}
+
+	// secret values are not expected in a public witness
+	var decoded jsonCircuit
+	assert.Error(ReadPublicJSON(strings.NewReader(`{"a": "1", "P": {"X": "4", "Y": "5"}, "X": "10"}`), ecc.BN254, &decoded))
+
+	// unknown curve
+	assert.Error(ReadFullJSON(strings.NewReader(invalid["missing value"]), ecc.UNKNOWN, &decoded))
+}
+
+type mapCircuit struct {
+	M map[string]frontend.Variable `gnark:",public"`
+	P *point
+}
+
+func (circuit *mapCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	return nil
+}
+
+func newMapCircuit() *mapCircuit {
+	return &mapCircuit{
+		M: map[string]frontend.Variable{"b": {}, "a": {}},
+		P: &point{},
+	}
+}
+
+func TestJSONMapAndPointer(t *testing.T) {
+	assert := require.New(t)
+
+	witness, err := New(newMapCircuit(), map[string]interface{}{
+		"M": map[string]interface{}{"a": 1, "b": 2},
+		"P": map[string]interface{}{"X": 3, "Y": 4},
+	})
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	assert.NoError(WriteFullJSON(&buf, ecc.BN254, witness))
+	assert.JSONEq(`{"M": {"a": "1", "b": "2"}, "P": {"X": "3", "Y": "4"}}`, buf.String())
+
+	decoded := newMapCircuit()
+	assert.NoError(ReadFullJSON(&buf, ecc.BN254, decoded))
+
+	// map entries are ordered by key in the binary witness
+	var expected, got bytes.Buffer
+	_, err = WriteFullTo(&expected, ecc.BN254, witness)
+	assert.NoError(err)
+	_, err = WriteFullTo(&got, ecc.BN254, decoded)
+	assert.NoError(err)
+	assert.Equal(expected.Bytes(), got.Bytes())
+
+	// the template map is not shared with the witness
+	clone := Clone(witness).(*mapCircuit)
+	Reset(clone)
+	assert.Nil(frontend.GetAssignedValue(clone.M["a"]))
+	assert.NotNil(frontend.GetAssignedValue(witness.(*mapCircuit).M["a"]))
+
+	// unknown map key
+	_, err = New(newMapCircuit(), map[string]interface{}{
+		"M": map[string]interface{}{"a": 1, "b": 2, "c": 3},
+		"P": map[string]interface{}{"X": 3, "Y": 4},
+	})
+	assert.Error(err)
+
+	// nil pointer
+	assert.Error(ReadFullJSON(strings.NewReader(`{"M": {"a": "1", "b": "2"}}`), ecc.BN254, &mapCircuit{M: map[string]frontend.Variable{"a": {}, "b": {}}}))
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/backend/compiled"
+	"github.com/consensys/gnark/internal/parser"
+)
+
+// WritePublicSnarkJS encodes the public witness in the snarkjs public.json format: a JSON array of decimal strings,
+// one per public variable, in the binary witness order
+func WritePublicSnarkJS(w io.Writer, curveID ecc.ID, publicWitness frontend.Circuit) error {
+	q, err := modulus(curveID)
+	if err != nil {
+		return err
+	}
+
+	values := []string{}
+	var handler parser.LeafHandler = func(visibility compiled.Visibility, name string, tInput reflect.Value) error {
+		if visibility != compiled.Public {
+			return nil
+		}
+		v := tInput.Interface().(frontend.Variable)
+		value := frontend.GetAssignedValue(v)
+		if value == nil {
+			return fmt.Errorf("when parsing variable %s: missing assignment", name)
+		}
+		b, err := toBigInt(value)
+		if err != nil {
+			return fmt.Errorf("when parsing variable %s: %v", name, err)
+		}
+		values = append(values, b.Mod(b, q).String())
+		return nil
+	}
+	if err := parser.Visit(publicWitness, "", compiled.Unset, handler, tVariable); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	return enc.Encode(values)
+}
+
+// ReadPublicSnarkJS decodes a public witness in the snarkjs public.json format and assigns the public variables
+// of publicWitness, in the binary witness order
+func ReadPublicSnarkJS(r io.Reader, curveID ecc.ID, publicWitness frontend.Circuit) error {
+	q, err := modulus(curveID)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var values []interface{}
+	if err := dec.Decode(&values); err != nil {
+		return err
+	}
+
+	i := 0
+	var handler parser.LeafHandler = func(visibility compiled.Visibility, name string, tInput reflect.Value) error {
+		if visibility != compiled.Public {
+			return nil
+		}
+		if i >= len(values) {
+			return errors.New("too few public values")
+		}
+		if !tInput.CanSet() {
+			return fmt.Errorf("when parsing variable %s: can't set value", name)
+		}
+		v := tInput.Addr().Interface().(*frontend.Variable)
+		if frontend.GetAssignedValue(*v) != nil {
+			return fmt.Errorf("when parsing variable %s: already assigned", name)
+		}
+		value, err := parseValue(values[i], q)
+		if err != nil {
+			return fmt.Errorf("when parsing variable %s: %v", name, err)
+		}
+		v.Assign(value)
+		i++
+		return nil
+	}
+	if err := parser.Visit(publicWitness, "", compiled.Unset, handler, tVariable); err != nil {
+		return err
+	}
+	if i != len(values) {
+		return fmt.Errorf("expected %d public values, got %d", i, len(values))
+	}
+
+	return nil
+}
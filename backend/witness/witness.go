@@ -14,6 +14,9 @@
 
 // Package witness provides serialization helpers to encode a witness into a []byte.
 //
+// It also provides helpers to build a witness from native Go values (see New), and to reuse
+// a circuit structure for many witnesses (see Clone and Reset).
+//
 // Binary protocol
 //
 // 	Full witness     ->  [uint32(nbElements) | publicVariables | secretVariables]
@@ -25,7 +28,8 @@
 //
 // Ordering
 //
-// First, `publicVariables`, then `secretVariables`. Each subset is ordered from the order of definition in the circuit structure.
+// First, `publicVariables`, then `secretVariables`. Each subset is ordered from the order of definition in the circuit structure
+// (map entries are ordered by key).
 // For example, with this circuit on `ecc.BN254`
 //
 // 	type Circuit struct {
@@ -38,6 +42,17 @@
 // 	* `[uint32(3)|bytes(Y)|bytes(X)|bytes(Z)]`
 // 	* Hex representation with values `Y = 35`, `X = 3`, `Z = 2`
 // 	`00000003000000000000000000000000000000000000000000000000000000000000002300000000000000000000000000000000000000000000000000000000000000030000000000000000000000000000000000000000000000000000000000000002`
+//
+// JSON protocol
+//
+// A witness can also be encoded as a JSON object keyed by the circuit field names (or gnark tag names).
+// Nested structures and maps are encoded as JSON objects, slices and arrays as JSON arrays, and each variable
+// as a string, holding either a decimal or an hexadecimal (prefixed with 0x) value in the scalar field.
+// The public witness contains only the public variables. With the circuit above, a valid full witness would be:
+// 	{"X": "3", "Y": "35", "Z": "0x2"}
+//
+// The public witness can also be encoded in the snarkjs public.json format (see WritePublicSnarkJS), a JSON array
+// of decimal strings in the binary protocol order: ["35"].
 package witness
 
 import (
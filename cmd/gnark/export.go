@@ -0,0 +1,45 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+func runExportSolidity(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("export-solidity", stderr)
+	vkPath := fs.String("vk", "", "verifying key file (required)")
+	output := fs.String("o", "", "output solidity file (required)")
+	if err := fs.parse(args, "vk", "o"); err != nil {
+		return err
+	}
+	if fs.backend.id != backend.GROTH16 {
+		return errors.New("solidity export is only supported with groth16")
+	}
+	if fs.curve.id != ecc.BN254 {
+		return errors.New("solidity export is only supported on bn254")
+	}
+
+	vk := groth16.NewVerifyingKey(fs.curve.id)
+	if err := readFile(*vkPath, vk); err != nil {
+		return err
+	}
+	return createFile(*output, vk.ExportSolidity)
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/consensys/gnark/backend"
+)
+
+func runInspect(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("inspect", stderr)
+	csPath := fs.String("cs", "", "constraint system file (required)")
+	if err := fs.parse(args, "cs"); err != nil {
+		return err
+	}
+
+	ccs, err := fs.readCS(*csPath)
+	if err != nil {
+		return err
+	}
+	internal, secret, public := ccs.GetNbVariables()
+	if fs.backend.id == backend.GROTH16 {
+		public-- // the ONE_WIRE is not an input of the circuit
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "curve\t%s\n", ccs.CurveID())
+	fmt.Fprintf(w, "backend\t%s\n", fs.backend.id)
+	fmt.Fprintf(w, "constraints\t%d\n", ccs.GetNbConstraints())
+	fmt.Fprintf(w, "public variables\t%d\n", public)
+	fmt.Fprintf(w, "secret variables\t%d\n", secret)
+	fmt.Fprintf(w, "internal variables\t%d\n", internal)
+	fmt.Fprintf(w, "coefficients\t%d\n", ccs.GetNbCoefficients())
+	return w.Flush()
+}
@@ -0,0 +1,282 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command gnark operates on serialized gnark objects: constraint systems, keys, witnesses and proofs.
+//
+// Circuits are compiled in Go (see frontend.Compile) and the resulting constraint system is serialized
+// with its WriteTo method. Serialized objects don't encode the curve nor the proof system they were
+// built for: they are provided with the -curve (default bn254) and -backend (default groth16) flags.
+//
+// Usage
+//
+// 	gnark inspect -cs circuit.cs
+// 	gnark setup -cs circuit.cs -pk circuit.pk -vk circuit.vk
+// 	gnark prove -cs circuit.cs -pk circuit.pk -witness witness.json -proof proof.bin
+// 	gnark verify -vk circuit.vk -proof proof.bin -public public.json
+// 	gnark export-solidity -vk circuit.vk -o Verifier.sol
+//
+// PLONK setup, prove and verify need a KZG SRS (-srs flag). If the file given to setup doesn't exist,
+// a SRS is generated and written to it; it is NOT suitable for production, where a SRS generated through
+// MPC should be used.
+//
+// Witnesses
+//
+// Witness files ending with .json are JSON encoded, as an object holding the public and secret values,
+// each one in the binary witness order (see package backend/witness):
+// 	{"public": ["35"], "secret": ["3"]}
+// A public witness holds only the "public" array, or is the array itself (snarkjs public.json format).
+// Other witness files use the binary witness protocol (see witness.WriteFullTo and witness.WritePublicTo).
+//
+// Groth16 proofs whose file name ends with .json are encoded in the snarkjs proof.json format. PLONK proofs
+// have no JSON encoding, and a proof file ending with .json is rejected.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+)
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{"inspect", "print the curve, backend, and variable and constraint counts of a constraint system", runInspect},
+	{"setup", "run the setup of a constraint system and write the proving and verifying keys", runSetup},
+	{"prove", "compute a proof from a constraint system, a proving key and a full witness", runProve},
+	{"verify", "verify a proof against a verifying key and a public witness", runVerify},
+	{"export-solidity", "write a solidity verifier contract from a groth16 verifying key (bn254 only)", runExportSolidity},
+}
+
+// errUsage is returned when the command line is invalid; the usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+// errPlonkJSONProof is returned when a plonk proof file ends with .json
+var errPlonkJSONProof = errors.New("plonk proofs have no JSON encoding, use a binary proof file")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if err != errUsage {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		usage(stderr)
+		return errUsage
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			if err := c.run(args[1:], stdout, stderr); err != flag.ErrHelp {
+				return err
+			}
+			return nil
+		}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		usage(stdout)
+		return nil
+	}
+	fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+	usage(stderr)
+	return errUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: gnark <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", c.name, c.description)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "run 'gnark <command> -h' for the flags of a command")
+}
+
+// curves lists the curves supported by gnark, in the order they are displayed
+var curves = []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BLS24_315, ecc.BW6_761, ecc.BW6_633, ecc.BW6_672}
+
+// curveFlag is a flag.Value parsing a curve name, as returned by ecc.ID.String() ("bls12-381" is also accepted)
+type curveFlag struct {
+	id ecc.ID
+}
+
+func (f *curveFlag) String() string {
+	if f.id == ecc.UNKNOWN {
+		return ""
+	}
+	return f.id.String()
+}
+
+func (f *curveFlag) Set(s string) error {
+	s = strings.ReplaceAll(strings.ToLower(s), "-", "_")
+	for _, id := range curves {
+		if id.String() == s {
+			f.id = id
+			return nil
+		}
+	}
+	names := make([]string, len(curves))
+	for i, id := range curves {
+		names[i] = id.String()
+	}
+	return fmt.Errorf("unknown curve, expected one of %s", strings.Join(names, ", "))
+}
+
+// backendFlag is a flag.Value parsing a proof system name ("groth16" or "plonk")
+type backendFlag struct {
+	id backend.ID
+}
+
+func (f *backendFlag) String() string {
+	return f.id.String()
+}
+
+func (f *backendFlag) Set(s string) error {
+	for _, id := range backend.Implemented() {
+		if id.String() == strings.ToLower(s) {
+			f.id = id
+			return nil
+		}
+	}
+	return errors.New("unknown backend, expected groth16 or plonk")
+}
+
+// flagSet wraps a flag.FlagSet with the flags shared by all commands
+type flagSet struct {
+	*flag.FlagSet
+	curve   curveFlag
+	backend backendFlag
+}
+
+func newFlagSet(name string, stderr io.Writer) *flagSet {
+	fs := &flagSet{
+		FlagSet: flag.NewFlagSet(name, flag.ContinueOnError),
+		curve:   curveFlag{ecc.BN254},
+		backend: backendFlag{backend.GROTH16},
+	}
+	fs.SetOutput(stderr)
+	fs.Var(&fs.curve, "curve", "elliptic curve")
+	fs.Var(&fs.backend, "backend", "proof system (groth16 or plonk)")
+	return fs
+}
+
+// parse parses the command line and checks that the required flags are set
+//
+// it returns flag.ErrHelp if the help was requested, and errUsage if the command line is invalid
+func (fs *flagSet) parse(args []string, required ...string) error {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintf(fs.Output(), "unexpected argument %q\n", fs.Arg(0))
+		fs.Usage()
+		return errUsage
+	}
+	for _, name := range required {
+		if fs.Lookup(name).Value.String() == "" {
+			fmt.Fprintf(fs.Output(), "missing required flag -%s\n", name)
+			fs.Usage()
+			return errUsage
+		}
+	}
+	return nil
+}
+
+// readCS reads a constraint system compiled for the curve and backend set in fs
+func (fs *flagSet) readCS(path string) (frontend.CompiledConstraintSystem, error) {
+	var ccs frontend.CompiledConstraintSystem
+	switch fs.backend.id {
+	case backend.GROTH16:
+		ccs = groth16.NewCS(fs.curve.id)
+	case backend.PLONK:
+		ccs = plonk.NewCS(fs.curve.id)
+	}
+	if err := readFile(path, ccs); err != nil {
+		return nil, err
+	}
+	return ccs, nil
+}
+
+// readFile opens path and decodes its content into o
+func readFile(path string, o io.ReaderFrom) error {
+	return decodeFile(path, func(r io.Reader) error {
+		_, err := o.ReadFrom(r)
+		return err
+	})
+}
+
+// decodeFile opens path and decodes its content with read
+func decodeFile(path string, read func(r io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := read(bufio.NewReader(f)); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// writeFile creates path and encodes o into it
+func writeFile(path string, o io.WriterTo) error {
+	return createFile(path, func(w io.Writer) error {
+		_, err := o.WriteTo(w)
+		return err
+	})
+}
+
+// createFile creates path and writes its content with write
+func createFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// isJSON returns true if the file at path is JSON encoded, based on its extension
+func isJSON(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
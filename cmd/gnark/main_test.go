@@ -0,0 +1,137 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCLI(t *testing.T) {
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_381} {
+		for _, backendID := range backend.Implemented() {
+			t.Run(curve.String()+"/"+backendID.String(), func(t *testing.T) {
+				testCLI(t, curve, backendID)
+			})
+		}
+	}
+}
+
+func testCLI(t *testing.T, curve ecc.ID, backendID backend.ID) {
+	assert := require.New(t)
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	var circuit cubic.Circuit
+	ccs, err := frontend.Compile(curve, backendID, &circuit)
+	assert.NoError(err)
+	assert.NoError(writeFile(path("cubic.cs"), ccs))
+
+	var good, bad cubic.Circuit
+	good.X.Assign(3)
+	good.Y.Assign(35)
+	bad.X.Assign(3)
+	bad.Y.Assign(36)
+	var buf bytes.Buffer
+	_, err = witness.WriteFullTo(&buf, curve, &good)
+	assert.NoError(err)
+	assert.NoError(ioutil.WriteFile(path("witness.bin"), buf.Bytes(), 0600))
+	buf.Reset()
+	_, err = witness.WritePublicTo(&buf, curve, &good)
+	assert.NoError(err)
+	assert.NoError(ioutil.WriteFile(path("public.bin"), buf.Bytes(), 0600))
+	assert.NoError(ioutil.WriteFile(path("witness.json"), []byte(`{"public": ["35"], "secret": ["3"]}`), 0600))
+	assert.NoError(ioutil.WriteFile(path("public.json"), []byte(`{"public": ["35"]}`), 0600))
+	assert.NoError(ioutil.WriteFile(path("snarkjs.json"), []byte(`["35"]`), 0600))
+	assert.NoError(ioutil.WriteFile(path("bad.json"), []byte(`["36"]`), 0600))
+
+	cli := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		args = append(args, "-curve", curve.String(), "-backend", backendID.String())
+		err := run(args, &stdout, &stderr)
+		return stdout.String(), err
+	}
+
+	out, err := cli("inspect", "-cs", path("cubic.cs"))
+	assert.NoError(err)
+	assert.Contains(out, curve.String())
+	assert.Contains(out, backendID.String())
+	assert.Regexp(`public variables\s+1\n`, out)
+	assert.Regexp(`secret variables\s+1\n`, out)
+
+	_, err = cli("setup", "-cs", path("cubic.cs"), "-pk", path("cubic.pk"), "-vk", path("cubic.vk"), "-srs", path("kzg.srs"))
+	assert.NoError(err)
+
+	proofs := []string{"proof.bin"}
+	if backendID == backend.GROTH16 {
+		proofs = append(proofs, "proof.json")
+	} else {
+		_, err = cli("prove", "-cs", path("cubic.cs"), "-pk", path("cubic.pk"), "-witness", path("witness.bin"), "-proof", path("proof.json"), "-srs", path("kzg.srs"))
+		assert.ErrorIs(err, errPlonkJSONProof)
+		_, err = cli("verify", "-vk", path("cubic.vk"), "-proof", path("proof.json"), "-public", path("public.bin"), "-srs", path("kzg.srs"))
+		assert.ErrorIs(err, errPlonkJSONProof)
+	}
+	for _, proof := range proofs {
+		for _, w := range []string{"witness.bin", "witness.json"} {
+			_, err = cli("prove", "-cs", path("cubic.cs"), "-pk", path("cubic.pk"), "-witness", path(w), "-proof", path(proof), "-srs", path("kzg.srs"))
+			assert.NoError(err, w)
+
+			for _, public := range []string{"public.bin", "public.json", "snarkjs.json"} {
+				out, err = cli("verify", "-vk", path("cubic.vk"), "-proof", path(proof), "-public", path(public), "-srs", path("kzg.srs"))
+				assert.NoError(err, public)
+				assert.True(strings.HasPrefix(out, "proof is valid"))
+			}
+			_, err = cli("verify", "-vk", path("cubic.vk"), "-proof", path(proof), "-public", path("bad.json"), "-srs", path("kzg.srs"))
+			assert.Error(err, "verifying with a wrong public witness should fail")
+		}
+	}
+
+	_, err = cli("export-solidity", "-vk", path("cubic.vk"), "-o", path("Verifier.sol"))
+	if curve == ecc.BN254 && backendID == backend.GROTH16 {
+		assert.NoError(err)
+		sol, err := ioutil.ReadFile(path("Verifier.sol"))
+		assert.NoError(err)
+		assert.Contains(string(sol), "contract Verifier")
+	} else {
+		assert.Error(err)
+	}
+}
+
+func TestCLIUsage(t *testing.T) {
+	assert := require.New(t)
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(errUsage, run(nil, &stdout, &stderr))
+	assert.Equal(errUsage, run([]string{"unknown"}, &stdout, &stderr))
+	assert.Equal(errUsage, run([]string{"inspect"}, &stdout, &stderr), "missing -cs")
+	assert.Equal(errUsage, run([]string{"inspect", "-cs", "a", "-curve", "secp256k1"}, &stdout, &stderr))
+	assert.NoError(run([]string{"inspect", "-h"}, &stdout, &stderr))
+
+	stdout.Reset()
+	assert.NoError(run([]string{"help"}, &stdout, &stderr))
+	for _, c := range commands {
+		assert.Contains(stdout.String(), c.name)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+)
+
+func runProve(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("prove", stderr)
+	csPath := fs.String("cs", "", "constraint system file (required)")
+	pkPath := fs.String("pk", "", "proving key file (required)")
+	witnessPath := fs.String("witness", "", "full witness file, binary or .json (required)")
+	proofPath := fs.String("proof", "", "output proof file (required)")
+	srsPath := fs.String("srs", "", "KZG SRS file (plonk only)")
+	force := fs.Bool("force", false, "compute the proof even if the witness doesn't satisfy the constraints")
+	if err := fs.parse(args, "cs", "pk", "witness", "proof"); err != nil {
+		return err
+	}
+
+	ccs, err := fs.readCS(*csPath)
+	if err != nil {
+		return err
+	}
+
+	var opts []backend.ProverOption
+	if *force {
+		opts = append(opts, backend.IgnoreSolverError())
+	}
+
+	switch fs.backend.id {
+	case backend.GROTH16:
+		pk := groth16.NewProvingKey(fs.curve.id)
+		if err := readFile(*pkPath, pk); err != nil {
+			return err
+		}
+		var proof groth16.Proof
+		if isJSON(*witnessPath) {
+			_, nbSecret, nbPublic := ccs.GetNbVariables()
+			w, err := readFullJSON(*witnessPath, fs.curve.id, nbPublic-1, nbSecret) // - 1 for ONE_WIRE
+			if err != nil {
+				return err
+			}
+			proof, err = groth16.Prove(ccs, pk, w, opts...)
+			if err != nil {
+				return err
+			}
+		} else {
+			f, err := os.Open(*witnessPath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if proof, err = groth16.ReadAndProve(ccs, pk, bufio.NewReader(f), opts...); err != nil {
+				return err
+			}
+		}
+		if isJSON(*proofPath) {
+			return createFile(*proofPath, proof.ExportSnarkJS)
+		}
+		return writeFile(*proofPath, proof)
+
+	case backend.PLONK:
+		if isJSON(*proofPath) {
+			return errPlonkJSONProof
+		}
+		srs, err := fs.readSRS(*srsPath)
+		if err != nil {
+			return err
+		}
+		pk := plonk.NewProvingKey(fs.curve.id)
+		if err := readFile(*pkPath, pk); err != nil {
+			return err
+		}
+		if err := pk.InitKZG(srs); err != nil {
+			return err
+		}
+		var proof plonk.Proof
+		if isJSON(*witnessPath) {
+			_, nbSecret, nbPublic := ccs.GetNbVariables()
+			w, err := readFullJSON(*witnessPath, fs.curve.id, nbPublic, nbSecret)
+			if err != nil {
+				return err
+			}
+			proof, err = plonk.Prove(ccs, pk, w, opts...)
+			if err != nil {
+				return err
+			}
+		} else {
+			f, err := os.Open(*witnessPath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if proof, err = plonk.ReadAndProve(ccs, pk, bufio.NewReader(f), opts...); err != nil {
+				return err
+			}
+		}
+		return writeFile(*proofPath, proof)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+)
+
+func runSetup(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("setup", stderr)
+	csPath := fs.String("cs", "", "constraint system file (required)")
+	pkPath := fs.String("pk", "", "output proving key file (required)")
+	vkPath := fs.String("vk", "", "output verifying key file (required)")
+	srsPath := fs.String("srs", "", "KZG SRS file (plonk only), generated if it doesn't exist")
+	if err := fs.parse(args, "cs", "pk", "vk"); err != nil {
+		return err
+	}
+
+	ccs, err := fs.readCS(*csPath)
+	if err != nil {
+		return err
+	}
+
+	var pk, vk io.WriterTo
+	switch fs.backend.id {
+	case backend.GROTH16:
+		pk, vk, err = groth16.Setup(ccs)
+	case backend.PLONK:
+		var srs kzg.SRS
+		if srs, err = fs.readOrCreateSRS(*srsPath, ccs, stderr); err != nil {
+			return err
+		}
+		pk, vk, err = plonk.Setup(ccs, srs)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(*pkPath, pk); err != nil {
+		return err
+	}
+	return writeFile(*vkPath, vk)
+}
+
+// readOrCreateSRS reads the KZG SRS at path, or, if the file doesn't exist, creates a SRS sized for ccs
+// and writes it at path
+func (fs *flagSet) readOrCreateSRS(path string, ccs frontend.CompiledConstraintSystem, stderr io.Writer) (kzg.SRS, error) {
+	if path == "" {
+		return nil, errors.New("plonk needs a KZG SRS, set with -srs")
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fs.readSRS(path)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	fmt.Fprintf(stderr, "warning: %s doesn't exist, generating a KZG SRS which is NOT suitable for production\n", path)
+	srs, err := plonk.NewSRS(ccs)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFile(path, srs); err != nil {
+		return nil, err
+	}
+	return srs, nil
+}
+
+// readSRS reads the KZG SRS at path
+func (fs *flagSet) readSRS(path string) (kzg.SRS, error) {
+	if path == "" {
+		return nil, errors.New("plonk needs a KZG SRS, set with -srs")
+	}
+	srs := plonk.NewKZGSRS(fs.curve.id)
+	if err := readFile(path, srs); err != nil {
+		return nil, err
+	}
+	return srs, nil
+}
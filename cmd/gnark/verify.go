@@ -0,0 +1,114 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+)
+
+func runVerify(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("verify", stderr)
+	vkPath := fs.String("vk", "", "verifying key file (required)")
+	proofPath := fs.String("proof", "", "proof file (required)")
+	publicPath := fs.String("public", "", "public witness file, binary or .json (required)")
+	srsPath := fs.String("srs", "", "KZG SRS file (plonk only)")
+	if err := fs.parse(args, "vk", "proof", "public"); err != nil {
+		return err
+	}
+
+	switch fs.backend.id {
+	case backend.GROTH16:
+		vk := groth16.NewVerifyingKey(fs.curve.id)
+		if err := readFile(*vkPath, vk); err != nil {
+			return err
+		}
+		proof := groth16.NewProof(fs.curve.id)
+		if isJSON(*proofPath) {
+			if err := decodeFile(*proofPath, proof.ImportSnarkJS); err != nil {
+				return err
+			}
+		} else if err := readFile(*proofPath, proof); err != nil {
+			return err
+		}
+
+		if isJSON(*publicPath) {
+			w, err := readPublicJSON(*publicPath, fs.curve.id, vk.NbPublicWitness())
+			if err != nil {
+				return err
+			}
+			if err := groth16.Verify(proof, vk, w); err != nil {
+				return err
+			}
+		} else {
+			f, err := os.Open(*publicPath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if err := groth16.ReadAndVerify(proof, vk, bufio.NewReader(f)); err != nil {
+				return err
+			}
+		}
+
+	case backend.PLONK:
+		if isJSON(*proofPath) {
+			return errPlonkJSONProof
+		}
+		srs, err := fs.readSRS(*srsPath)
+		if err != nil {
+			return err
+		}
+		vk := plonk.NewVerifyingKey(fs.curve.id)
+		if err := readFile(*vkPath, vk); err != nil {
+			return err
+		}
+		if err := vk.InitKZG(srs); err != nil {
+			return err
+		}
+		proof := plonk.NewProof(fs.curve.id)
+		if err := readFile(*proofPath, proof); err != nil {
+			return err
+		}
+
+		if isJSON(*publicPath) {
+			w, err := readPublicJSON(*publicPath, fs.curve.id, vk.NbPublicWitness())
+			if err != nil {
+				return err
+			}
+			if err := plonk.Verify(proof, vk, w); err != nil {
+				return err
+			}
+		} else {
+			f, err := os.Open(*publicPath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if err := plonk.ReadAndVerify(proof, vk, bufio.NewReader(f)); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Fprintln(stdout, "proof is valid")
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+)
+
+// flatWitness is a witness of any circuit, its variables being listed in the binary witness order
+//
+// It is never compiled: it is used to decode JSON witnesses with the backend/witness package.
+type flatWitness struct {
+	Public []frontend.Variable `gnark:"public,public"`
+	Secret []frontend.Variable `gnark:"secret"`
+}
+
+func (w *flatWitness) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	return errors.New("flatWitness can't be compiled")
+}
+
+// readFullJSON reads a JSON full witness with nbPublic public and nbSecret secret variables
+func readFullJSON(path string, curveID ecc.ID, nbPublic, nbSecret int) (*flatWitness, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	w := &flatWitness{
+		Public: make([]frontend.Variable, nbPublic),
+		Secret: make([]frontend.Variable, nbSecret),
+	}
+	if err := witness.ReadFullJSON(bufio.NewReader(f), curveID, w); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return w, nil
+}
+
+// readPublicJSON reads a JSON public witness with nbPublic variables, either as a {"public": [...]} object,
+// or in the snarkjs public.json format
+func readPublicJSON(path string, curveID ecc.ID, nbPublic int) (*flatWitness, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &flatWitness{Public: make([]frontend.Variable, nbPublic)}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] == '[' {
+		err = witness.ReadPublicSnarkJS(bytes.NewReader(data), curveID, w)
+	} else {
+		err = witness.ReadPublicJSON(bytes.NewReader(data), curveID, w)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return w, nil
+}
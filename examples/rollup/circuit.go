@@ -164,7 +164,7 @@ func verifyTransferSignature(cs *frontend.ConstraintSystem, t TransferConstraint
 	hFunc.Write(t.Nonce, t.Amount, t.SenderPubKey.A.X, t.SenderPubKey.A.Y, t.ReceiverPubKey.A.X, t.ReceiverPubKey.A.Y)
 	htransfer := hFunc.Sum()
 
-	err := eddsa.Verify(cs, t.Signature, htransfer, t.SenderPubKey)
+	err := eddsa.Verify(cs, &hFunc, t.Signature, t.SenderPubKey, htransfer)
 	if err != nil {
 		return err
 	}
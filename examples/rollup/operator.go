@@ -22,6 +22,7 @@ import (
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
 	"github.com/consensys/gnark/std/accumulator/merkle"
 )
@@ -123,10 +124,12 @@ func (o *Operator) updateState(t Transfer, numTransfer int) error {
 	}
 
 	// set witnesses for the public keys
-	o.witnesses.PublicKeysSender[numTransfer].A.X.Assign(senderAccount.pubKey.A.X)
-	o.witnesses.PublicKeysSender[numTransfer].A.Y.Assign(senderAccount.pubKey.A.Y)
-	o.witnesses.PublicKeysReceiver[numTransfer].A.X.Assign(receiverAccount.pubKey.A.X)
-	o.witnesses.PublicKeysReceiver[numTransfer].A.Y.Assign(receiverAccount.pubKey.A.Y)
+	if err := o.witnesses.PublicKeysSender[numTransfer].Assign(ecc.BN254, senderAccount.pubKey.Bytes()); err != nil {
+		return err
+	}
+	if err := o.witnesses.PublicKeysReceiver[numTransfer].Assign(ecc.BN254, receiverAccount.pubKey.Bytes()); err != nil {
+		return err
+	}
 
 	// set witnesses for the accounts before update
 	o.witnesses.SenderAccountsBefore[numTransfer].Index.Assign(senderAccount.index)
@@ -173,10 +176,9 @@ func (o *Operator) updateState(t Transfer, numTransfer int) error {
 
 	// set witnesses for the transfer
 	o.witnesses.Transfers[numTransfer].Amount.Assign(t.amount)
-	o.witnesses.Transfers[numTransfer].Signature.R.X.Assign(t.signature.R.X)
-	o.witnesses.Transfers[numTransfer].Signature.R.Y.Assign(t.signature.R.Y)
-	o.witnesses.Transfers[numTransfer].Signature.S1.Assign(t.signature.S[:16])
-	o.witnesses.Transfers[numTransfer].Signature.S2.Assign(t.signature.S[16:])
+	if err := o.witnesses.Transfers[numTransfer].Signature.Assign(ecc.BN254, t.signature.Bytes()); err != nil {
+		return err
+	}
 
 	// verifying the signature. The msg is the hash (o.h) of the transfer
 	// nonce || amount || senderpubKey(x&y) || receiverPubkey(x&y)
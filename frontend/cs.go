@@ -27,6 +27,7 @@ import (
 	"strings"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/internal/backend/compiled"
 )
 
@@ -40,10 +41,12 @@ type ConstraintSystem struct {
 	// Variables (aka wires)
 	public struct {
 		variables []Variable       // public inputs
+		names     []string         // names of the public inputs, in the circuit structure
 		booleans  map[int]struct{} // keep track of boolean variables (we constrain them once)
 	}
 	secret struct {
 		variables []Variable       // secret inputs
+		names     []string         // names of the secret inputs, in the circuit structure
 		booleans  map[int]struct{} // keep track of boolean variables (we constrain them once)
 	}
 	internal struct {
@@ -60,10 +63,14 @@ type ConstraintSystem struct {
 	coeffsIDs map[string]int // map to fast check existence of a coefficient (key = coeff.Text(16))
 
 	// debug info
-	logs           []logEntry // list of logs to be printed when solving a circuit. The logs are called with the method Println
-	debugInfo      []logEntry // list of logs storing information about assertions. If an assertion fails, it prints it in a friendly format
+	logs           []logEntry // list of logs to be printed when solving a circuit. The logs are called with the methods Println, Printf and PrintIf
+	debugInfo      []logEntry // list of logs storing information about constraints. If a constraint fails, it prints it in a friendly format
+	coDebugInfo    []int      // index in debugInfo of the debug info of each computational constraint (call stack where it is recorded)
+	asDebugInfo    []int      // index in debugInfo of the debug info of each assertion
 	unsetVariables []logEntry // unset variables. If a variable is unset, the error is caught when compiling the circuit
 
+	callStacks map[callers]int // index in debugInfo of the call stacks of the computational constraints; see addConstraint
+	debug      bool            // record the call stacks of the computational constraints; see WithDebugInfo
 }
 
 // CompiledConstraintSystem ...
@@ -76,11 +83,19 @@ type CompiledConstraintSystem interface {
 	GetNbConstraints() int
 	GetNbCoefficients() int
 
-	// SetLoggerOutput replace existing logger output with provided one
-	SetLoggerOutput(w io.Writer)
-
 	CurveID() ecc.ID
 	FrSize() int
+
+	// SolveWires reads the full witness [public | secret] from fullWitness (see backend/witness.WriteFullTo),
+	// solves the constraint system and returns the values of all the wires, labeled by the name of the inputs
+	// or the call site of the constraint computing the internal wires (recorded with WithDebugInfo).
+	// The wires can be exported with Wires.WriteJSON or Wires.WriteCSV, for instance to compare
+	// the executions of two witnesses (see Wires.Diff).
+	//
+	// If the witness doesn't solve the constraint system, the wires are returned along with the solver error,
+	// which lists all the unsatisfied constraints (see backend.WithDiagnostics); the wires that could not be
+	// solved are marked as such.
+	SolveWires(fullWitness io.Reader, opts ...backend.ProverOption) (backend.Wires, error)
 }
 
 // initialCapacity has quite some impact on frontend performance, especially on large circuits size
@@ -95,6 +110,8 @@ func newConstraintSystem(initialCapacity ...int) ConstraintSystem {
 		coeffsIDs:   make(map[string]int),
 		constraints: make([]compiled.R1C, 0, capacity),
 		assertions:  make([]compiled.R1C, 0),
+		coDebugInfo: make([]int, 0, capacity),
+		callStacks:  make(map[callers]int),
 	}
 
 	cs.coeffs[compiled.CoeffIdZero].SetInt64(0)
@@ -113,13 +130,16 @@ func newConstraintSystem(initialCapacity ...int) ConstraintSystem {
 
 	// by default the circuit is given on public wire equal to 1
 	cs.public.variables[0] = cs.newPublicVariable()
+	cs.public.names[0] = "ONE_WIRE"
 
 	return cs
 }
 
 type logEntry struct {
-	format    string
-	toResolve []compiled.Term
+	format      string
+	toResolve   []compiled.Term
+	caller      string // file.go:line of the log
+	conditional bool   // toResolve[0] is the condition of the log (see PrintIf)
 }
 
 var (
@@ -200,9 +220,39 @@ func (cs *ConstraintSystem) reduce(l compiled.LinearExpression) compiled.LinearE
 
 func (cs *ConstraintSystem) addAssertion(constraint compiled.R1C, debugInfo logEntry) {
 	cs.assertions = append(cs.assertions, constraint)
+	cs.asDebugInfo = append(cs.asDebugInfo, len(cs.debugInfo))
 	cs.debugInfo = append(cs.debugInfo, debugInfo)
 }
 
+// addConstraint records a computational constraint; its debug info is debugInfo if provided (the constraint
+// is then reported like an assertion when it can't be solved). Otherwise, if cs.debug is set, it is the call
+// stack from the caller of addConstraint up to the Define method of the circuit, and it is empty if not
+func (cs *ConstraintSystem) addConstraint(constraint compiled.R1C, debugInfo ...logEntry) {
+	cs.constraints = append(cs.constraints, constraint)
+
+	if len(debugInfo) == 1 {
+		cs.coDebugInfo = append(cs.coDebugInfo, len(cs.debugInfo))
+		cs.debugInfo = append(cs.debugInfo, debugInfo[0])
+		return
+	}
+
+	// the constraints are recorded from a few call sites only, so the debug info
+	// is shared by the constraints with the same call stack (program counters)
+	var pc callers
+	if cs.debug {
+		runtime.Callers(2, pc[:])
+	}
+	id, ok := cs.callStacks[pc]
+	if !ok {
+		stack := strings.Join(formatCallStack(pc[:]), "\n")
+		stack = strings.ReplaceAll(stack, "%", "%%") // the stack is used as a format
+		id = len(cs.debugInfo)
+		cs.debugInfo = append(cs.debugInfo, logEntry{format: stack})
+		cs.callStacks[pc] = id
+	}
+	cs.coDebugInfo = append(cs.coDebugInfo, id)
+}
+
 // coeffID tries to fetch the entry where b is if it exits, otherwise appends b to
 // the list of coeffs and returns the corresponding entry
 func (cs *ConstraintSystem) coeffID(b *big.Int) int {
@@ -244,7 +294,7 @@ func (cs *ConstraintSystem) allocate(v Variable) Variable {
 	if v.visibility == compiled.Unset && len(v.linExp) > 0 {
 		iv := cs.newInternalVariable()
 		one := cs.one()
-		cs.constraints = append(cs.constraints, newR1C(v, one, iv))
+		cs.addConstraint(newR1C(v, one, iv))
 		return iv
 	}
 	return v
@@ -270,6 +320,7 @@ func (cs *ConstraintSystem) newPublicVariable() Variable {
 
 	v := cs.buildVarFromWire(w)
 	cs.public.variables = append(cs.public.variables, v)
+	cs.public.names = append(cs.public.names, "")
 	return v
 }
 
@@ -280,6 +331,7 @@ func (cs *ConstraintSystem) newSecretVariable() Variable {
 
 	v := cs.buildVarFromWire(w)
 	cs.secret.variables = append(cs.secret.variables, v)
+	cs.secret.names = append(cs.secret.names, "")
 	return v
 }
 
@@ -296,7 +348,10 @@ func parseLogValue(input interface{}, name string, handler logValueHandler) {
 	tVariable := reflect.TypeOf(Variable{})
 
 	tValue := reflect.ValueOf(input)
-	if tValue.Kind() == reflect.Ptr {
+	for tValue.Kind() == reflect.Ptr {
+		if tValue.IsNil() {
+			return
+		}
 		tValue = tValue.Elem()
 	}
 	switch tValue.Kind() {
@@ -325,15 +380,35 @@ func parseLogValue(input interface{}, name string, handler logValueHandler) {
 			_name := appendName(name, entry)
 			parseLogValue(value, _name, handler)
 		}
+	case reflect.Map:
+		keys := tValue.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			_name := appendName(name, fmt.Sprint(key))
+			parseLogValue(tValue.MapIndex(key).Interface(), _name, handler)
+		}
 	}
 }
 
+// callers holds the program counters of a call stack, see runtime.Callers
+type callers [10]uintptr
+
 // derived from: https://golang.org/pkg/runtime/#example_Frames
 // we stop when func name == Define as it is where the gnark circuit code should start
 func getCallStack() []string {
 	// Ask runtime.Callers for up to 10 pcs
-	pc := make([]uintptr, 10)
-	n := runtime.Callers(3, pc)
+	var pc callers
+	runtime.Callers(3, pc[:])
+	return formatCallStack(pc[:])
+}
+
+// formatCallStack returns the frames of the call stack pc (as filled by runtime.Callers),
+// up to the Define method of the circuit
+func formatCallStack(pc []uintptr) []string {
+	n := 0
+	for n < len(pc) && pc[n] != 0 {
+		n++
+	}
 	if n == 0 {
 		// No pcs available. Stop now.
 		// This can happen if the first argument to runtime.Callers is large.
@@ -165,7 +165,7 @@ func (cs *ConstraintSystem) Mul(i1, i2 interface{}, in ...interface{}) Variable
 			case Variable:
 				t2.assertIsSet()
 				_res = cs.newInternalVariable() // only in this case we record the constraint in the cs
-				cs.constraints = append(cs.constraints, newR1C(t1, t2, _res))
+				cs.addConstraint(newR1C(t1, t2, _res))
 				return _res
 			default:
 				_res = cs.mulConstant(t2, t1)
@@ -205,7 +205,7 @@ func (cs *ConstraintSystem) Inverse(v Variable) Variable {
 	// allocate resulting variable
 	res := cs.newInternalVariable()
 
-	cs.constraints = append(cs.constraints, newR1C(v, res, cs.one()))
+	cs.addConstraint(newR1C(v, res, cs.one()))
 
 	return res
 }
@@ -223,21 +223,21 @@ func (cs *ConstraintSystem) Div(i1, i2 interface{}) Variable {
 		switch t2 := i2.(type) {
 		case Variable:
 			t2.assertIsSet()
-			cs.constraints = append(cs.constraints, newR1C(t2, res, t1))
+			cs.addConstraint(newR1C(t2, res, t1))
 		default:
 			tmp := cs.Constant(t2)
-			cs.constraints = append(cs.constraints, newR1C(tmp, res, t1))
+			cs.addConstraint(newR1C(tmp, res, t1))
 		}
 	default:
 		switch t2 := i2.(type) {
 		case Variable:
 			t2.assertIsSet()
 			tmp := cs.Constant(t1)
-			cs.constraints = append(cs.constraints, newR1C(t2, res, tmp))
+			cs.addConstraint(newR1C(t2, res, tmp))
 		default:
 			tmp1 := cs.Constant(t1)
 			tmp2 := cs.Constant(t2)
-			cs.constraints = append(cs.constraints, newR1C(tmp2, res, tmp1))
+			cs.addConstraint(newR1C(tmp2, res, tmp1))
 		}
 	}
 
@@ -258,7 +258,7 @@ func (cs *ConstraintSystem) Xor(a, b Variable) Variable {
 	v2 := cs.Add(a, b)   // no constraint recorded
 	v2 = cs.Sub(v2, res) // no constraint recorded
 
-	cs.constraints = append(cs.constraints, newR1C(v1, b, v2))
+	cs.addConstraint(newR1C(v1, b, v2))
 
 	return res
 }
@@ -276,7 +276,7 @@ func (cs *ConstraintSystem) Or(a, b Variable) Variable {
 	v1 := cs.Sub(1, a)
 	v2 := cs.Sub(res, a)
 
-	cs.constraints = append(cs.constraints, newR1C(b, v1, v2))
+	cs.addConstraint(newR1C(b, v1, v2))
 
 	return res
 }
@@ -360,7 +360,7 @@ func (cs *ConstraintSystem) ToBinary(a Variable, nbBits int) []Variable {
 	}
 
 	// record the constraint Σ (2**i * b[i]) == a
-	cs.constraints = append(cs.constraints, newR1C(Σbi, cs.one(), a, compiled.BinaryDec))
+	cs.addConstraint(newR1C(Σbi, cs.one(), a, compiled.BinaryDec))
 	return b
 
 }
@@ -408,7 +408,7 @@ func (cs *ConstraintSystem) Select(b Variable, i1, i2 interface{}) Variable {
 		v := cs.Sub(t1, i2)  // no constraint is recorded
 		w := cs.Sub(res, i2) // no constraint is recorded
 		//cs.Println("u-v: ", v)
-		cs.constraints = append(cs.constraints, newR1C(b, v, w))
+		cs.addConstraint(newR1C(b, v, w))
 		return res
 	default:
 		switch t2 := i2.(type) {
@@ -417,7 +417,7 @@ func (cs *ConstraintSystem) Select(b Variable, i1, i2 interface{}) Variable {
 			res = cs.newInternalVariable()
 			v := cs.Sub(t1, t2)  // no constraint is recorded
 			w := cs.Sub(res, t2) // no constraint is recorded
-			cs.constraints = append(cs.constraints, newR1C(b, v, w))
+			cs.addConstraint(newR1C(b, v, w))
 			return res
 		default:
 			// in this case, no constraint is recorded
@@ -511,6 +511,93 @@ func (cs *ConstraintSystem) AssertIsEqual(i1, i2 interface{}) {
 	cs.addAssertion(newR1C(l, r, o), debugInfo)
 }
 
+// AssertIsEqualIf adds an assertion in the constraint system (cond == 1 ⇒ i1 == i2)
+//
+// cond must be boolean: it is constrained as such. The assertion holds trivially if cond == 0.
+func (cs *ConstraintSystem) AssertIsEqualIf(cond Variable, i1, i2 interface{}) {
+
+	// encoded as L * R == O
+	// set L = cond
+	// set R = i1 - i2
+	// set O = 0
+
+	cond.assertIsSet()
+	cs.AssertIsBoolean(cond)
+
+	debugInfo := logEntry{}
+
+	l := cs.Constant(i1) // no constraint is recorded
+	o := cs.Constant(i2) // no constraint is recorded
+
+	// build log
+	var sbb strings.Builder
+	sbb.WriteString("[")
+	lhs := cs.buildLogEntryFromVariable(l)
+	sbb.WriteString(lhs.format)
+	debugInfo.toResolve = lhs.toResolve
+	sbb.WriteString(" != ")
+	rhs := cs.buildLogEntryFromVariable(o)
+	sbb.WriteString(rhs.format)
+	debugInfo.toResolve = append(debugInfo.toResolve, rhs.toResolve...)
+	sbb.WriteString(" if ")
+	c := cs.buildLogEntryFromVariable(cond)
+	sbb.WriteString(c.format)
+	debugInfo.toResolve = append(debugInfo.toResolve, c.toResolve...)
+	sbb.WriteString("]")
+
+	// get call stack
+	sbb.WriteString("error AssertIsEqualIf")
+	stack := getCallStack()
+	for i := 0; i < len(stack); i++ {
+		sbb.WriteByte('\n')
+		sbb.WriteString(stack[i])
+	}
+	debugInfo.format = sbb.String()
+
+	cs.addAssertion(newR1C(cond, cs.Sub(l, o), cs.Constant(0)), debugInfo)
+}
+
+// AssertIsDifferent adds an assertion in the constraint system (i1 != i2)
+//
+// the assertion is encoded by the existence of the inverse of i1 - i2: if i1 == i2, the
+// constraint computing it can't be solved, and the error reports the values of i1 and i2.
+func (cs *ConstraintSystem) AssertIsDifferent(i1, i2 interface{}) {
+
+	// encoded as L * R == O
+	// set L = i1 - i2
+	// set R = 1 / (i1 - i2), computed by the solver
+	// set O = 1
+
+	debugInfo := logEntry{}
+
+	l := cs.Constant(i1) // no constraint is recorded
+	o := cs.Constant(i2) // no constraint is recorded
+
+	// build log
+	var sbb strings.Builder
+	sbb.WriteString("[")
+	lhs := cs.buildLogEntryFromVariable(l)
+	sbb.WriteString(lhs.format)
+	debugInfo.toResolve = lhs.toResolve
+	sbb.WriteString(" == ")
+	rhs := cs.buildLogEntryFromVariable(o)
+	sbb.WriteString(rhs.format)
+	debugInfo.toResolve = append(debugInfo.toResolve, rhs.toResolve...)
+	sbb.WriteString("]")
+
+	// get call stack
+	sbb.WriteString("error AssertIsDifferent")
+	stack := getCallStack()
+	for i := 0; i < len(stack); i++ {
+		sbb.WriteByte('\n')
+		sbb.WriteString(stack[i])
+	}
+	debugInfo.format = sbb.String()
+
+	res := cs.newInternalVariable()
+	cs.addConstraint(newR1C(cs.Sub(l, o), res, cs.one()), debugInfo)
+}
+
 // markBoolean marks the variable as boolean and return true
 // if a constraint was added, false if the variable was already
 // constrained as a boolean
@@ -585,6 +672,19 @@ func (cs *ConstraintSystem) AssertIsLessOrEqual(v Variable, bound interface{}) {
 
 }
 
+// AssertIsLessOrEqualIf adds assertion in constraint system (cond == 1 ⇒ v <= bound)
+//
+// cond must be boolean: it is constrained as such. bound can be a constant or a Variable;
+// if cond == 0, the assertion is applied to 0 instead of v and holds trivially.
+func (cs *ConstraintSystem) AssertIsLessOrEqualIf(cond Variable, v Variable, bound interface{}) {
+
+	cond.assertIsSet()
+	v.assertIsSet()
+
+	cs.AssertIsBoolean(cond)
+	cs.AssertIsLessOrEqual(cs.Mul(cond, v), bound)
+}
+
 func (cs *ConstraintSystem) mustBeLessOrEqVar(w, bound Variable) {
 
 	// prepare debug info to be displayed in case the constraint is not solved
@@ -692,6 +792,105 @@ func (cs *ConstraintSystem) mustBeLessOrEqCst(v Variable, bound big.Int) {
 	}
 }
 
+// maxComparisonBits is the maximum bit size of the operands of the comparisons (see IsLessOrEqual):
+// the comparison decomposes a difference of the operands in nbBits+1 bits, which must not overflow
+// the smallest supported scalar field (253 bits)
+const maxComparisonBits = 251
+
+// IsLessOrEqual returns 1 if a <= b, 0 otherwise
+//
+// a and b can be constants or Variables, and must be in [0, 2^nbBits): the Variables are constrained as such.
+// nbBits must be in [1, 251].
+func (cs *ConstraintSystem) IsLessOrEqual(a, b interface{}, nbBits int) Variable {
+	cs.assertFitsInBits(a, nbBits)
+	cs.assertFitsInBits(b, nbBits)
+	return cs.isLessOrEqual(a, b, 0, nbBits)
+}
+
+// IsLess returns 1 if a < b, 0 otherwise
+//
+// a and b can be constants or Variables, and must be in [0, 2^nbBits): the Variables are constrained as such.
+// nbBits must be in [1, 251].
+func (cs *ConstraintSystem) IsLess(a, b interface{}, nbBits int) Variable {
+	cs.assertFitsInBits(a, nbBits)
+	cs.assertFitsInBits(b, nbBits)
+	return cs.isLessOrEqual(a, b, 1, nbBits)
+}
+
+// Cmp returns 1 if a > b, 0 if a == b and -1 if a < b
+//
+// a and b can be constants or Variables, and must be in [0, 2^nbBits): the Variables are constrained as such.
+// nbBits must be in [1, 251].
+func (cs *ConstraintSystem) Cmp(a, b interface{}, nbBits int) Variable {
+	cs.assertFitsInBits(a, nbBits)
+	cs.assertFitsInBits(b, nbBits)
+
+	// the difference is decomposed once, as in isLessOrEqual: d = b - a + 2^nbBits, and a == b iff d == 2^nbBits
+	// ie iff its nbBits low bits are zero (d can't be 0, as a and b are in [0, 2^nbBits))
+	bits := cs.differenceToBinary(a, b, 0, nbBits)
+	le := bits[nbBits]
+	eq := cs.Sub(1, bits[0]) // no constraint is recorded
+	for i := 1; i < nbBits; i++ {
+		eq = cs.Mul(eq, cs.Sub(1, bits[i]))
+	}
+
+	// 1 - 2 * le + eq is -1 if a < b, 0 if a == b and 1 if a > b
+	res := cs.Sub(1, cs.Mul(le, 2)) // no constraint is recorded
+	return cs.Add(res, eq)          // no constraint is recorded
+}
+
+// Min returns the minimum of a and b
+//
+// a and b can be constants or Variables, and must be in [0, 2^nbBits) (see IsLessOrEqual)
+func (cs *ConstraintSystem) Min(a, b interface{}, nbBits int) Variable {
+	return cs.Select(cs.IsLessOrEqual(a, b, nbBits), a, b)
+}
+
+// Max returns the maximum of a and b
+//
+// a and b can be constants or Variables, and must be in [0, 2^nbBits) (see IsLessOrEqual)
+func (cs *ConstraintSystem) Max(a, b interface{}, nbBits int) Variable {
+	return cs.Select(cs.IsLessOrEqual(a, b, nbBits), b, a)
+}
+
+// isLessOrEqual returns 1 if a + offset <= b, 0 otherwise, for a, b in [0, 2^nbBits) and offset in {0, 1}
+//
+// d = b - a - offset + 2^nbBits is in [0, 2^(nbBits+1)), and its bit nbBits is set iff a + offset <= b
+func (cs *ConstraintSystem) isLessOrEqual(a, b interface{}, offset int64, nbBits int) Variable {
+	bits := cs.differenceToBinary(a, b, offset, nbBits)
+	return bits[nbBits]
+}
+
+// differenceToBinary returns the nbBits+1 bits of d = b - a - offset + 2^nbBits, for a, b in [0, 2^nbBits)
+// and offset in {0, 1}
+func (cs *ConstraintSystem) differenceToBinary(a, b interface{}, offset int64, nbBits int) []Variable {
+	var shift big.Int
+	shift.Lsh(bOne, uint(nbBits)).Sub(&shift, big.NewInt(offset))
+
+	d := cs.Sub(b, a)     // no constraint is recorded
+	d = cs.Add(d, &shift) // no constraint is recorded
+
+	return cs.ToBinary(d, nbBits+1)
+}
+
+// assertFitsInBits constrains v to be in [0, 2^nbBits) if it is a Variable, or panics
+// if it is a constant that doesn't fit in nbBits bits
+func (cs *ConstraintSystem) assertFitsInBits(v interface{}, nbBits int) {
+	if nbBits < 1 || nbBits > maxComparisonBits {
+		panic(fmt.Sprintf("comparisons are supported for 1 to %d bits operands, got %d", maxComparisonBits, nbBits))
+	}
+	switch t := v.(type) {
+	case Variable:
+		t.assertIsSet()
+		cs.ToBinary(t, nbBits)
+	default:
+		c := FromInterface(t)
+		if c.Sign() < 0 || c.BitLen() > nbBits {
+			panic(fmt.Sprintf("constant %s doesn't fit in %d bits", c.String(), nbBits))
+		}
+	}
+}
+
 // Println enables circuit debugging and behaves almost like fmt.Println()
 //
 // the print will be done once the R1CS.Solve() method is executed
@@ -699,20 +898,113 @@ func (cs *ConstraintSystem) mustBeLessOrEqCst(v Variable, bound big.Int) {
 // if one of the input is a Variable, its value will be resolved avec R1CS.Solve() method is called
 func (cs *ConstraintSystem) Println(a ...interface{}) {
 	var sbb strings.Builder
+	entry := logEntry{caller: logCaller()}
+
+	for i, arg := range a {
+		if i > 0 {
+			sbb.WriteByte(' ')
+		}
+		cs.appendLogArg(&sbb, &entry, arg, "%v")
+	}
+
+	// set format string to be used with fmt.Sprintf, once the variables are solved in the R1CS.Solve() method
+	entry.format = sbb.String()
+
+	cs.logs = append(cs.logs, entry)
+}
+
+// Printf enables circuit debugging and behaves like fmt.Printf()
+//
+// the print will be done once the R1CS.Solve() method is executed
+//
+// the values of the Variables are resolved as big.Int, such that the verbs %d, %x, %X, %o, %b
+// can be used to format them (%v and %s print them in base 10). Argument indexes and * are not supported.
+func (cs *ConstraintSystem) Printf(format string, a ...interface{}) {
+	entry := logEntry{caller: logCaller()}
+	entry.format = cs.logFormat(&entry, format, a)
+	cs.logs = append(cs.logs, entry)
+}
+
+// PrintIf behaves like Printf, but the log is printed only if cond is not zero
+//
+// cond is evaluated when the R1CS.Solve() method is executed; the log is not printed
+// if cond couldn't be solved.
+func (cs *ConstraintSystem) PrintIf(cond Variable, format string, a ...interface{}) {
+	entry := logEntry{caller: logCaller(), conditional: true}
+	_cond := cs.allocate(cond)
+	entry.toResolve = append(entry.toResolve, compiled.Pack(_cond.id, 0, _cond.visibility))
+	entry.format = cs.logFormat(&entry, format, a)
+	cs.logs = append(cs.logs, entry)
+}
+
+// logCaller returns the location (file.go:line) of the caller of the log method
+func logCaller() string {
+	if _, file, line, ok := runtime.Caller(2); ok {
+		return filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+	return ""
+}
+
+// logFormat parses the Printf format and returns the format of the log entry, in which
+// the Variables of a are replaced by their verb and added to the entry, and the other arguments are
+// formatted with fmt.Sprintf
+func (cs *ConstraintSystem) logFormat(entry *logEntry, format string, a []interface{}) string {
+	var sbb strings.Builder
+	argNum := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			sbb.WriteByte(format[i])
+			continue
+		}
+
+		// flags, width and precision
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.", format[j]) != -1 {
+			j++
+		}
+		if j == len(format) {
+			sbb.WriteString("%%!(NOVERB)")
+			break
+		}
+		verb := format[i : j+1]
+		i = j
+
+		switch format[j] {
+		case '%':
+			sbb.WriteString("%%")
+			continue
+		case '*', '[':
+			panic("Printf: argument indexes and * are not supported")
+		}
 
-	// prefix log line with file.go:line
-	if _, file, line, ok := runtime.Caller(1); ok {
-		sbb.WriteString(filepath.Base(file))
-		sbb.WriteByte(':')
-		sbb.WriteString(strconv.Itoa(line))
-		sbb.WriteByte(' ')
+		if argNum >= len(a) {
+			sbb.WriteString(escapeLog(fmt.Sprintf(verb)))
+			continue
+		}
+		cs.appendLogArg(&sbb, entry, a[argNum], verb)
+		argNum++
+	}
+
+	if argNum < len(a) {
+		sbb.WriteString("%%!(EXTRA ")
+		for i, arg := range a[argNum:] {
+			if i > 0 {
+				sbb.WriteString(", ")
+			}
+			sbb.WriteString(escapeLog(fmt.Sprintf("%T=%v", arg, arg)))
+		}
+		sbb.WriteByte(')')
 	}
 
-	// for each argument, if it is a circuit structure and contains variable
-	// we add the variables in the logEntry.toResolve part, and add %s to the format string in the log entry
-	// if it doesn't contain variable, call fmt.Sprint(arg) instead
-	entry := logEntry{}
+	return sbb.String()
+}
 
+// appendLogArg writes arg to the format of the log entry
+//
+// if arg is a circuit structure and contains variables, we add the variables in the logEntry.toResolve part,
+// and add verb to the format string in the log entry. If it doesn't contain variable, arg is formatted
+// with fmt.Sprintf(verb, arg) instead
+func (cs *ConstraintSystem) appendLogArg(sbb *strings.Builder, entry *logEntry, arg interface{}, verb string) {
 	// this is call recursively on the arguments using reflection on each argument
 	foundVariable := false
 
@@ -726,28 +1018,24 @@ func (cs *ConstraintSystem) Println(a ...interface{}) {
 		entry.toResolve = append(entry.toResolve, compiled.Pack(_v.id, 0, _v.visibility))
 
 		if name == "" {
-			sbb.WriteString("%s")
+			sbb.WriteString(verb)
 		} else {
-			sbb.WriteString(fmt.Sprintf("%s: %%s ", name))
+			sbb.WriteString(escapeLog(name))
+			sbb.WriteString(": ")
+			sbb.WriteString(verb)
+			sbb.WriteByte(' ')
 		}
 
 		foundVariable = true
 	}
 
-	for i, arg := range a {
-		if i > 0 {
-			sbb.WriteByte(' ')
-		}
-		foundVariable = false
-		parseLogValue(arg, "", handler)
-		if !foundVariable {
-			sbb.WriteString(fmt.Sprint(arg))
-		}
+	parseLogValue(arg, "", handler)
+	if !foundVariable {
+		sbb.WriteString(escapeLog(fmt.Sprintf(verb, arg)))
 	}
-	sbb.WriteByte('\n')
-
-	// set format string to be used with fmt.Sprintf, once the variables are solved in the R1CS.Solve() method
-	entry.format = sbb.String()
+}
 
-	cs.logs = append(cs.logs, entry)
+// escapeLog escapes s such that it is printed as is by fmt.Sprintf
+func escapeLog(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
 }
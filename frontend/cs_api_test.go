@@ -739,4 +739,10 @@ func TestPrintln(t *testing.T) {
 	cs.Println(one)
 
 	cs.Println(nil, 1, "a", new(big.Int), one)
+
+	cs.Printf("%d %x %s%%", one, one, "a")
+	cs.Printf("%d", nil)
+	cs.Printf("%d")
+	cs.Printf("%", one)
+	cs.PrintIf(one, "one is not zero: %v", one)
 }
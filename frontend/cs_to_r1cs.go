@@ -28,6 +28,8 @@ func (cs *ConstraintSystem) toR1CS(curveID ecc.ID) (CompiledConstraintSystem, er
 		NbConstraints:       len(cs.constraints) + len(cs.assertions),
 		NbCOConstraints:     len(cs.constraints),
 		Constraints:         make([]compiled.R1C, len(cs.constraints)+len(cs.assertions)),
+		PublicInputs:        cs.public.names,
+		SecretInputs:        cs.secret.names,
 		Logs:                make([]compiled.LogEntry, len(cs.logs)),
 		DebugInfo:           make([]compiled.LogEntry, len(cs.debugInfo)),
 	}
@@ -73,7 +75,9 @@ func (cs *ConstraintSystem) toR1CS(curveID ecc.ID) (CompiledConstraintSystem, er
 	// we need to offset the ids in logs too
 	for i := 0; i < len(cs.logs); i++ {
 		entry := compiled.LogEntry{
-			Format: cs.logs[i].format,
+			Format:      cs.logs[i].format,
+			Caller:      cs.logs[i].caller,
+			Conditional: cs.logs[i].conditional,
 		}
 		for j := 0; j < len(cs.logs[i].toResolve); j++ {
 			_, cID, cVisibility := cs.logs[i].toResolve[j].Unpack()
@@ -116,6 +120,14 @@ func (cs *ConstraintSystem) toR1CS(curveID ecc.ID) (CompiledConstraintSystem, er
 		res.DebugInfo[i] = entry
 	}
 
+	// the debug info of the constraints is in the same order as the constraints: computational constraints, then assertions
+	res.ConstraintsDebugInfo = make([]int, 0, len(res.Constraints))
+	res.ConstraintsDebugInfo = append(res.ConstraintsDebugInfo, cs.coDebugInfo...)
+	res.ConstraintsDebugInfo = append(res.ConstraintsDebugInfo, cs.asDebugInfo...)
+
+	// group the computational constraints in levels for the parallel solver
+	res.BuildLevels()
+
 	switch curveID {
 	case ecc.BLS12_377:
 		return bls12377r1cs.NewR1CS(res, cs.coeffs), nil
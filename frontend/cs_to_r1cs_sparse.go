@@ -48,6 +48,9 @@ type sparseR1CS struct {
 	// cs_variable_id -> plonk_cs_variable_id (internal variables only)
 	mCStoCCS        []int
 	solvedVariables []bool
+
+	// index in ccs.DebugInfo of the constraint being converted
+	debugInfoID int
 }
 
 func (cs *ConstraintSystem) toSparseR1CS(curveID ecc.ID) (CompiledConstraintSystem, error) {
@@ -57,9 +60,12 @@ func (cs *ConstraintSystem) toSparseR1CS(curveID ecc.ID) (CompiledConstraintSyst
 		ccs: compiled.SparseR1CS{
 			NbPublicVariables: len(cs.public.variables) - 1, // the ONE_WIRE is discarded as it is not used in PLONK
 			NbSecretVariables: len(cs.secret.variables),
+			PublicInputs:      cs.public.names[1:], // the ONE_WIRE is discarded
+			SecretInputs:      cs.secret.names,
 			Constraints:       make([]compiled.SparseR1C, 0, len(cs.constraints)),
 			Assertions:        make([]compiled.SparseR1C, 0, len(cs.assertions)),
 			Logs:              make([]compiled.LogEntry, len(cs.logs)),
+			DebugInfo:         make([]compiled.LogEntry, len(cs.debugInfo)),
 		},
 		mCStoCCS:        make([]int, len(cs.internal.variables)),
 		solvedVariables: make([]bool, len(cs.internal.variables)),
@@ -67,9 +73,11 @@ func (cs *ConstraintSystem) toSparseR1CS(curveID ecc.ID) (CompiledConstraintSyst
 
 	// convert the constraints invidually
 	for i := 0; i < len(cs.constraints); i++ {
+		res.debugInfoID = cs.coDebugInfo[i]
 		res.r1cToSparseR1C(cs.constraints[i])
 	}
 	for i := 0; i < len(cs.assertions); i++ {
+		res.debugInfoID = cs.asDebugInfo[i]
 		res.splitR1C(cs.assertions[i])
 	}
 
@@ -161,14 +169,16 @@ func (cs *ConstraintSystem) toSparseR1CS(curveID ecc.ID) (CompiledConstraintSyst
 		}
 	}
 
-	// offset IDs in the logs
-	for i := 0; i < len(cs.logs); i++ {
+	// offset IDs in the logs and in the debug info
+	offsetLogEntry := func(e logEntry) compiled.LogEntry {
 		entry := compiled.LogEntry{
-			Format:    cs.logs[i].format,
-			ToResolve: make([]int, len(cs.logs[i].toResolve)),
+			Format:      e.format,
+			ToResolve:   make([]int, len(e.toResolve)),
+			Caller:      e.caller,
+			Conditional: e.conditional,
 		}
-		for j := 0; j < len(cs.logs[i].toResolve); j++ {
-			_, cID, cVisibility := cs.logs[i].toResolve[j].Unpack()
+		for j := 0; j < len(e.toResolve); j++ {
+			_, cID, cVisibility := e.toResolve[j].Unpack()
 			switch cVisibility {
 			case compiled.Public:
 				entry.ToResolve[j] += cID - 1 //+ res.NbInternalVariables + res.NbSecretVariables // -1 because the ONE_WIRE's is not counted
@@ -180,9 +190,18 @@ func (cs *ConstraintSystem) toSparseR1CS(curveID ecc.ID) (CompiledConstraintSyst
 				panic("encountered unset visibility on a variable in logs id offset routine")
 			}
 		}
-		res.ccs.Logs[i] = entry
+		return entry
+	}
+	for i := 0; i < len(cs.logs); i++ {
+		res.ccs.Logs[i] = offsetLogEntry(cs.logs[i])
+	}
+	for i := 0; i < len(cs.debugInfo); i++ {
+		res.ccs.DebugInfo[i] = offsetLogEntry(cs.debugInfo[i])
 	}
 
+	// group the constraints in levels for the parallel solver
+	res.ccs.BuildLevels()
+
 	switch curveID {
 	case ecc.BLS12_377:
 		return bls12377r1cs.NewSparseR1CS(res.ccs, cs.coeffs), nil
@@ -342,11 +361,13 @@ func (scs *sparseR1CS) addConstraint(c compiled.SparseR1C) {
 		c.M[1].SetVariableID(c.R.VariableID())
 	}
 	scs.ccs.Constraints = append(scs.ccs.Constraints, c)
+	scs.ccs.ConstraintsDebugInfo = append(scs.ccs.ConstraintsDebugInfo, scs.debugInfoID)
 }
 
 // recordAssertion records a plonk constraint (assertion) in the ccs
 func (scs *sparseR1CS) recordAssertion(c compiled.SparseR1C) {
 	scs.ccs.Assertions = append(scs.ccs.Assertions, c)
+	scs.ccs.AssertionsDebugInfo = append(scs.ccs.AssertionsDebugInfo, scs.debugInfoID)
 }
 
 // if t=a*variable, it returns -a*variable
@@ -31,12 +31,18 @@ var ErrInputNotSet = errors.New("variable is not allocated")
 // 		if zkpID == backend.GROTH16	--> R1CS
 //		if zkpID == backend.PLONK 	--> SparseR1CS
 //
-// initialCapacity is an optional parameter that reserves memory in slices
-// it should be set to the estimated number of constraints in the circuit, if known.
-func Compile(curveID ecc.ID, zkpID backend.ID, circuit Circuit, initialCapacity ...int) (ccs CompiledConstraintSystem, err error) {
+// The compilation is configured with opts (see CompileOption).
+func Compile(curveID ecc.ID, zkpID backend.ID, circuit Circuit, opts ...CompileOption) (ccs CompiledConstraintSystem, err error) {
+
+	var opt CompileConfig
+	for _, o := range opts {
+		if err := o(&opt); err != nil {
+			return nil, err
+		}
+	}
 
 	// build the constraint system (see Circuit.Define)
-	cs, err := buildCS(curveID, circuit, initialCapacity...)
+	cs, err := buildCS(curveID, circuit, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -56,10 +62,48 @@ func Compile(curveID ecc.ID, zkpID backend.ID, circuit Circuit, initialCapacity
 	return
 }
 
+// CompileOption configures the compilation of a circuit (see Compile)
+type CompileOption func(opt *CompileConfig) error
+
+// CompileConfig holds the configuration of Compile, set by the CompileOption
+type CompileConfig struct {
+	// Capacity is the number of constraints for which memory is reserved
+	Capacity int
+
+	// DebugInfo records the call stack of each computational constraint, see WithDebugInfo
+	DebugInfo bool
+}
+
+// WithCapacity reserves memory for capacity constraints
+//
+// it should be set to the estimated number of constraints in the circuit, if known;
+// it has quite some impact on frontend performance, especially on large circuits size.
+func WithCapacity(capacity int) CompileOption {
+	return func(opt *CompileConfig) error {
+		if capacity < 0 {
+			return errors.New("capacity must be positive")
+		}
+		opt.Capacity = capacity
+		return nil
+	}
+}
+
+// WithDebugInfo records the call stack (source location in the circuit) of each computational constraint,
+// reported by the solver in diagnostic mode (see backend.WithDiagnostics)
+//
+// the assertions always record their source location; recording it for every computational constraint
+// slows down the compilation.
+func WithDebugInfo() CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.DebugInfo = true
+		return nil
+	}
+}
+
 // buildCS builds the constraint system. It bootstraps the inputs
 // allocations by parsing the circuit's underlying structure, then
 // it builds the constraint system using the Define method.
-func buildCS(curveID ecc.ID, circuit Circuit, initialCapacity ...int) (cs ConstraintSystem, err error) {
+func buildCS(curveID ecc.ID, circuit Circuit, opt CompileConfig) (cs ConstraintSystem, err error) {
 	// recover from panics to print user-friendlier messages
 	defer func() {
 		if r := recover(); r != nil {
@@ -67,7 +111,8 @@ func buildCS(curveID ecc.ID, circuit Circuit, initialCapacity ...int) (cs Constr
 		}
 	}()
 	// instantiate our constraint system
-	cs = newConstraintSystem(initialCapacity...)
+	cs = newConstraintSystem(opt.Capacity)
+	cs.debug = opt.DebugInfo
 
 	// leaf handlers are called when encoutering leafs in the circuit data struct
 	// leafs are Constraints that need to be initialized in the context of compiling a circuit
@@ -83,9 +128,13 @@ func buildCS(curveID ecc.ID, circuit Circuit, initialCapacity ...int) (cs Constr
 			}
 			switch visibility {
 			case compiled.Secret:
-				tInput.Set(reflect.ValueOf(cs.newSecretVariable()))
+				v = cs.newSecretVariable()
+				cs.secret.names[v.id] = name
+				tInput.Set(reflect.ValueOf(v))
 			case compiled.Public:
-				tInput.Set(reflect.ValueOf(cs.newPublicVariable()))
+				v = cs.newPublicVariable()
+				cs.public.names[v.id] = name
+				tInput.Set(reflect.ValueOf(v))
 			case compiled.Unset:
 				return errors.New("can't set val " + name + " visibility is unset")
 			}
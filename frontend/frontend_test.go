@@ -1,6 +1,7 @@
 package frontend
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -14,7 +15,7 @@ func BenchmarkCompileReferenceGroth16(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		Compile(ecc.BN254, backend.GROTH16, &c, benchSize)
+		Compile(ecc.BN254, backend.GROTH16, &c, WithCapacity(benchSize))
 	}
 }
 
@@ -23,7 +24,7 @@ func BenchmarkCompileReferencePlonk(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		Compile(ecc.BN254, backend.PLONK, &c, benchSize)
+		Compile(ecc.BN254, backend.PLONK, &c, WithCapacity(benchSize))
 	}
 }
 
@@ -40,3 +41,62 @@ func (circuit *benchCircuit) Define(curveID ecc.ID, cs *ConstraintSystem) error
 	cs.AssertIsEqual(circuit.X, circuit.Y)
 	return nil
 }
+
+type debugInfoCircuit struct {
+	X Variable
+	Y Variable `gnark:",public"`
+}
+
+func (circuit *debugInfoCircuit) Define(curveID ecc.ID, cs *ConstraintSystem) error {
+	cs.AssertIsEqual(cs.Mul(circuit.X, circuit.X), circuit.Y)
+	return nil
+}
+
+func TestDebugInfo(t *testing.T) {
+	var circuit debugInfoCircuit
+
+	// the call stacks of the computational constraints are only recorded with WithDebugInfo
+	for _, debug := range []bool{false, true} {
+		cs, err := buildCS(ecc.BN254, &circuit, CompileConfig{DebugInfo: debug})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cs.coDebugInfo) != 1 {
+			t.Fatal("expected 1 computational constraint, got", len(cs.coDebugInfo))
+		}
+		entry := cs.debugInfo[cs.coDebugInfo[0]]
+		if strings.Contains(entry.format, "frontend_test.go") != debug {
+			t.Fatal("unexpected debug info with debug set to", debug, ":", entry.format)
+		}
+	}
+}
+
+type assertIsDifferentCircuit struct {
+	X Variable
+	Y Variable `gnark:",public"`
+}
+
+func (circuit *assertIsDifferentCircuit) Define(curveID ecc.ID, cs *ConstraintSystem) error {
+	cs.AssertIsDifferent(circuit.X, circuit.Y)
+	return nil
+}
+
+func TestAssertIsDifferentDebugInfo(t *testing.T) {
+	var circuit assertIsDifferentCircuit
+
+	// the constraint of AssertIsDifferent reports its operands and call stack, even without WithDebugInfo
+	cs, err := buildCS(ecc.BN254, &circuit, CompileConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs.coDebugInfo) != 1 {
+		t.Fatal("expected 1 computational constraint, got", len(cs.coDebugInfo))
+	}
+	entry := cs.debugInfo[cs.coDebugInfo[0]]
+	if !strings.Contains(entry.format, "error AssertIsDifferent") || !strings.Contains(entry.format, "frontend_test.go") {
+		t.Fatal("unexpected debug info:", entry.format)
+	}
+	if len(entry.toResolve) != 2 {
+		t.Fatal("expected the 2 operands to be resolved, got", len(entry.toResolve))
+	}
+}
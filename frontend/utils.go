@@ -26,7 +26,7 @@ type toBigIntInterface interface {
 // FromInterface converts an interface to a big.Int element
 // interface must implement ToBigIntRegular(res *big.Int) *big.Int
 // (which is the case for field generated by goff)
-// or be uint64, int, string, []byte or big.Int
+// or be an unsigned or signed integer, string, []byte or big.Int
 // it panics if the input is invalid
 func FromInterface(i1 interface{}) big.Int {
 	var val big.Int
@@ -38,6 +38,22 @@ func FromInterface(i1 interface{}) big.Int {
 		val.Set(c1)
 	case uint64:
 		val.SetUint64(c1)
+	case uint32:
+		val.SetUint64(uint64(c1))
+	case uint16:
+		val.SetUint64(uint64(c1))
+	case uint8:
+		val.SetUint64(uint64(c1))
+	case uint:
+		val.SetUint64(uint64(c1))
+	case int64:
+		val.SetInt64(c1)
+	case int32:
+		val.SetInt64(int64(c1))
+	case int16:
+		val.SetInt64(int64(c1))
+	case int8:
+		val.SetInt64(int64(c1))
 	case int:
 		val.SetInt64(int64(c1))
 	case string:
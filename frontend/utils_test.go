@@ -20,6 +20,9 @@ func TestFromInterfaceValidFormats(t *testing.T) {
 	_ = FromInterface(a)
 	_ = FromInterface(&a)
 	_ = FromInterface(12)
+	_ = FromInterface(uint8(12))
+	_ = FromInterface(uint32(12))
+	_ = FromInterface(int64(-12))
 	_ = FromInterface(big.NewInt(-42))
 	_ = FromInterface(*big.NewInt(42))
 	_ = FromInterface("8000")
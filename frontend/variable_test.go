@@ -3,6 +3,7 @@ package frontend
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
 	"runtime/debug"
 	"testing"
@@ -162,5 +163,95 @@ func TestStructTags(t *testing.T) {
 		expected["A_1"] = compiled.Public
 		testParseType(&s, expected)
 	}
+	// map, visited in key order
+	{
+		s := struct {
+			A map[string]Variable `gnark:",public"`
+			B map[string][]Variable
+		}{
+			A: map[string]Variable{"y": {}, "x": {}},
+			B: map[string][]Variable{"z": make([]Variable, 2)},
+		}
+		expected := make(map[string]compiled.Visibility)
+		expected["A_x"] = compiled.Public
+		expected["A_y"] = compiled.Public
+		expected["B_z_0"] = compiled.Secret
+		expected["B_z_1"] = compiled.Secret
+		testParseType(&s, expected)
+	}
+
+	// pointer to struct
+	{
+		type child struct {
+			D Variable
+		}
+		s := struct {
+			A *child `gnark:",public"`
+			B **child
+		}{A: &child{}}
+		b := &child{}
+		s.B = &b
+		expected := make(map[string]compiled.Visibility)
+		expected["A_D"] = compiled.Public
+		expected["B_D"] = compiled.Secret
+		testParseType(&s, expected)
+	}
+
+}
 
+func TestStructTagsInvalid(t *testing.T) {
+	tVariable := reflect.TypeOf(Variable{})
+	var handler parser.LeafHandler = func(visibility compiled.Visibility, name string, tInput reflect.Value) error {
+		return nil
+	}
+
+	type child struct {
+		D Variable
+	}
+
+	invalid := map[string]interface{}{
+		"nil pointer": &struct {
+			A *child
+		}{},
+		"map not keyed by strings": &struct {
+			A map[int]Variable
+		}{A: map[int]Variable{1: {}}},
+	}
+
+	for name, input := range invalid {
+		if err := parser.Visit(input, "", compiled.Unset, handler, tVariable); err == nil {
+			t.Fatal("expected an error for", name)
+		}
+	}
+
+	// unexported fields, empty slices and nil pointers to types without variables are ignored
+	valid := map[string]interface{}{
+		"unexported variable": &struct {
+			a Variable
+			B Variable
+		}{},
+		"unexported struct": &struct {
+			a child
+			B Variable
+		}{},
+		"unexported slice": &struct {
+			a []Variable
+			B Variable
+		}{a: make([]Variable, 1)},
+		"uninitialized slice": &struct {
+			A []Variable
+			B Variable
+		}{},
+		"nil pointer": &struct {
+			A *big.Int
+			B Variable
+			C []uint64
+		}{},
+	}
+
+	for name, input := range valid {
+		if err := parser.Visit(input, "", compiled.Unset, handler, tVariable); err != nil {
+			t.Fatal(name, err)
+		}
+	}
 }
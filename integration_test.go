@@ -19,6 +19,7 @@ package gnark
 import (
 	"bytes"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -66,7 +67,7 @@ func TestIntegrationAPI(t *testing.T) {
 			correctProof, err := groth16.Prove(r1cs, pk, circuit.Good)
 			assert.NoError(err)
 
-			wrongProof, err := groth16.Prove(r1cs, pk, circuit.Bad, true)
+			wrongProof, err := groth16.Prove(r1cs, pk, circuit.Bad, backend.IgnoreSolverError())
 			assert.NoError(err)
 
 			assert.NoError(groth16.Verify(correctProof, vk, circuit.Public))
@@ -91,6 +92,28 @@ func TestIntegrationAPI(t *testing.T) {
 				assert.NoError(err)
 			}
 
+			// witness json serialization tests.
+			{
+				buf.Reset()
+
+				assert.NoError(witness.WriteFullJSON(&buf, curve, circuit.Good))
+
+				fullWitness := reflect.New(reflect.TypeOf(circuit.Good).Elem()).Interface().(frontend.Circuit)
+				assert.NoError(witness.ReadFullJSON(&buf, curve, fullWitness))
+
+				correctProof, err := groth16.Prove(r1cs, pk, fullWitness)
+				assert.NoError(err)
+
+				buf.Reset()
+
+				assert.NoError(witness.WritePublicJSON(&buf, curve, circuit.Good))
+
+				publicWitness := reflect.New(reflect.TypeOf(circuit.Good).Elem()).Interface().(frontend.Circuit)
+				assert.NoError(witness.ReadPublicJSON(&buf, curve, publicWitness))
+
+				assert.NoError(groth16.Verify(correctProof, vk, publicWitness))
+			}
+
 		}
 	}
 }
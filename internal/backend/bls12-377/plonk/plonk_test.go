@@ -135,7 +135,7 @@ func BenchmarkProver(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err = bls12_377plonk.Prove(ccs.(*cs.SparseR1CS), pk, fullWitness)
+		_, err = bls12_377plonk.Prove(ccs.(*cs.SparseR1CS), pk, fullWitness, proverConfig())
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -160,7 +160,7 @@ func BenchmarkVerifier(b *testing.B) {
 		b.Fatal(err)
 	}
 
-	proof, err := bls12_377plonk.Prove(ccs.(*cs.SparseR1CS), pk, fullWitness)
+	proof, err := bls12_377plonk.Prove(ccs.(*cs.SparseR1CS), pk, fullWitness, proverConfig())
 	if err != nil {
 		panic(err)
 	}
@@ -184,7 +184,7 @@ func BenchmarkSerialization(b *testing.B) {
 		b.Fatal(err)
 	}
 
-	proof, err := bls12_377plonk.Prove(ccs.(*cs.SparseR1CS), pk, fullWitness)
+	proof, err := bls12_377plonk.Prove(ccs.(*cs.SparseR1CS), pk, fullWitness, proverConfig())
 	if err != nil {
 		b.Fatal(err)
 	}
@@ -240,3 +240,9 @@ func BenchmarkSerialization(b *testing.B) {
 	}
 
 }
+
+// proverConfig returns the default prover configuration, without the circuit logs
+func proverConfig() backend.ProverConfig {
+	opt, _ := backend.NewProverConfig(backend.WithLoggerOutput(nil))
+	return opt
+}
@@ -25,12 +25,16 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
 
+	"context"
+	"crypto/rand"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
 	bls12_381witness "github.com/consensys/gnark/internal/backend/bls12-381/witness"
 	"github.com/consensys/gnark/internal/utils"
+	"io"
 	"math/big"
-	"runtime"
+	"sync/atomic"
 )
 
 // Proof represents a Groth16 proof that was encoded with a ProvingKey and can be verified
@@ -52,21 +56,32 @@ func (proof *Proof) CurveID() ecc.ID {
 }
 
 // Prove generates the proof of knoweldge of a r1cs with full witness (secret + public part).
-// if force flag is set, Prove ignores R1CS solving error (ie invalid witness) and executes
+// if opt.Force is set, Prove ignores R1CS solving error (ie invalid witness) and executes
 // the FFTs and MultiExponentiations to compute an (invalid) Proof object
-func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_381witness.Witness, force bool) (*Proof, error) {
+func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_381witness.Witness, opt backend.ProverConfig) (*Proof, error) {
+	return ProveContext(context.Background(), r1cs, pk, witness, opt)
+}
+
+// ProveContext behaves like Prove, but stops the computation and returns ctx.Err() if ctx is done
+// before the proof is computed. ctx is checked between the steps of the computation: an FFT or a
+// multi-exponentiation which has started runs to completion, and ProveContext waits for it before returning.
+// The end of each phase of the computation is reported to opt.Progress, if not nil.
+func ProveContext(ctx context.Context, r1cs *cs.R1CS, pk *ProvingKey, witness bls12_381witness.Witness, opt backend.ProverConfig) (*Proof, error) {
 	if len(witness) != int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables) {
 		return nil, fmt.Errorf("invalid witness size, got %d, expected %d = %d (public - ONE_WIRE) + %d (secret)", len(witness), int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables), r1cs.NbPublicVariables, r1cs.NbSecretVariables)
 	}
 
+	p := utils.NewProgress(opt.Progress)
+
 	// solve the R1CS and compute the a, b, c vectors
 	a := make([]fr.Element, r1cs.NbConstraints, pk.Domain.Cardinality)
 	b := make([]fr.Element, r1cs.NbConstraints, pk.Domain.Cardinality)
 	c := make([]fr.Element, r1cs.NbConstraints, pk.Domain.Cardinality)
 	wireValues := make([]fr.Element, r1cs.NbInternalVariables+r1cs.NbPublicVariables+r1cs.NbSecretVariables)
-	if err := r1cs.Solve(witness, a, b, c, wireValues); err != nil && !force {
+	if err := r1cs.SolveContext(ctx, witness, a, b, c, wireValues, opt); err != nil && (!opt.Force || ctx.Err() != nil) {
 		return nil, err
 	}
+	p.Done(backend.PhaseSolve)
 
 	// set the wire values in regular form
 	utils.Parallelize(len(wireValues), func(start, end int) {
@@ -75,28 +90,29 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_381witness.Witness, forc
 		}
 	})
 
-	// H (witness reduction / FFT part)
-	var h []fr.Element
-	chHDone := make(chan struct{}, 1)
-	go func() {
-		h = computeH(a, b, c, &pk.Domain)
-		a = nil
-		b = nil
-		c = nil
-		chHDone <- struct{}{}
-	}()
-
 	// sample random r and s
 	var r, s big.Int
 	var _r, _s, _kr fr.Element
-	if _, err := _r.SetRandom(); err != nil {
+	if err := setRandom(&_r, opt.RandomSource); err != nil {
 		return nil, err
 	}
-	if _, err := _s.SetRandom(); err != nil {
+	if err := setRandom(&_s, opt.RandomSource); err != nil {
 		return nil, err
 	}
 	_kr.Mul(&_r, &_s).Neg(&_kr)
 
+	// H (witness reduction / FFT part)
+	var h []fr.Element
+	chHDone := make(chan error, 1)
+	go func() {
+		var err error
+		h, err = computeH(ctx, a, b, c, &pk.Domain)
+		a = nil
+		b = nil
+		c = nil
+		chHDone <- err
+	}()
+
 	_r.FromMont()
 	_s.FromMont()
 	_kr.FromMont()
@@ -109,23 +125,47 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_381witness.Witness, forc
 	proof := &Proof{}
 	var bs1, ar curve.G1Jac
 
-	n := runtime.NumCPU()
+	// the multi-exponentiations in G1 run concurrently, with half of the tasks each
+	n := opt.NbTasks
+	nHalf := n / 2
+	if nHalf == 0 {
+		nHalf = 1
+	}
+
+	// [B]1 and [B]2 are computed concurrently, the last one to complete reports PhaseMSMB
+	var nbBDone int32
+	bDone := func() {
+		if atomic.AddInt32(&nbBDone, 1) == 2 {
+			p.Done(backend.PhaseMSMB)
+		}
+	}
 
 	chBs1Done := make(chan error, 1)
 	computeBS1 := func() {
-		if _, err := bs1.MultiExp(pk.G1.B, wireValues, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := ctx.Err(); err != nil {
+			chBs1Done <- err
+			close(chBs1Done)
+			return
+		}
+		if _, err := bs1.MultiExp(pk.G1.B, wireValues, ecc.MultiExpConfig{NbTasks: nHalf}); err != nil {
 			chBs1Done <- err
 			close(chBs1Done)
 			return
 		}
 		bs1.AddMixed(&pk.G1.Beta)
 		bs1.AddMixed(&deltas[1])
+		bDone()
 		chBs1Done <- nil
 	}
 
 	chArDone := make(chan error, 1)
 	computeAR1 := func() {
-		if _, err := ar.MultiExp(pk.G1.A, wireValues, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := ctx.Err(); err != nil {
+			chArDone <- err
+			close(chArDone)
+			return
+		}
+		if _, err := ar.MultiExp(pk.G1.A, wireValues, ecc.MultiExpConfig{NbTasks: nHalf}); err != nil {
 			chArDone <- err
 			close(chArDone)
 			return
@@ -133,6 +173,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_381witness.Witness, forc
 		ar.AddMixed(&pk.G1.Alpha)
 		ar.AddMixed(&deltas[0])
 		proof.Ar.FromJacobian(&ar)
+		p.Done(backend.PhaseMSMA)
 		chArDone <- nil
 	}
 
@@ -144,42 +185,50 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_381witness.Witness, forc
 		var krs, krs2, p1 curve.G1Jac
 		chKrs2Done := make(chan error, 1)
 		go func() {
-			_, err := krs2.MultiExp(pk.G1.Z, h, ecc.MultiExpConfig{NbTasks: n / 2})
+			if err := ctx.Err(); err != nil {
+				chKrs2Done <- err
+				return
+			}
+			_, err := krs2.MultiExp(pk.G1.Z, h, ecc.MultiExpConfig{NbTasks: nHalf})
 			chKrs2Done <- err
 		}()
-		if _, err := krs.MultiExp(pk.G1.K, wireValues[r1cs.NbPublicVariables:], ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
-			chKrsDone <- err
-			return
+		err := ctx.Err()
+		if err == nil {
+			_, err = krs.MultiExp(pk.G1.K, wireValues[r1cs.NbPublicVariables:], ecc.MultiExpConfig{NbTasks: nHalf})
 		}
 		krs.AddMixed(&deltas[2])
+
+		// we wait for the 3 other multi-exponentiations even if one failed, so that none is left running
 		n := 3
 		for n != 0 {
 			select {
-			case err := <-chKrs2Done:
-				if err != nil {
-					chKrsDone <- err
-					return
+			case errKrs2 := <-chKrs2Done:
+				if err == nil {
+					err = errKrs2
 				}
 				krs.AddAssign(&krs2)
-			case err := <-chArDone:
-				if err != nil {
-					chKrsDone <- err
-					return
+			case errAr := <-chArDone:
+				if err == nil {
+					err = errAr
 				}
 				p1.ScalarMultiplication(&ar, &s)
 				krs.AddAssign(&p1)
-			case err := <-chBs1Done:
-				if err != nil {
-					chKrsDone <- err
-					return
+			case errBs1 := <-chBs1Done:
+				if err == nil {
+					err = errBs1
 				}
 				p1.ScalarMultiplication(&bs1, &r)
 				krs.AddAssign(&p1)
 			}
 			n--
 		}
+		if err != nil {
+			chKrsDone <- err
+			return
+		}
 
 		proof.Krs.FromJacobian(&krs)
+		p.Done(backend.PhaseMSMK)
 		chKrsDone <- nil
 	}
 
@@ -188,10 +237,13 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_381witness.Witness, forc
 		var Bs, deltaS curve.G2Jac
 
 		nbTasks := n
-		if nbTasks <= 16 {
+		if nbTasks <= 16 && !opt.NbTasksSet {
 			// if we don't have a lot of CPUs, this may artificially split the MSM
 			nbTasks *= 2
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if _, err := Bs.MultiExp(pk.G2.B, wireValues, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
 			return err
 		}
@@ -202,29 +254,45 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_381witness.Witness, forc
 		Bs.AddMixed(&pk.G2.Beta)
 
 		proof.Bs.FromJacobian(&Bs)
+		bDone()
 		return nil
 	}
 
 	// wait for FFT to end, as it uses all our CPUs
-	<-chHDone
+	if err := <-chHDone; err != nil {
+		return nil, err
+	}
+	p.Done(backend.PhaseH)
 
 	// schedule our proof part computations
 	go computeKRS()
 	go computeAR1()
 	go computeBS1()
-	if err := computeBS2(); err != nil {
-		return nil, err
-	}
+	errBs2 := computeBS2()
 
-	// wait for all parts of the proof to be computed.
+	// wait for all parts of the proof to be computed (computeKRS waits for computeAR1 and computeBS1).
 	if err := <-chKrsDone; err != nil {
 		return nil, err
 	}
+	if errBs2 != nil {
+		return nil, errBs2
+	}
 
 	return proof, nil
 }
 
-func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
+// setRandom sets e to a uniformly random element, sampled from r
+func setRandom(e *fr.Element, r io.Reader) error {
+	v, err := rand.Int(r, fr.Modulus())
+	if err != nil {
+		return err
+	}
+	e.SetBigInt(v)
+	return nil
+}
+
+// computeH returns ctx.Err() if ctx is done between two FFT steps
+func computeH(ctx context.Context, a, b, c []fr.Element, domain *fft.Domain) ([]fr.Element, error) {
 	// H part of Krs
 	// Compute H (hz=ab-c, where z=-2 on ker X^n+1 (z(x)=x^n-1))
 	// 	1 - _a = ifft(a), _b = ifft(b), _c = ifft(c)
@@ -243,6 +311,9 @@ func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 	domain.FFTInverse(a, fft.DIF, 0)
 	domain.FFTInverse(b, fft.DIF, 0)
 	domain.FFTInverse(c, fft.DIF, 0)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	domain.FFT(a, fft.DIT, 1)
 	domain.FFT(b, fft.DIT, 1)
@@ -263,6 +334,10 @@ func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 		}
 	})
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// ifft_coset
 	domain.FFTInverse(a, fft.DIF, 1)
 
@@ -272,5 +347,5 @@ func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 		}
 	})
 
-	return a
+	return a, nil
 }
@@ -25,6 +25,10 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
+	"github.com/consensys/gnark/backend"
+
+	"crypto/rand"
+	"io"
 	"math/big"
 	"math/bits"
 )
@@ -72,7 +76,9 @@ type VerifyingKey struct {
 }
 
 // Setup constructs the SRS
-func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
+//
+// the toxic waste is sampled from opt.RandomSource
+func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey, opt backend.SetupConfig) error {
 
 	/*
 		Setup
@@ -93,7 +99,7 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	domain := fft.NewDomain(uint64(r1cs.NbConstraints), 1, true)
 
 	// samples toxic waste
-	toxicWaste, err := sampleToxicWaste()
+	toxicWaste, err := sampleToxicWaste(opt.RandomSource)
 	if err != nil {
 		return err
 	}
@@ -306,23 +312,23 @@ type toxicWaste struct {
 	alphaReg, betaReg, gammaReg, deltaReg fr.Element
 }
 
-func sampleToxicWaste() (toxicWaste, error) {
+func sampleToxicWaste(r io.Reader) (toxicWaste, error) {
 
 	res := toxicWaste{}
 
-	if _, err := res.t.SetRandom(); err != nil {
+	if err := setRandom(&res.t, r); err != nil {
 		return res, err
 	}
-	if _, err := res.alpha.SetRandom(); err != nil {
+	if err := setRandom(&res.alpha, r); err != nil {
 		return res, err
 	}
-	if _, err := res.beta.SetRandom(); err != nil {
+	if err := setRandom(&res.beta, r); err != nil {
 		return res, err
 	}
-	if _, err := res.gamma.SetRandom(); err != nil {
+	if err := setRandom(&res.gamma, r); err != nil {
 		return res, err
 	}
-	if _, err := res.delta.SetRandom(); err != nil {
+	if err := setRandom(&res.delta, r); err != nil {
 		return res, err
 	}
 
@@ -352,7 +358,7 @@ func DummySetup(r1cs *cs.R1CS, pk *ProvingKey) error {
 	pk.G2.B = make([]curve.G2Affine, nbWires)
 
 	// samples toxic waste
-	toxicWaste, err := sampleToxicWaste()
+	toxicWaste, err := sampleToxicWaste(rand.Reader)
 	if err != nil {
 		return err
 	}
@@ -17,7 +17,10 @@
 package plonk
 
 import (
+	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"io"
 	"math/big"
 	"math/bits"
 	"runtime"
@@ -38,6 +41,7 @@ import (
 	"github.com/consensys/gnark/internal/backend/bls24-315/cs"
 
 	"github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/internal/utils"
 )
 
@@ -59,7 +63,16 @@ type Proof struct {
 }
 
 // Prove from the public data
-func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls24_315witness.Witness) (*Proof, error) {
+func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls24_315witness.Witness, opt backend.ProverConfig) (*Proof, error) {
+	return ProveContext(context.Background(), spr, pk, fullWitness, opt)
+}
+
+// ProveContext behaves like Prove, but stops the computation and returns ctx.Err() if ctx is done
+// before the proof is computed. ctx is checked between the steps of the computation: an FFT or a
+// multi-exponentiation which has started runs to completion, and ProveContext waits for it before returning.
+// The end of each phase of the computation is reported to opt.Progress, if not nil.
+func ProveContext(ctx context.Context, spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls24_315witness.Witness, opt backend.ProverConfig) (*Proof, error) {
+	p := utils.NewProgress(opt.Progress)
 
 	// pick a hash function that will be used to derive the challenges
 	hFunc := sha256.New()
@@ -71,23 +84,32 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls24_315witness.Witn
 	proof := &Proof{}
 
 	// compute the constraint system solution
-	solution, err := spr.Solve(fullWitness)
-	if err != nil {
+	// if opt.Force is set, an unsatisfied constraint doesn't stop the prover (the proof will be invalid)
+	solution, err := spr.SolveContext(ctx, fullWitness, opt)
+	if err != nil && (!opt.Force || solution == nil || ctx.Err() != nil) {
 		return nil, err
 	}
+	p.Done(backend.PhaseSolve)
 
 	// query l, r, o in Lagrange basis, not blinded
 	ll, lr, lo := computeLRO(spr, pk, solution)
 
-	// save ll, lr, lo, and make a copy of them in canonical basis.
-	// note that we allocate more capacity to reuse for blinded polynomials
-	bcl, bcr, bco, err := computeBlindedLRO(ll, lr, lo, &pk.DomainNum)
+	// draw the blinding polynomials of l, r, o (degree 1) and z (degree 2) sequentially:
+	// opt.RandomSource is not required to be safe for concurrent use
+	blindings, err := randomPolynomials(opt.RandomSource, 1, 1, 1, 2)
 	if err != nil {
 		return nil, err
 	}
 
+	// save ll, lr, lo, and make a copy of them in canonical basis.
+	// note that we allocate more capacity to reuse for blinded polynomials
+	bcl, bcr, bco := computeBlindedLRO(ll, lr, lo, &pk.DomainNum, blindings[0], blindings[1], blindings[2])
+
 	// compute kzg commitments of bcl, bcr and bco
-	if err := commitToLRO(bcl, bcr, bco, proof, pk.Vk.KZGSRS); err != nil {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := commitToLRO(bcl, bcr, bco, proof, pk.Vk.KZGSRS, opt.NbTasks); err != nil {
 		return nil, err
 	}
 
@@ -104,19 +126,19 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls24_315witness.Witn
 	var alpha fr.Element
 	go func() {
 		var err error
-		bz, err = computeBlindedZ(ll, lr, lo, pk, gamma)
-		if err != nil {
+		bz = computeBlindedZ(ll, lr, lo, pk, gamma, blindings[3])
+
+		// commit to the blinded version of z
+		if err := ctx.Err(); err != nil {
 			chZ <- err
 			close(chZ)
 			return
 		}
-
-		// commit to the blinded version of z
 		// note that we explicitly double the number of tasks for the multi exp in kzg.Commit
 		// this may add additional arithmetic operations, but with smaller tasks
 		// we ensure that this commitment is well parallelized, without having a "unbalanced task" making
 		// the rest of the code wait too long.
-		if proof.Z, err = kzg.Commit(bz, pk.Vk.KZGSRS, runtime.NumCPU()*2); err != nil {
+		if proof.Z, err = kzg.Commit(bz, pk.Vk.KZGSRS, opt.NbTasks*2); err != nil {
 			chZ <- err
 			close(chZ)
 			return
@@ -183,17 +205,27 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls24_315witness.Witn
 		close(chConstraintOrdering)
 	}()
 
-	if err := <-chConstraintOrdering; err != nil {
-		return nil, err
-	}
+	errOrdering := <-chConstraintOrdering
 	<-chConstraintInd
+	if errOrdering != nil {
+		return nil, errOrdering
+	}
+
 	// compute h in canonical form
-	h1, h2, h3 := computeH(pk, constraintsInd, constraintsOrdering, evalBZ, alpha)
+	h1, h2, h3, err := computeH(ctx, pk, constraintsInd, constraintsOrdering, evalBZ, alpha)
+	if err != nil {
+		return nil, err
+	}
+	p.Done(backend.PhaseH)
 
 	// compute kzg commitments of h1, h2 and h3
-	if err := commitToH(h1, h2, h3, proof, pk.Vk.KZGSRS); err != nil {
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
+	if err := commitToH(h1, h2, h3, proof, pk.Vk.KZGSRS, opt.NbTasks); err != nil {
+		return nil, err
+	}
+	p.Done(backend.PhaseCommitments)
 
 	// derive zeta
 	zeta, err := deriveRandomness(&fs, "zeta", &proof.H[0], &proof.H[1], &proof.H[2])
@@ -201,6 +233,10 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls24_315witness.Witn
 		return nil, err
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// compute evaluations of (blinded version of) l, r, o, z at zeta
 	var blzeta, brzeta, bozeta fr.Element
 	var wgZetaEvals sync.WaitGroup
@@ -228,6 +264,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls24_315witness.Witn
 		pk.Vk.KZGSRS,
 	)
 	if err != nil {
+		wgZetaEvals.Wait()
 		return nil, err
 	}
 
@@ -258,7 +295,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls24_315witness.Witn
 
 		// TODO this commitment is only necessary to derive the challenge, we should
 		// be able to avoid doing it and get the challenge in another way
-		linearizedPolynomialDigest, errLPoly = kzg.Commit(linearizedPolynomial, pk.Vk.KZGSRS)
+		linearizedPolynomialDigest, errLPoly = kzg.Commit(linearizedPolynomial, pk.Vk.KZGSRS, opt.NbTasks)
 		close(chLpoly)
 	}()
 
@@ -291,6 +328,9 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls24_315witness.Witn
 	}
 
 	// Batch open the first list of polynomials
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	proof.BatchedProof, err = kzg.BatchOpenSinglePoint(
 		[]polynomial.Polynomial{
 			foldedH,
@@ -318,14 +358,15 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls24_315witness.Witn
 	if err != nil {
 		return nil, err
 	}
+	p.Done(backend.PhaseOpenings)
 
 	return proof, nil
 
 }
 
 // fills proof.LRO with kzg commits of bcl, bcr and bco
-func commitToLRO(bcl, bcr, bco polynomial.Polynomial, proof *Proof, srs *kzg.SRS) error {
-	n := runtime.NumCPU() / 2
+func commitToLRO(bcl, bcr, bco polynomial.Polynomial, proof *Proof, srs *kzg.SRS, nbTasks int) error {
+	n := commitNbTasks(nbTasks)
 	var err0, err1, err2 error
 	chCommit0 := make(chan struct{}, 1)
 	chCommit1 := make(chan struct{}, 1)
@@ -350,8 +391,8 @@ func commitToLRO(bcl, bcr, bco polynomial.Polynomial, proof *Proof, srs *kzg.SRS
 	return err1
 }
 
-func commitToH(h1, h2, h3 polynomial.Polynomial, proof *Proof, srs *kzg.SRS) error {
-	n := runtime.NumCPU() / 2
+func commitToH(h1, h2, h3 polynomial.Polynomial, proof *Proof, srs *kzg.SRS, nbTasks int) error {
+	n := commitNbTasks(nbTasks)
 	var err0, err1, err2 error
 	chCommit0 := make(chan struct{}, 1)
 	chCommit1 := make(chan struct{}, 1)
@@ -376,79 +417,89 @@ func commitToH(h1, h2, h3 polynomial.Polynomial, proof *Proof, srs *kzg.SRS) err
 	return err1
 }
 
+// commitNbTasks returns the number of tasks of each of the 3 commitments computed concurrently
+func commitNbTasks(nbTasks int) int {
+	if nbTasks < 2 {
+		return 1
+	}
+	return nbTasks / 2
+}
+
 // computeBlindedLRO l, r, o in canonical basis with blinding
-func computeBlindedLRO(ll, lr, lo polynomial.Polynomial, domain *fft.Domain) (bcl, bcr, bco polynomial.Polynomial, err error) {
+func computeBlindedLRO(ll, lr, lo polynomial.Polynomial, domain *fft.Domain, bpl, bpr, bpo polynomial.Polynomial) (bcl, bcr, bco polynomial.Polynomial) {
 
 	// note that bcl, bcr and bco reuses cl, cr and co memory
 	cl := make(polynomial.Polynomial, domain.Cardinality, domain.Cardinality+2)
 	cr := make(polynomial.Polynomial, domain.Cardinality, domain.Cardinality+2)
 	co := make(polynomial.Polynomial, domain.Cardinality, domain.Cardinality+2)
 
-	chDone := make(chan error, 2)
+	chDone := make(chan struct{}, 2)
 
 	go func() {
-		var err error
 		copy(cl, ll)
 		domain.FFTInverse(cl, fft.DIF, 0)
 		fft.BitReverse(cl)
-		bcl, err = blindPoly(cl, domain.Cardinality, 1)
-		chDone <- err
+		bcl = blindPoly(cl, domain.Cardinality, bpl)
+		chDone <- struct{}{}
 	}()
 	go func() {
-		var err error
 		copy(cr, lr)
 		domain.FFTInverse(cr, fft.DIF, 0)
 		fft.BitReverse(cr)
-		bcr, err = blindPoly(cr, domain.Cardinality, 1)
-		chDone <- err
+		bcr = blindPoly(cr, domain.Cardinality, bpr)
+		chDone <- struct{}{}
 	}()
 	copy(co, lo)
 	domain.FFTInverse(co, fft.DIF, 0)
 	fft.BitReverse(co)
-	if bco, err = blindPoly(co, domain.Cardinality, 1); err != nil {
-		return
-	}
-	err = <-chDone
-	if err != nil {
-		return
-	}
-	err = <-chDone
+	bco = blindPoly(co, domain.Cardinality, bpo)
+	<-chDone
+	<-chDone
 	return
 
 }
 
-// blindPoly blinds a polynomial by adding a Q(X)*(X**degree-1), where deg Q = order.
+// randomPolynomials returns random polynomials of the given degrees, whose coefficients are read from randomSource
+func randomPolynomials(randomSource io.Reader, degrees ...uint64) ([]polynomial.Polynomial, error) {
+	res := make([]polynomial.Polynomial, len(degrees))
+	for i, d := range degrees {
+		res[i] = make(polynomial.Polynomial, d+1)
+		for j := range res[i] {
+			v, err := rand.Int(randomSource, fr.Modulus())
+			if err != nil {
+				return nil, err
+			}
+			res[i][j].SetBigInt(v)
+		}
+	}
+	return res, nil
+}
+
+// blindPoly blinds a polynomial by adding a Q(X)*(X**degree-1), where Q = blindingPoly.
 //
 // * cp polynomial in canonical form
 // * rou root of unity, meaning the blinding factor is multiple of X**rou-1
-// * bo blinding order,  it's the degree of Q, where the blinding is Q(X)*(X**degree-1)
+// * blindingPoly the random polynomial Q, of degree bo (the blinding order)
 //
 // WARNING:
 // pre condition degree(cp) <= rou + bo
 // pre condition cap(cp) >= int(totalDegree + 1)
-func blindPoly(cp polynomial.Polynomial, rou, bo uint64) (polynomial.Polynomial, error) {
+func blindPoly(cp polynomial.Polynomial, rou uint64, blindingPoly polynomial.Polynomial) polynomial.Polynomial {
 
 	// degree of the blinded polynomial is max(rou+order, cp.Degree)
+	bo := uint64(len(blindingPoly) - 1)
 	totalDegree := rou + bo
 
 	// re-use cp
 	res := cp[:totalDegree+1]
 
-	// random polynomial
-	blindingPoly := make(polynomial.Polynomial, bo+1)
-	for i := uint64(0); i < bo+1; i++ {
-		if _, err := blindingPoly[i].SetRandom(); err != nil {
-			return nil, err
-		}
-	}
-
 	// blinding
 	for i := uint64(0); i < bo+1; i++ {
 		res[i].Sub(&res[i], &blindingPoly[i])
 		res[rou+i].Add(&res[rou+i], &blindingPoly[i])
 	}
 
-	return res, nil
+	return res
 }
 
 // computeLRO extracts the solution l, r, o, and returns it in lagrange form.
@@ -500,7 +551,7 @@ func computeLRO(spr *cs.SparseR1CS, pk *ProvingKey, solution []fr.Element) (poly
 //								     (l_i+s1+gamma)*(r_i+s2+gamma)*(o_i+s3+gamma)
 //
 //	* l, r, o are the solution in Lagrange basis
-func computeBlindedZ(l, r, o polynomial.Polynomial, pk *ProvingKey, gamma fr.Element) (polynomial.Polynomial, error) {
+func computeBlindedZ(l, r, o polynomial.Polynomial, pk *ProvingKey, gamma fr.Element, blindingPoly polynomial.Polynomial) polynomial.Polynomial {
 
 	// note that z has more capacity has its memory is reused for blinded z later on
 	z := make(polynomial.Polynomial, pk.DomainNum.Cardinality, pk.DomainNum.Cardinality+3)
@@ -548,7 +599,7 @@ func computeBlindedZ(l, r, o polynomial.Polynomial, pk *ProvingKey, gamma fr.Ele
 	pk.DomainNum.FFTInverse(z, fft.DIF, 0)
 	fft.BitReverse(z)
 
-	return blindPoly(z, pk.DomainNum.Cardinality, 2)
+	return blindPoly(z, pk.DomainNum.Cardinality, blindingPoly)
 
 }
 
@@ -719,10 +770,13 @@ func evaluateHDomain(poly []fr.Element, domainH *fft.Domain) []fr.Element {
 //
 // qlL+qrR+qmL.R+qoO+k + alpha.(zu*g1*g2*g3*l-z*f1*f2*f3*l) + alpha**2*L1*(z-1)= h.Z
 // \------------------/         \------------------------/             \-----/
-//    constraintsInd			    constraintOrdering					startsAtOne
+//
+//	constraintsInd			    constraintOrdering					startsAtOne
 //
 // constraintInd, constraintOrdering are evaluated on the odd cosets of (Z/8mZ)/(Z/mZ)
-func computeH(pk *ProvingKey, constraintsInd, constraintOrdering, evalBZ polynomial.Polynomial, alpha fr.Element) (polynomial.Polynomial, polynomial.Polynomial, polynomial.Polynomial) {
+//
+// computeH returns ctx.Err() if ctx is done between two FFT steps
+func computeH(ctx context.Context, pk *ProvingKey, constraintsInd, constraintOrdering, evalBZ polynomial.Polynomial, alpha fr.Element) (polynomial.Polynomial, polynomial.Polynomial, polynomial.Polynomial, error) {
 
 	h := make(polynomial.Polynomial, pk.DomainH.Cardinality)
 
@@ -770,6 +824,9 @@ func computeH(pk *ProvingKey, constraintsInd, constraintOrdering, evalBZ polynom
 	// evaluates L1 on the odd cosets of (Z/8mZ)/(Z/mZ)
 	// / ! \ note that we scaled by the coset in the previous loop, hence we pass 0 as coset here.
 	pk.DomainH.FFT(startsAtOne, fft.DIF, 0)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, nil, err
+	}
 
 	// evaluate qlL+qrR+qmL.R+qoO+k + alpha.(zu*g1*g2*g3*l-z*f1*f2*f3*l) + alpha**2*L1(X)(Z(X)-1)
 	// on the odd cosets of (Z/8mZ)/(Z/mZ)
@@ -793,6 +850,10 @@ func computeH(pk *ProvingKey, constraintsInd, constraintOrdering, evalBZ polynom
 		}
 	})
 
+	if err := ctx.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
 	// put h in canonical form. h is of degree 3*(n+1)+2.
 	// using fft.DIT put h revert bit reverse
 	pk.DomainH.FFTInverse(h, fft.DIT, 1)
@@ -807,7 +868,7 @@ func computeH(pk *ProvingKey, constraintsInd, constraintOrdering, evalBZ polynom
 	h2 := h[pk.DomainNum.Cardinality+2 : 2*(pk.DomainNum.Cardinality+2)]
 	h3 := h[2*(pk.DomainNum.Cardinality+2) : 3*(pk.DomainNum.Cardinality+2)]
 
-	return h1, h2, h3
+	return h1, h2, h3, nil
 
 }
 
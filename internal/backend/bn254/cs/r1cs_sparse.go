@@ -17,12 +17,16 @@
 package cs
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/fxamacker/cbor/v2"
 	"io"
 	"math/big"
+	"strings"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/internal/backend/compiled"
 	"github.com/consensys/gnark/internal/backend/ioutils"
 
@@ -159,7 +163,7 @@ func (cs *SparseR1CS) solveConstraint(c compiled.SparseR1C, wireInstantiated []b
 		} else if lro == 1 { // we solve for R: u1L+u2R+u3LR+u4O+k=0 => R(u2+u3L)+u1L+u4O+k = 0
 
 			var u1, u2, u3, den, num, v1, v2 fr.Element
-			u3.Mul(&cs.Coefficients[c.M[0].VariableID()], &cs.Coefficients[c.M[1].VariableID()])
+			u3.Mul(&cs.Coefficients[c.M[0].CoeffID()], &cs.Coefficients[c.M[1].CoeffID()])
 			u1.Set(&cs.Coefficients[c.L.CoeffID()])
 			u2.Set(&cs.Coefficients[c.R.CoeffID()])
 			den.Mul(&u3, &solution[c.L.VariableID()]).Add(&den, &u2)
@@ -169,8 +173,8 @@ func (cs *SparseR1CS) solveConstraint(c compiled.SparseR1C, wireInstantiated []b
 			num.Add(&v1, &v2).Add(&num, &cs.Coefficients[c.K])
 
 			// TODO find a way to do lazy div (/ batch inversion)
-			solution[c.L.VariableID()].Div(&num, &den).Neg(&solution[c.L.VariableID()])
-			wireInstantiated[c.L.VariableID()] = true
+			solution[c.R.VariableID()].Div(&num, &den).Neg(&solution[c.R.VariableID()])
+			wireInstantiated[c.R.VariableID()] = true
 
 		} else { // O we solve for O
 			var o fr.Element
@@ -211,11 +215,34 @@ func (cs *SparseR1CS) solveConstraint(c compiled.SparseR1C, wireInstantiated []b
 
 // IsSolved returns nil if given witness solves the R1CS and error otherwise
 // this method wraps r1cs.Solve() and allocates r1cs.Solve() inputs
-func (cs *SparseR1CS) IsSolved(witness []fr.Element) error {
-	_, err := cs.Solve(witness)
+func (cs *SparseR1CS) IsSolved(witness []fr.Element, opt backend.ProverConfig) error {
+	_, err := cs.Solve(witness, opt)
 	return err
 }
 
+// SolveWires reads the full witness [public | secret] from fullWitness (see backend/witness.WriteFullTo),
+// solves the SparseR1CS and returns the values of all the wires, labeled (see compiled.SparseR1CS.WireLabels)
+//
+// The solver runs in diagnostic mode: if the witness doesn't solve the SparseR1CS, all the wires are computed
+// nonetheless and returned along with the solver error, to investigate the unsatisfied constraints.
+func (cs *SparseR1CS) SolveWires(fullWitness io.Reader, opts ...backend.ProverOption) (backend.Wires, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	witness, err := readWitness(fullWitness, int(cs.NbPublicVariables+cs.NbSecretVariables))
+	if err != nil {
+		return nil, err
+	}
+
+	opt.Diagnostics = true
+	solution, wireInstantiated, err := cs.solve(context.Background(), witness, opt)
+	if err != nil && !errors.Is(err, ErrUnsatisfiedConstraint) {
+		return nil, err
+	}
+	return toWires(solution, wireInstantiated, cs.WireLabels()), err
+}
+
 // checkConstraint verifies that the constraint holds
 func (cs *SparseR1CS) checkConstraint(c compiled.SparseR1C, solution []fr.Element) error {
 	l := cs.computeTerm(c.L, solution)
@@ -238,11 +265,30 @@ func (cs *SparseR1CS) checkConstraint(c compiled.SparseR1C, solution []fr.Elemen
 // wireValues =  [publicInputs | secretInputs | internalVariables ]
 // witness: contains the input variables
 // it returns the full slice of wires
-func (cs *SparseR1CS) Solve(witness []fr.Element) (solution []fr.Element, err error) {
+// the logs of the circuit are printed on opt.LoggerOut
+func (cs *SparseR1CS) Solve(witness []fr.Element, opt backend.ProverConfig) (solution []fr.Element, err error) {
+	return cs.SolveContext(context.Background(), witness, opt)
+}
+
+// SolveContext behaves like Solve, but stops and returns ctx.Err() if ctx is done before all the
+// constraints are processed
+//
+// If opt.NbTasks > 1 and the levels of the constraints are known (see compiled.SparseR1CS.BuildLevels), the
+// constraints of each level are solved concurrently. The result is the same as the sequential solver's.
+//
+// If opt.Diagnostics is set, the solver doesn't stop at the first unsatisfied constraint and returns
+// a *backend.UnsatisfiedConstraintsError listing all of them.
+func (cs *SparseR1CS) SolveContext(ctx context.Context, witness []fr.Element, opt backend.ProverConfig) (solution []fr.Element, err error) {
+	solution, _, err = cs.solve(ctx, witness, opt)
+	return solution, err
+}
+
+// solve implements SolveContext; it also returns wireInstantiated, set for the wires which have a value
+func (cs *SparseR1CS) solve(ctx context.Context, witness []fr.Element, opt backend.ProverConfig) (solution []fr.Element, wireInstantiated []bool, err error) {
 
 	expectedWitnessSize := int(cs.NbPublicVariables + cs.NbSecretVariables)
 	if len(witness) != expectedWitnessSize {
-		return nil, fmt.Errorf(
+		return nil, nil, fmt.Errorf(
 			"invalid witness size, got %d, expected %d = %d (public) + %d (secret)",
 			len(witness),
 			expectedWitnessSize,
@@ -254,7 +300,7 @@ func (cs *SparseR1CS) Solve(witness []fr.Element) (solution []fr.Element, err er
 	// set the slices holding the solution and monitoring which variables have been solved
 	nbVariables := cs.NbInternalVariables + cs.NbSecretVariables + cs.NbPublicVariables
 	solution = make([]fr.Element, nbVariables)
-	wireInstantiated := make([]bool, nbVariables)
+	wireInstantiated = make([]bool, nbVariables)
 
 	// solution = [publicInputs | secretInputs | internalVariables ] -> we fill publicInputs | secretInputs
 	copy(solution, witness)
@@ -263,51 +309,105 @@ func (cs *SparseR1CS) Solve(witness []fr.Element) (solution []fr.Element, err er
 	}
 
 	// defer log printing once all wireValues are computed
-	defer cs.printLogs(solution, wireInstantiated)
+	defer printLogs(cs.Logs, solution, wireInstantiated, opt)
 
 	coefficientsNegInv := fr.BatchInvert(cs.Coefficients)
 	for i := 0; i < len(coefficientsNegInv); i++ {
 		coefficientsNegInv[i].Neg(&coefficientsNegInv[i])
 	}
 
-	// loop through the constraints to solve the variables
-	for i := 0; i < len(cs.Constraints); i++ {
+	// fail is called when the constraint id (in [Constraints | Assertions]) is not satisfied; outside of diagnostic mode,
+	// it returns the solver error, otherwise it records the constraint in unsatisfied and returns nil so the solver continues
+	var unsatisfied []backend.UnsatisfiedConstraint
+	fail := func(id int) error {
+		debugInfo := cs.debugInfo(id, solution, wireInstantiated)
+		if !opt.Diagnostics {
+			return fmt.Errorf("constraint #%d: %w: %s", id, ErrUnsatisfiedConstraint, debugInfo)
+		}
+		unsatisfied = append(unsatisfied, backend.UnsatisfiedConstraint{ID: id, Debug: debugInfo})
+		return nil
+	}
+
+	// solve the variables of the constraint i
+	solve := func(i int) error {
 		cs.solveConstraint(cs.Constraints[i], wireInstantiated, solution, coefficientsNegInv)
-		err = cs.checkConstraint(cs.Constraints[i], solution)
-		if err != nil {
-			fmt.Printf("%d-th constraint\n", i)
-			return solution, err
+		if cs.checkConstraint(cs.Constraints[i], solution) != nil {
+			return fail(i)
+		}
+		return nil
+	}
+
+	// in diagnostic mode, the unsatisfied constraints are recorded in order: the constraints are solved sequentially
+	if opt.NbTasks > 1 && len(cs.Levels) != 0 && !opt.Diagnostics {
+		if err = solveLevels(ctx, cs.Levels, opt.NbTasks, solve); err != nil {
+			return solution, wireInstantiated, err
+		}
+	} else {
+		for i := 0; i < len(cs.Constraints); i++ {
+			if i%solveCheckInterval == 0 {
+				if err = ctx.Err(); err != nil {
+					return solution, wireInstantiated, err
+				}
+			}
+			if err = solve(i); err != nil {
+				return solution, wireInstantiated, err
+			}
 		}
 	}
 
 	// loop through the assertions and check consistency
 	for i := 0; i < len(cs.Assertions); i++ {
-		err = cs.checkConstraint(cs.Assertions[i], solution)
-		if err != nil {
-			return solution, err
+		if i%solveCheckInterval == 0 {
+			if err = ctx.Err(); err != nil {
+				return solution, wireInstantiated, err
+			}
 		}
+		if cs.checkConstraint(cs.Assertions[i], solution) != nil {
+			if err = fail(len(cs.Constraints) + i); err != nil {
+				return solution, wireInstantiated, err
+			}
+		}
+	}
+
+	if len(unsatisfied) != 0 {
+		return solution, wireInstantiated, &backend.UnsatisfiedConstraintsError{Err: ErrUnsatisfiedConstraint, Constraints: unsatisfied}
 	}
 
-	return solution, nil
+	return solution, wireInstantiated, nil
 
 }
 
-func logValue(entry compiled.LogEntry, wireValues []fr.Element, wireInstantiated []bool) string {
-	var toResolve []interface{}
-	for j := 0; j < len(entry.ToResolve); j++ {
-		wireID := entry.ToResolve[j]
-		if !wireInstantiated[wireID] {
-			panic("wire values was not instantiated")
+// debugInfo returns the debug info of the unsatisfied constraint id (in [Constraints | Assertions]),
+// with the wire values resolved, followed by the values of its terms
+func (cs *SparseR1CS) debugInfo(id int, solution []fr.Element, wireInstantiated []bool) string {
+	var sbb strings.Builder
+	var c compiled.SparseR1C
+	debugInfoID := -1
+	if id < len(cs.Constraints) {
+		c = cs.Constraints[id]
+		if id < len(cs.ConstraintsDebugInfo) {
+			debugInfoID = cs.ConstraintsDebugInfo[id]
+		}
+		sbb.WriteString("couldn't solve computational constraint. May happen: div by 0 or no inverse found")
+	} else {
+		id -= len(cs.Constraints)
+		c = cs.Assertions[id]
+		if id < len(cs.AssertionsDebugInfo) {
+			debugInfoID = cs.AssertionsDebugInfo[id]
 		}
-		toResolve = append(toResolve, wireValues[wireID].String())
 	}
-	return fmt.Sprintf(entry.Format, toResolve...)
-}
-
-func (cs *SparseR1CS) printLogs(wireValues []fr.Element, wireInstantiated []bool) {
-
-	// for each log, resolve the wire values and print the log to stdout
-	for i := 0; i < len(cs.Logs); i++ {
-		fmt.Print(logValue(cs.Logs[i], wireValues, wireInstantiated))
+	if debugInfoID != -1 {
+		if sbb.Len() != 0 {
+			sbb.WriteByte('\n')
+		}
+		sbb.WriteString(logValue(cs.DebugInfo[debugInfoID], solution, wireInstantiated))
 	}
+
+	l := cs.computeTerm(c.L, solution)
+	r := cs.computeTerm(c.R, solution)
+	m0 := cs.computeTerm(c.M[0], solution)
+	m1 := cs.computeTerm(c.M[1], solution)
+	o := cs.computeTerm(c.O, solution)
+	fmt.Fprintf(&sbb, "\n%s + %s + %s ⋅ %s + %s + %s != 0", l.String(), r.String(), m0.String(), m1.String(), o.String(), cs.Coefficients[c.K].String())
+	return sbb.String()
 }
@@ -18,14 +18,22 @@ package cs_test
 
 import (
 	"bytes"
+	"errors"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/internal/backend/circuits"
+	"github.com/consensys/gnark/internal/backend/compiled"
+	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
 	"github.com/consensys/gnark/internal/backend/bn254/cs"
+
+	bn254witness "github.com/consensys/gnark/internal/backend/bn254/witness"
 )
 
 func TestSerialization(t *testing.T) {
@@ -48,9 +56,6 @@ func TestSerialization(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		// no need to serialize.
-		r1cs.SetLoggerOutput(nil)
-		r1cs2.SetLoggerOutput(nil)
 		{
 			buffer.Reset()
 			t.Log(name)
@@ -114,3 +119,198 @@ func TestSerialization(t *testing.T) {
 		}
 	}
 }
+
+// wideCircuit has levels large enough to be solved concurrently
+type wideCircuit struct {
+	X [256]frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *wideCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cubes := make([]interface{}, len(circuit.X))
+	for i := range circuit.X {
+		cubes[i] = cs.Mul(circuit.X[i], circuit.X[i], circuit.X[i])
+	}
+	cs.AssertIsEqual(circuit.Y, cs.Add(cubes[0], cubes[1], cubes[2:]...))
+	return nil
+}
+
+func TestParallelSolve(t *testing.T) {
+	var wide, wideWitness wideCircuit
+	sum := 0
+	for i := range wideWitness.X {
+		wideWitness.X[i].Assign(i + 1)
+		sum += (i + 1) * (i + 1) * (i + 1)
+	}
+	wideWitness.Y.Assign(sum)
+
+	testCircuits := map[string]circuits.TestCircuit{"wide": {Circuit: &wide, Good: &wideWitness}}
+	for name, circuit := range circuits.Circuits {
+		testCircuits[name] = circuit
+	}
+
+	sequential := backend.ProverConfig{NbTasks: 1}
+	parallel := backend.ProverConfig{NbTasks: 4}
+
+	for name, circuit := range testCircuits {
+		for _, assignment := range []frontend.Circuit{circuit.Good, circuit.Bad} {
+			if assignment == nil {
+				continue
+			}
+			w := bn254witness.Witness{}
+			if err := w.FromFullAssignment(assignment); err != nil {
+				t.Fatal(err)
+			}
+
+			r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, circuit.Circuit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(r1cs.(*cs.R1CS).Levels) == 0 && r1cs.(*cs.R1CS).NbCOConstraints != 0 {
+				t.Fatal(name, "levels of the R1CS are not set")
+			}
+			var values [2][4][]fr.Element
+			var errs [2]error
+			for i, opt := range []backend.ProverConfig{sequential, parallel} {
+				nbConstraints := r1cs.GetNbConstraints()
+				internal, secret, public := r1cs.GetNbVariables()
+				for j := 0; j < 3; j++ {
+					values[i][j] = make([]fr.Element, nbConstraints)
+				}
+				values[i][3] = make([]fr.Element, internal+secret+public)
+				errs[i] = r1cs.(*cs.R1CS).Solve(w, values[i][0], values[i][1], values[i][2], values[i][3], opt)
+			}
+			if (errs[0] == nil) != (errs[1] == nil) || (errs[0] != nil && errs[0].Error() != errs[1].Error()) {
+				t.Fatal(name, "parallel R1CS solver error differs:", errs[0], errs[1])
+			}
+			if errs[0] == nil && !reflect.DeepEqual(values[0], values[1]) {
+				t.Fatal(name, "parallel R1CS solver result differs")
+			}
+
+			sparseR1CS, err := frontend.Compile(ecc.BN254, backend.PLONK, circuit.Circuit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var solutions [2][]fr.Element
+			for i, opt := range []backend.ProverConfig{sequential, parallel} {
+				solutions[i], errs[i] = sparseR1CS.(*cs.SparseR1CS).Solve(w, opt)
+			}
+			if (errs[0] == nil) != (errs[1] == nil) || (errs[0] != nil && errs[0].Error() != errs[1].Error()) {
+				t.Fatal(name, "parallel SparseR1CS solver error differs:", errs[0], errs[1])
+			}
+			if errs[0] == nil && !reflect.DeepEqual(solutions[0], solutions[1]) {
+				t.Fatal(name, "parallel SparseR1CS solver result differs")
+			}
+		}
+	}
+}
+
+// TestSparseSolveR checks that the sparse solver computes R when it is the only unsolved wire of a constraint
+func TestSparseSolveR(t *testing.T) {
+	// L + R + 2LR - 17 = 0, with L = 2 (secret) and R = 3 (internal)
+	sparseR1CS := cs.NewSparseR1CS(compiled.SparseR1CS{
+		NbSecretVariables:   1,
+		NbInternalVariables: 1,
+		Constraints: []compiled.SparseR1C{
+			{
+				L: compiled.Pack(0, compiled.CoeffIdOne, compiled.Secret),
+				R: compiled.Pack(1, compiled.CoeffIdOne, compiled.Internal),
+				M: [2]compiled.Term{
+					compiled.Pack(0, compiled.CoeffIdOne, compiled.Secret),
+					compiled.Pack(1, compiled.CoeffIdTwo, compiled.Internal),
+				},
+				K:      4,
+				Solver: compiled.SingleOutput,
+			},
+		},
+	}, []big.Int{*big.NewInt(0), *big.NewInt(1), *big.NewInt(2), *big.NewInt(-1), *big.NewInt(-17)})
+
+	var l fr.Element
+	l.SetUint64(2)
+	solution, err := sparseR1CS.Solve([]fr.Element{l}, backend.ProverConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected fr.Element
+	expected.SetUint64(3)
+	if !solution[0].Equal(&l) || !solution[1].Equal(&expected) {
+		t.Fatal("unexpected solution", solution[0].String(), solution[1].String())
+	}
+}
+
+type diagnosticCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (circuit *diagnosticCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(cs.Inverse(circuit.X), circuit.Y)
+	cs.AssertIsEqual(circuit.X, circuit.Y)
+	cs.AssertIsBoolean(circuit.Y)
+	return nil
+}
+
+func TestDiagnostics(t *testing.T) {
+	var circuit, witness diagnosticCircuit
+	witness.X.Assign(0) // no inverse
+	witness.Y.Assign(2) // all the assertions fail
+	w := bn254witness.Witness{}
+	if err := w.FromFullAssignment(&witness); err != nil {
+		t.Fatal(err)
+	}
+
+	diagnostics := backend.ProverConfig{NbTasks: 4, Diagnostics: true}
+
+	// checkErr checks that err lists the 4 unsatisfied constraints, the first one being the inverse
+	checkErr := func(name string, err error) {
+		var unsatisfied *backend.UnsatisfiedConstraintsError
+		if !errors.As(err, &unsatisfied) {
+			t.Fatal(name, "expected an UnsatisfiedConstraintsError, got", err)
+		}
+		if !errors.Is(err, cs.ErrUnsatisfiedConstraint) {
+			t.Fatal(name, "error doesn't wrap ErrUnsatisfiedConstraint")
+		}
+		if len(unsatisfied.Constraints) != 4 {
+			t.Fatal(name, "expected 4 unsatisfied constraints, got", len(unsatisfied.Constraints))
+		}
+		for i, c := range unsatisfied.Constraints {
+			if i > 0 && c.ID <= unsatisfied.Constraints[i-1].ID {
+				t.Fatal(name, "unsatisfied constraints are not in order")
+			}
+			if !strings.Contains(c.Debug, "r1cs_test.go") {
+				t.Fatal(name, "debug info doesn't contain the source location:", c.Debug)
+			}
+		}
+		if !strings.Contains(unsatisfied.Constraints[0].Debug, "Inverse") {
+			t.Fatal(name, "the first unsatisfied constraint should be the inverse:", unsatisfied.Constraints[0].Debug)
+		}
+	}
+
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit, frontend.WithDebugInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r1cs.(*cs.R1CS).ConstraintsDebugInfo) != r1cs.GetNbConstraints() {
+		t.Fatal("R1CS debug info is not set for all constraints")
+	}
+	nbConstraints := r1cs.GetNbConstraints()
+	internal, secret, public := r1cs.GetNbVariables()
+	a := make([]fr.Element, nbConstraints)
+	b := make([]fr.Element, nbConstraints)
+	c := make([]fr.Element, nbConstraints)
+	wireValues := make([]fr.Element, internal+secret+public)
+	checkErr("R1CS", r1cs.(*cs.R1CS).Solve(w, a, b, c, wireValues, diagnostics))
+
+	// outside of diagnostic mode, the solver stops at the first unsatisfied constraint
+	err = r1cs.(*cs.R1CS).Solve(w, a, b, c, wireValues, backend.ProverConfig{NbTasks: 1})
+	if !errors.Is(err, cs.ErrUnsatisfiedConstraint) || !strings.Contains(err.Error(), "Inverse") {
+		t.Fatal("R1CS solver should fail on the inverse, got", err)
+	}
+
+	sparseR1CS, err := frontend.Compile(ecc.BN254, backend.PLONK, &circuit, frontend.WithDebugInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = sparseR1CS.(*cs.SparseR1CS).Solve(w, diagnostics)
+	checkErr("SparseR1CS", err)
+}
@@ -17,16 +17,21 @@
 package cs
 
 import (
+	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"math/big"
-	"os"
+	"strings"
+	"sync"
 
 	"github.com/fxamacker/cbor/v2"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/internal/backend/compiled"
 	"github.com/consensys/gnark/internal/backend/ioutils"
+	"github.com/consensys/gnark/internal/utils"
 
 	"github.com/consensys/gnark-crypto/ecc"
 
@@ -36,11 +41,17 @@ import (
 // ErrUnsatisfiedConstraint can be generated when solving a R1CS
 var ErrUnsatisfiedConstraint = errors.New("constraint is not satisfied")
 
+// solveCheckInterval is the number of constraints processed between two checks of the context in SolveContext
+const solveCheckInterval = 1 << 14
+
+// minParallelLevelSize is the minimum number of constraints in a level for the level to be solved concurrently;
+// smaller levels are solved sequentially, as the cost of the goroutines would exceed the gain
+const minParallelLevelSize = 64
+
 // R1CS decsribes a set of R1CS constraint
 type R1CS struct {
 	compiled.R1CS
 	Coefficients []fr.Element // R1C coefficients indexes point here
-	loggerOut    io.Writer
 }
 
 // NewR1CS returns a new R1CS and sets r1cs.Coefficient (fr.Element) from provided big.Int values
@@ -48,7 +59,6 @@ func NewR1CS(r1cs compiled.R1CS, coefficients []big.Int) *R1CS {
 	r := R1CS{
 		r1cs,
 		make([]fr.Element, len(coefficients)),
-		os.Stdout,
 	}
 	for i := 0; i < len(coefficients); i++ {
 		r.Coefficients[i].SetBigInt(&coefficients[i])
@@ -71,13 +81,6 @@ func (r1cs *R1CS) FrSize() int {
 	return fr.Limbs * 8
 }
 
-// SetLoggerOutput replace existing logger output with provided one
-// default uses os.Stdout
-// if nil is provided, logs are not printed
-func (r1cs *R1CS) SetLoggerOutput(w io.Writer) {
-	r1cs.loggerOut = w
-}
-
 // WriteTo encodes R1CS into provided io.Writer using cbor
 func (r1cs *R1CS) WriteTo(w io.Writer) (int64, error) {
 	_w := ioutils.WriterCounter{W: w} // wraps writer to count the bytes written
@@ -101,12 +104,74 @@ func (r1cs *R1CS) ReadFrom(r io.Reader) (int64, error) {
 
 // IsSolved returns nil if given witness solves the R1CS and error otherwise
 // this method wraps r1cs.Solve() and allocates r1cs.Solve() inputs
-func (r1cs *R1CS) IsSolved(witness []fr.Element) error {
+func (r1cs *R1CS) IsSolved(witness []fr.Element, opt backend.ProverConfig) error {
 	a := make([]fr.Element, r1cs.NbConstraints)
 	b := make([]fr.Element, r1cs.NbConstraints)
 	c := make([]fr.Element, r1cs.NbConstraints)
 	wireValues := make([]fr.Element, r1cs.NbInternalVariables+r1cs.NbPublicVariables+r1cs.NbSecretVariables)
-	return r1cs.Solve(witness, a, b, c, wireValues)
+	return r1cs.Solve(witness, a, b, c, wireValues, opt)
+}
+
+// SolveWires reads the full witness [public | secret] from fullWitness (see backend/witness.WriteFullTo),
+// solves the R1CS and returns the values of all the wires, labeled (see compiled.R1CS.WireLabels)
+//
+// The solver runs in diagnostic mode: if the witness doesn't solve the R1CS, all the wires are computed
+// nonetheless and returned along with the solver error, to investigate the unsatisfied constraints.
+func (r1cs *R1CS) SolveWires(fullWitness io.Reader, opts ...backend.ProverOption) (backend.Wires, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	witness, err := readWitness(fullWitness, int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables)) // - 1 for ONE_WIRE
+	if err != nil {
+		return nil, err
+	}
+
+	nbWires := r1cs.NbInternalVariables + r1cs.NbPublicVariables + r1cs.NbSecretVariables
+	a := make([]fr.Element, r1cs.NbConstraints)
+	b := make([]fr.Element, r1cs.NbConstraints)
+	c := make([]fr.Element, r1cs.NbConstraints)
+	wireValues := make([]fr.Element, nbWires)
+	wireInstantiated := make([]bool, nbWires)
+	opt.Diagnostics = true
+	err = r1cs.solve(context.Background(), witness, a, b, c, wireValues, wireInstantiated, opt)
+	if err != nil && !errors.Is(err, ErrUnsatisfiedConstraint) {
+		return nil, err
+	}
+	return toWires(wireValues, wireInstantiated, r1cs.WireLabels()), err
+}
+
+// toWires returns the wires with the given values and labels; the wires which are not instantiated are
+// marked as not solved, and have no value
+func toWires(wireValues []fr.Element, wireInstantiated []bool, labels []string) backend.Wires {
+	wires := make(backend.Wires, len(wireValues))
+	for i := 0; i < len(wireValues); i++ {
+		wires[i] = backend.Wire{ID: i, Label: labels[i], Solved: wireInstantiated[i]}
+		if wireInstantiated[i] {
+			wires[i].Value = wireValues[i].String()
+		}
+	}
+	return wires
+}
+
+// readWitness reads a witness of expectedSize elements from r, in the binary format of the witness
+// (the number of elements as a uint32, followed by the elements)
+func readWitness(r io.Reader, expectedSize int) ([]fr.Element, error) {
+	var buf [fr.Limbs * 8]byte
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return nil, err
+	}
+	if size := binary.BigEndian.Uint32(buf[:4]); int(size) != expectedSize {
+		return nil, fmt.Errorf("invalid witness size, got %d, expected %d", size, expectedSize)
+	}
+	witness := make([]fr.Element, expectedSize)
+	for i := 0; i < expectedSize; i++ {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		witness[i].SetBytes(buf[:])
+	}
+	return witness, nil
 }
 
 // Solve sets all the wires and returns the a, b, c vectors.
@@ -115,7 +180,25 @@ func (r1cs *R1CS) IsSolved(witness []fr.Element) error {
 // a, b, c vectors: ab-c = hz
 // wireValues =  [publicWires | secretWires | internalWires ]
 // witness = [publicWires | secretWires] (without the ONE_WIRE !)
-func (r1cs *R1CS) Solve(witness []fr.Element, a, b, c, wireValues []fr.Element) error {
+// the logs of the circuit are printed on opt.LoggerOut
+func (r1cs *R1CS) Solve(witness []fr.Element, a, b, c, wireValues []fr.Element, opt backend.ProverConfig) error {
+	return r1cs.SolveContext(context.Background(), witness, a, b, c, wireValues, opt)
+}
+
+// SolveContext behaves like Solve, but stops and returns ctx.Err() if ctx is done before all the
+// constraints are processed
+//
+// If opt.NbTasks > 1 and the levels of the constraints are known (see compiled.R1CS.BuildLevels), the
+// computational constraints of each level are solved concurrently. The result is the same as the sequential solver's.
+//
+// If opt.Diagnostics is set, the solver doesn't stop at the first unsatisfied constraint and returns
+// a *backend.UnsatisfiedConstraintsError listing all of them.
+func (r1cs *R1CS) SolveContext(ctx context.Context, witness []fr.Element, a, b, c, wireValues []fr.Element, opt backend.ProverConfig) error {
+	return r1cs.solve(ctx, witness, a, b, c, wireValues, make([]bool, len(wireValues)), opt)
+}
+
+// solve implements SolveContext; wireInstantiated is set for the wires which have a value
+func (r1cs *R1CS) solve(ctx context.Context, witness []fr.Element, a, b, c, wireValues []fr.Element, wireInstantiated []bool, opt backend.ProverConfig) error {
 	if len(witness) != int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables) { // - 1 for ONE_WIRE
 		return fmt.Errorf("invalid witness size, got %d, expected %d = %d (public - ONE_WIRE) + %d (secret)", len(witness), int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables), r1cs.NbPublicVariables-1, r1cs.NbSecretVariables)
 	}
@@ -125,7 +208,6 @@ func (r1cs *R1CS) Solve(witness []fr.Element, a, b, c, wireValues []fr.Element)
 		return errors.New("invalid input size: len(a, b, c) == r1cs.NbConstraints and len(wireValues) == r1cs.NbWires")
 	}
 	// keep track of wire that have a value
-	wireInstantiated := make([]bool, nbWires)
 	wireInstantiated[0] = true // ONE_WIRE
 	wireValues[0].SetOne()
 	copy(wireValues[1:], witness) // TODO factorize
@@ -135,14 +217,22 @@ func (r1cs *R1CS) Solve(witness []fr.Element, a, b, c, wireValues []fr.Element)
 
 	// now that we know all inputs are set, defer log printing once all wireValues are computed
 	// (or sooner, if a constraint is not satisfied)
-	defer r1cs.printLogs(wireValues, wireInstantiated)
-
-	// check if there is an inconsistant constraint
-	var check fr.Element
-
-	// Loop through computational constraints (the one wwe need to solve and compute a wire in)
-	for i := 0; i < int(r1cs.NbCOConstraints); i++ {
+	defer printLogs(r1cs.Logs, wireValues, wireInstantiated, opt)
+
+	// fail is called when the constraint i is not satisfied; outside of diagnostic mode, it returns
+	// the solver error, otherwise it records the constraint in unsatisfied and returns nil so the solver continues
+	var unsatisfied []backend.UnsatisfiedConstraint
+	fail := func(i int) error {
+		debugInfo := r1cs.debugInfo(i, &a[i], &b[i], &c[i], wireValues, wireInstantiated)
+		if !opt.Diagnostics {
+			return fmt.Errorf("%w: %s", ErrUnsatisfiedConstraint, debugInfo)
+		}
+		unsatisfied = append(unsatisfied, backend.UnsatisfiedConstraint{ID: i, Debug: debugInfo})
+		return nil
+	}
 
+	// solve the computational constraint i (the ones we need to solve and compute a wire in)
+	solve := func(i int) error {
 		// solve the constraint, this will compute the missing wire of the gate
 		r1cs.solveR1C(&r1cs.Constraints[i], wireInstantiated, wireValues)
 
@@ -150,15 +240,43 @@ func (r1cs *R1CS) Solve(witness []fr.Element, a, b, c, wireValues []fr.Element)
 		// if not, it means there is a bug in the solver
 		a[i], b[i], c[i] = instantiateR1C(&r1cs.Constraints[i], r1cs, wireValues)
 
+		var check fr.Element
 		check.Mul(&a[i], &b[i])
 		if !check.Equal(&c[i]) {
-			return fmt.Errorf("%w: %s", ErrUnsatisfiedConstraint, "couldn't solve computational constraint. May happen: div by 0 or no inverse found")
+			return fail(i)
 		}
+		return nil
 	}
 
+	// in diagnostic mode, the unsatisfied constraints are recorded in order: the constraints are solved sequentially
+	if opt.NbTasks > 1 && len(r1cs.Levels) != 0 && !opt.Diagnostics {
+		if err := solveLevels(ctx, r1cs.Levels, opt.NbTasks, solve); err != nil {
+			return err
+		}
+	} else {
+		for i := 0; i < int(r1cs.NbCOConstraints); i++ {
+			if i%solveCheckInterval == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+			if err := solve(i); err != nil {
+				return err
+			}
+		}
+	}
+
+	// check if there is an inconsistant constraint
+	var check fr.Element
+
 	// Loop through the assertions -- here all wireValues should be instantiated
 	// if a[i] * b[i] != c[i]; it means the constraint is not satisfied
 	for i := int(r1cs.NbCOConstraints); i < len(r1cs.Constraints); i++ {
+		if i%solveCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
 
 		// A this stage we are not guaranteed that a[i+sizecg]*b[i+sizecg]=c[i+sizecg] because we only query the values (computed
 		// at the previous step)
@@ -167,38 +285,130 @@ func (r1cs *R1CS) Solve(witness []fr.Element, a, b, c, wireValues []fr.Element)
 		// check that the constraint is satisfied
 		check.Mul(&a[i], &b[i])
 		if !check.Equal(&c[i]) {
-			debugInfo := r1cs.DebugInfo[i-int(r1cs.NbCOConstraints)]
-			debugInfoStr := r1cs.logValue(debugInfo, wireValues, wireInstantiated)
-			return fmt.Errorf("%w: %s", ErrUnsatisfiedConstraint, debugInfoStr)
+			if err := fail(i); err != nil {
+				return err
+			}
 		}
 	}
 
+	if len(unsatisfied) != 0 {
+		return &backend.UnsatisfiedConstraintsError{Err: ErrUnsatisfiedConstraint, Constraints: unsatisfied}
+	}
+
 	return nil
 }
 
-func (r1cs *R1CS) logValue(entry compiled.LogEntry, wireValues []fr.Element, wireInstantiated []bool) string {
-	var toResolve []interface{}
-	for j := 0; j < len(entry.ToResolve); j++ {
-		wireID := entry.ToResolve[j]
+// debugInfo returns the debug info of the unsatisfied constraint i, with the wire values resolved,
+// followed by the values a, b, c of its linear expressions
+func (r1cs *R1CS) debugInfo(i int, a, b, c *fr.Element, wireValues []fr.Element, wireInstantiated []bool) string {
+	var sbb strings.Builder
+	if i < int(r1cs.NbCOConstraints) {
+		sbb.WriteString("couldn't solve computational constraint. May happen: div by 0 or no inverse found")
+	}
+	if i < len(r1cs.ConstraintsDebugInfo) {
+		if sbb.Len() != 0 {
+			sbb.WriteByte('\n')
+		}
+		sbb.WriteString(logValue(r1cs.DebugInfo[r1cs.ConstraintsDebugInfo[i]], wireValues, wireInstantiated))
+	}
+	fmt.Fprintf(&sbb, "\n%s ⋅ %s != %s", a.String(), b.String(), c.String())
+	return sbb.String()
+}
+
+// solveLevels calls solve on the constraints, level by level; the constraints of a level
+// are solved concurrently on nbTasks goroutines.
+//
+// If solve fails, the remaining levels are processed nonetheless, and the error of the first failing constraint
+// in the sequential order is returned: the result is the same as calling solve on the constraints in order.
+func solveLevels(ctx context.Context, levels [][]int, nbTasks int, solve func(i int) error) error {
+	var lock sync.Mutex
+	errID := -1
+	var errSolve error
+
+	solveLevel := func(level []int) {
+		for _, i := range level {
+			if err := solve(i); err != nil {
+				lock.Lock()
+				if errID == -1 || i < errID {
+					errID, errSolve = i, err
+				}
+				lock.Unlock()
+			}
+		}
+	}
+
+	for _, level := range levels {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if len(level) < minParallelLevelSize {
+			solveLevel(level)
+			continue
+		}
+		utils.Parallelize(len(level), func(start, end int) {
+			solveLevel(level[start:end])
+		}, nbTasks)
+	}
+
+	return errSolve
+}
+
+// resolveLogEntry returns the values of the wires of the log entry (nil if not solved) and the formatted log;
+// the condition of a conditional log is not part of the values
+func resolveLogEntry(entry compiled.LogEntry, wireValues []fr.Element, wireInstantiated []bool) ([]*big.Int, string) {
+	wireIDs := entry.ToResolve
+	if entry.Conditional {
+		wireIDs = wireIDs[1:]
+	}
+	values := make([]*big.Int, len(wireIDs))
+	toResolve := make([]interface{}, len(wireIDs))
+	for j, wireID := range wireIDs {
 		if !wireInstantiated[wireID] {
-			toResolve = append(toResolve, "???")
+			toResolve[j] = "???"
 		} else {
-			toResolve = append(toResolve, wireValues[wireID].String())
+			values[j] = new(big.Int)
+			wireValues[wireID].ToBigIntRegular(values[j])
+			toResolve[j] = values[j]
 		}
 	}
-	return fmt.Sprintf(entry.Format, toResolve...)
+	return values, fmt.Sprintf(entry.Format, toResolve...)
+}
+
+// logValue returns the log entry formatted with the values of its wires ("???" if not solved)
+func logValue(entry compiled.LogEntry, wireValues []fr.Element, wireInstantiated []bool) string {
+	_, msg := resolveLogEntry(entry, wireValues, wireInstantiated)
+	return msg
 }
 
-func (r1cs *R1CS) printLogs(wireValues []fr.Element, wireInstantiated []bool) {
+// printLogs resolves the wire values of the logs and sends them to opt.Logger, or prints them to opt.LoggerOut;
+// conditional logs are skipped if their condition is not solved or zero
+func printLogs(logs []compiled.LogEntry, wireValues []fr.Element, wireInstantiated []bool, opt backend.ProverConfig) {
+	if opt.Logger == nil && opt.LoggerOut == nil {
+		return
+	}
 
-	// for each log, resolve the wire values and print the log to stdout
-	for i := 0; i < len(r1cs.Logs); i++ {
-		logLine := r1cs.logValue(r1cs.Logs[i], wireValues, wireInstantiated)
-		if r1cs.loggerOut != nil {
-			if _, err := io.WriteString(r1cs.loggerOut, logLine); err != nil {
-				fmt.Println("error", err.Error())
+	for i := 0; i < len(logs); i++ {
+		if logs[i].Conditional {
+			cond := logs[i].ToResolve[0]
+			if !wireInstantiated[cond] || wireValues[cond].IsZero() {
+				continue
 			}
 		}
+		values, msg := resolveLogEntry(logs[i], wireValues, wireInstantiated)
+		if opt.Logger != nil {
+			opt.Logger.Log(logs[i].Caller, msg, values)
+			continue
+		}
+		logLine := msg
+		if logs[i].Caller != "" {
+			logLine = logs[i].Caller + " " + logLine
+		}
+		if !strings.HasSuffix(logLine, "\n") {
+			logLine += "\n"
+		}
+		if _, err := io.WriteString(opt.LoggerOut, logLine); err != nil {
+			fmt.Println("error", err.Error())
+		}
 	}
 }
 
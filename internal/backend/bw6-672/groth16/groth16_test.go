@@ -111,7 +111,7 @@ func BenchmarkSetup(b *testing.B) {
 
 	b.Run("setup", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			bw6_672groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk)
+			bw6_672groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk, setupConfig())
 		}
 	})
 }
@@ -130,7 +130,7 @@ func BenchmarkProver(b *testing.B) {
 	b.ResetTimer()
 	b.Run("prover", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			_, _ = bw6_672groth16.Prove(r1cs.(*cs.R1CS), &pk, fullWitness, false)
+			_, _ = bw6_672groth16.Prove(r1cs.(*cs.R1CS), &pk, fullWitness, proverConfig())
 		}
 	})
 }
@@ -150,8 +150,8 @@ func BenchmarkVerifier(b *testing.B) {
 
 	var pk bw6_672groth16.ProvingKey
 	var vk bw6_672groth16.VerifyingKey
-	bw6_672groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk)
-	proof, err := bw6_672groth16.Prove(r1cs.(*cs.R1CS), &pk, fullWitness, false)
+	bw6_672groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk, setupConfig())
+	proof, err := bw6_672groth16.Prove(r1cs.(*cs.R1CS), &pk, fullWitness, proverConfig())
 	if err != nil {
 		panic(err)
 	}
@@ -174,8 +174,8 @@ func BenchmarkSerialization(b *testing.B) {
 
 	var pk bw6_672groth16.ProvingKey
 	var vk bw6_672groth16.VerifyingKey
-	bw6_672groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk)
-	proof, err := bw6_672groth16.Prove(r1cs.(*cs.R1CS), &pk, fullWitness, false)
+	bw6_672groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk, setupConfig())
+	proof, err := bw6_672groth16.Prove(r1cs.(*cs.R1CS), &pk, fullWitness, proverConfig())
 	if err != nil {
 		panic(err)
 	}
@@ -335,3 +335,15 @@ func BenchmarkSerialization(b *testing.B) {
 	}
 
 }
+
+// setupConfig returns the default setup configuration
+func setupConfig() backend.SetupConfig {
+	opt, _ := backend.NewSetupConfig()
+	return opt
+}
+
+// proverConfig returns the default prover configuration, without the circuit logs
+func proverConfig() backend.ProverConfig {
+	opt, _ := backend.NewProverConfig(backend.WithLoggerOutput(nil))
+	return opt
+}
@@ -0,0 +1,77 @@
+package circuits
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+type assertIfCircuit struct {
+	X, Y, Z           frontend.Variable
+	Enabled, Disabled frontend.Variable `gnark:",public"`
+}
+
+func (circuit *assertIfCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqualIf(circuit.Enabled, circuit.X, circuit.Y)
+	cs.AssertIsEqualIf(circuit.Disabled, circuit.X, cs.Add(circuit.Y, 1)) // never holds, but disabled
+	cs.AssertIsLessOrEqualIf(circuit.Enabled, circuit.X, 161)
+	cs.AssertIsLessOrEqualIf(circuit.Disabled, circuit.X, 2) // doesn't hold, but disabled
+	cs.AssertIsLessOrEqualIf(circuit.Enabled, circuit.Z, circuit.X)
+	return nil
+}
+
+// assertIf registers the assertIfCircuit with a bad witness (x, y, z) failing only one of the enabled assertions
+func assertIf(name string, x, y, z int) {
+	var circuit, good, bad, public assertIfCircuit
+
+	good.X.Assign(10)
+	good.Y.Assign(10)
+	good.Z.Assign(5)
+	good.Enabled.Assign(1)
+	good.Disabled.Assign(0)
+
+	bad.X.Assign(x)
+	bad.Y.Assign(y)
+	bad.Z.Assign(z)
+	bad.Enabled.Assign(1)
+	bad.Disabled.Assign(0)
+
+	public.Enabled.Assign(1)
+	public.Disabled.Assign(0)
+
+	addEntry(name, &circuit, &good, &bad, &public)
+}
+
+type assertDifferentCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *assertDifferentCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsDifferent(circuit.X, circuit.Y)
+	cs.AssertIsDifferent(circuit.X, 0)
+	return nil
+}
+
+// assertDifferent registers the assertDifferentCircuit with a bad witness x failing only one of the assertions
+func assertDifferent(name string, x int) {
+	var circuit, good, bad, public assertDifferentCircuit
+
+	good.X.Assign(3)
+	good.Y.Assign(4)
+
+	bad.X.Assign(x)
+	bad.Y.Assign(4)
+
+	public.Y.Assign(4)
+
+	addEntry(name, &circuit, &good, &bad, &public)
+}
+
+func init() {
+	assertIf("assert_if_equal", 10, 11, 5)
+	assertIf("assert_if_less_or_equal_constant", 200, 200, 5)
+	assertIf("assert_if_less_or_equal", 10, 10, 11)
+
+	assertDifferent("assert_different_variable", 4)
+	assertDifferent("assert_different_constant", 0)
+}
@@ -0,0 +1,89 @@
+package circuits
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+const cmpNbBits = 251
+
+type cmpCircuit struct {
+	A, B                             frontend.Variable
+	LessOrEqual, Less, Cmp, Min, Max frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cmpCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(cs.IsLessOrEqual(circuit.A, circuit.B, cmpNbBits), circuit.LessOrEqual)
+	cs.AssertIsEqual(cs.IsLess(circuit.A, circuit.B, cmpNbBits), circuit.Less)
+	cs.AssertIsEqual(cs.Cmp(circuit.A, circuit.B, cmpNbBits), circuit.Cmp)
+	cs.AssertIsEqual(cs.Min(circuit.A, circuit.B, cmpNbBits), circuit.Min)
+	cs.AssertIsEqual(cs.Max(circuit.A, circuit.B, cmpNbBits), circuit.Max)
+	return nil
+}
+
+// assign sets the operands to a and b, and the results to the comparisons of a and b
+func (circuit *cmpCircuit) assign(a, b *big.Int) {
+	circuit.A.Assign(a)
+	circuit.B.Assign(b)
+	cmp := a.Cmp(b)
+	circuit.LessOrEqual.Assign(0)
+	circuit.Less.Assign(0)
+	if cmp <= 0 {
+		circuit.LessOrEqual.Assign(1)
+	}
+	if cmp < 0 {
+		circuit.Less.Assign(1)
+	}
+	circuit.Cmp.Assign(cmp)
+	if cmp <= 0 {
+		circuit.Min.Assign(a)
+		circuit.Max.Assign(b)
+	} else {
+		circuit.Min.Assign(b)
+		circuit.Max.Assign(a)
+	}
+}
+
+// cmp registers the cmpCircuit with the good witness (a, b); the bad witness (badA, badB) keeps
+// the results of (a, b), which don't hold for it
+func cmp(name string, a, b, badA, badB *big.Int) {
+	var circuit, good, bad, public cmpCircuit
+
+	good.assign(a, b)
+	public.assign(a, b)
+
+	bad.assign(a, b)
+	bad.A.Assign(badA)
+	bad.B.Assign(badB)
+
+	addEntry(name, &circuit, &good, &bad, &public)
+}
+
+func init() {
+	// boundary values of the operands
+	max := new(big.Int).Lsh(big.NewInt(1), cmpNbBits)
+	max.Sub(max, big.NewInt(1))
+	maxMinusOne := new(big.Int).Sub(max, big.NewInt(1))
+	zero, one := big.NewInt(0), big.NewInt(1)
+
+	cmp("cmp_less", zero, max, max, zero)
+	cmp("cmp_greater", max, maxMinusOne, maxMinusOne, max)
+	cmp("cmp_equal", one, one, zero, one)
+	cmp("cmp_equal_max", max, max, max, maxMinusOne)
+
+	cmpOutOfRange(max)
+}
+
+// cmpOutOfRange registers the cmpCircuit with a bad witness whose results hold, but whose
+// operand doesn't fit in cmpNbBits bits
+func cmpOutOfRange(max *big.Int) {
+	var circuit, good, bad, public cmpCircuit
+
+	good.assign(max, big.NewInt(0))
+	public.assign(max, big.NewInt(0))
+	bad.assign(new(big.Int).Add(max, big.NewInt(1)), big.NewInt(0))
+
+	addEntry("cmp_out_of_range", &circuit, &good, &bad, &public)
+}
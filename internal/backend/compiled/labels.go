@@ -0,0 +1,85 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiled
+
+import (
+	"strings"
+)
+
+// WireLabels returns a label for each wire, for debugging purposes: the name of the inputs,
+// and the call site of the constraint computing the internal wires (see DebugInfo)
+func (r1cs *R1CS) WireLabels() []string {
+	labels := make([]string, r1cs.NbInternalVariables+r1cs.NbPublicVariables+r1cs.NbSecretVariables)
+	copy(labels, r1cs.PublicInputs)
+	copy(labels[r1cs.NbPublicVariables:], r1cs.SecretInputs)
+
+	r1cs.visitSolver(func(cID int, inputs, outputs []int) {
+		if cID >= len(r1cs.ConstraintsDebugInfo) {
+			return
+		}
+		label := callSite(r1cs.DebugInfo[r1cs.ConstraintsDebugInfo[cID]].Format)
+		for _, wID := range outputs {
+			labels[wID] = label
+		}
+	})
+
+	return labels
+}
+
+// WireLabels returns a label for each wire, for debugging purposes: the name of the inputs,
+// and the call site of the constraint computing the internal wires (see DebugInfo)
+//
+// The internal wires added by the conversion to plonk constraints are labeled with the call site
+// of the constraint they were added for.
+func (cs *SparseR1CS) WireLabels() []string {
+	labels := make([]string, cs.NbInternalVariables+cs.NbPublicVariables+cs.NbSecretVariables)
+	copy(labels, cs.PublicInputs)
+	copy(labels[cs.NbPublicVariables:], cs.SecretInputs)
+
+	cs.visitSolver(func(cID int, inputs, outputs []int) {
+		if cID >= len(cs.ConstraintsDebugInfo) {
+			return
+		}
+		label := callSite(cs.DebugInfo[cs.ConstraintsDebugInfo[cID]].Format)
+		for _, wID := range outputs {
+			labels[wID] = label
+		}
+	})
+
+	return labels
+}
+
+// callSite returns the first frame of the call stack in the debug info format that is
+// not in the gnark frontend, on one line: "function file:line"
+//
+// the frames of the call stack are formatted as "function\n\tfile:line" (see frontend.getCallStack);
+// if there is none, format is returned unchanged.
+func callSite(format string) string {
+	lines := strings.Split(strings.ReplaceAll(format, "%%", "%"), "\n")
+	site := ""
+	for i := 0; i+1 < len(lines); i++ {
+		if !strings.HasPrefix(lines[i+1], "\t") || strings.HasPrefix(lines[i], "\t") {
+			continue
+		}
+		site = lines[i] + " " + strings.TrimPrefix(lines[i+1], "\t")
+		if !strings.HasPrefix(lines[i], "frontend.") {
+			return site
+		}
+	}
+	if site == "" {
+		return format
+	}
+	return site
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiled
+
+// levelBuilder groups constraints in levels, such that a constraint only depends
+// on wires computed by constraints of the previous levels.
+//
+// The constraints must be added in the order of the sequential solver, which is simulated
+// to find the wires computed by each constraint.
+type levelBuilder struct {
+	solved    []bool // wires that are known when the next constraint is solved
+	wireLevel []int  // level of the constraint computing the wire, -1 for the inputs
+	levels    [][]int
+}
+
+func newLevelBuilder(nbWires, nbInputs int) *levelBuilder {
+	b := &levelBuilder{
+		solved:    make([]bool, nbWires),
+		wireLevel: make([]int, nbWires),
+	}
+	for i := 0; i < nbInputs; i++ {
+		b.solved[i] = true
+		b.wireLevel[i] = -1
+	}
+	return b
+}
+
+// add adds the constraint cID, which reads the wires in inputs and computes the wires in outputs
+//
+// inputs may contain the outputs and unsolved wires, which are ignored
+func (b *levelBuilder) add(cID int, inputs, outputs []int) {
+	level := 0
+	for _, wID := range inputs {
+		if b.solved[wID] && b.wireLevel[wID] >= level {
+			level = b.wireLevel[wID] + 1
+		}
+	}
+	for _, wID := range outputs {
+		b.solved[wID] = true
+		b.wireLevel[wID] = level
+	}
+	if level == len(b.levels) {
+		b.levels = append(b.levels, nil)
+	}
+	b.levels[level] = append(b.levels[level], cID)
+}
+
+// visitSolver simulates the solver on the computational constraints: visit is called on each of them,
+// in order, with the wires it reads and the wires it computes (inputs may contain the outputs)
+//
+// The wire IDs of the constraints must be final, that is [ONE_WIRE | public | secret | internal].
+func (r1cs *R1CS) visitSolver(visit func(cID int, inputs, outputs []int)) {
+	nbWires := r1cs.NbInternalVariables + r1cs.NbPublicVariables + r1cs.NbSecretVariables
+	solved := make([]bool, nbWires)
+	for i := 0; i < r1cs.NbPublicVariables+r1cs.NbSecretVariables; i++ {
+		solved[i] = true
+	}
+
+	var inputs, outputs []int
+	for i := 0; i < r1cs.NbCOConstraints; i++ {
+		r := &r1cs.Constraints[i]
+		inputs, outputs = inputs[:0], outputs[:0]
+		for _, l := range []LinearExpression{r.L, r.R, r.O} {
+			for _, t := range l {
+				inputs = append(inputs, t.VariableID())
+			}
+		}
+
+		switch r.Solver {
+		case SingleOutput:
+			// the wire computed is the one that is not solved yet, if any
+			for _, wID := range inputs {
+				if !solved[wID] {
+					outputs = append(outputs, wID)
+					break
+				}
+			}
+		case BinaryDec:
+			// the bits are in L
+			for _, t := range r.L {
+				outputs = append(outputs, t.VariableID())
+			}
+		}
+		visit(i, inputs, outputs)
+		for _, wID := range outputs {
+			solved[wID] = true
+		}
+	}
+}
+
+// BuildLevels sets r1cs.Levels from the computational constraints
+//
+// The wire IDs of the constraints must be final, that is [ONE_WIRE | public | secret | internal].
+func (r1cs *R1CS) BuildLevels() {
+	nbWires := r1cs.NbInternalVariables + r1cs.NbPublicVariables + r1cs.NbSecretVariables
+	b := newLevelBuilder(nbWires, r1cs.NbPublicVariables+r1cs.NbSecretVariables)
+	r1cs.visitSolver(b.add)
+	r1cs.Levels = b.levels
+}
+
+// visitSolver simulates the solver on the constraints: visit is called on each of them,
+// in order, with the wires it reads and the wires it computes (inputs may contain the outputs)
+//
+// The wire IDs of the constraints must be final, that is [public | secret | internal].
+func (cs *SparseR1CS) visitSolver(visit func(cID int, inputs, outputs []int)) {
+	nbWires := cs.NbInternalVariables + cs.NbPublicVariables + cs.NbSecretVariables
+	solved := make([]bool, nbWires)
+	for i := 0; i < cs.NbPublicVariables+cs.NbSecretVariables; i++ {
+		solved[i] = true
+	}
+
+	for i := 0; i < len(cs.Constraints); i++ {
+		c := &cs.Constraints[i]
+		inputs := []int{c.L.VariableID(), c.R.VariableID(), c.O.VariableID(), c.M[0].VariableID(), c.M[1].VariableID()}
+
+		var outputs []int
+		switch c.Solver {
+		case SingleOutput:
+			// see the solver: L, then R, then O
+			switch {
+			case c.L.CoeffID() != 0 && !solved[c.L.VariableID()],
+				c.M[0].CoeffID() != 0 && !solved[c.M[0].VariableID()]:
+				outputs = []int{c.L.VariableID()}
+			case c.R.CoeffID() != 0 && !solved[c.R.VariableID()],
+				c.M[1].CoeffID() != 0 && !solved[c.M[1].VariableID()]:
+				outputs = []int{c.R.VariableID()}
+			default:
+				outputs = []int{c.O.VariableID()}
+			}
+		case BinaryDec:
+			outputs = []int{c.L.VariableID(), c.R.VariableID()}
+		}
+		visit(i, inputs, outputs)
+		for _, wID := range outputs {
+			solved[wID] = true
+		}
+	}
+}
+
+// BuildLevels sets cs.Levels from the constraints
+//
+// The wire IDs of the constraints must be final, that is [public | secret | internal].
+func (cs *SparseR1CS) BuildLevels() {
+	nbWires := cs.NbInternalVariables + cs.NbPublicVariables + cs.NbSecretVariables
+	b := newLevelBuilder(nbWires, cs.NbPublicVariables+cs.NbSecretVariables)
+	cs.visitSolver(b.add)
+	cs.Levels = b.levels
+}
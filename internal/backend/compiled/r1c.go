@@ -56,8 +56,10 @@ type R1C struct {
 // to represent string values (in logs or debug info) where a value is not known at compile time
 // (which is the case for variables that need to be resolved in the R1CS)
 type LogEntry struct {
-	Format    string
-	ToResolve []int
+	Format      string
+	ToResolve   []int
+	Caller      string // location of the log in the circuit (file.go:line), empty for debug info
+	Conditional bool   // if set, ToResolve[0] is a condition: the log is printed only if it is solved and not zero
 }
 
 // Visibility encodes a Variable (or wire) visibility
@@ -18,6 +18,7 @@ import (
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
 )
 
 // R1CS decsribes a set of R1CS constraint
@@ -28,6 +29,8 @@ type R1CS struct {
 	NbInternalVariables int
 	NbPublicVariables   int // includes ONE wire
 	NbSecretVariables   int
+	PublicInputs        []string // names of the public inputs, including the ONE wire
+	SecretInputs        []string // names of the secret inputs
 	Logs                []LogEntry
 	DebugInfo           []LogEntry
 
@@ -35,6 +38,13 @@ type R1CS struct {
 	NbConstraints   int // total number of constraints
 	NbCOConstraints int // number of constraints that need to be solved, the first of the Constraints slice
 	Constraints     []R1C
+
+	// ConstraintsDebugInfo[i] is the index in DebugInfo of the debug info of Constraints[i]
+	ConstraintsDebugInfo []int
+
+	// Levels groups the indexes of the computational constraints, such that the constraints of a level
+	// only depend on wires computed at the previous levels: they can be solved concurrently (see BuildLevels)
+	Levels [][]int
 }
 
 // GetNbConstraints returns the number of constraints
@@ -65,6 +75,11 @@ func (r1cs *R1CS) FrSize() int {
 	panic("not implemented")
 }
 
+// SolveWires panics (can't solve untyped constraint system)
+func (r1cs *R1CS) SolveWires(fullWitness io.Reader, opts ...backend.ProverOption) (backend.Wires, error) {
+	panic("not implemented")
+}
+
 // WriteTo panics (can't serialize untyped R1CS)
 func (r1cs *R1CS) WriteTo(w io.Writer) (n int64, err error) {
 	panic("not implemented")
@@ -74,10 +89,3 @@ func (r1cs *R1CS) WriteTo(w io.Writer) (n int64, err error) {
 func (r1cs *R1CS) ReadFrom(r io.Reader) (n int64, err error) {
 	panic("not implemented")
 }
-
-// SetLoggerOutput replace existing logger output with provided one
-// default uses os.Stdout
-// if nil is provided, logs are not printed
-func (r1cs *R1CS) SetLoggerOutput(w io.Writer) {
-	panic("not implemented")
-}
@@ -18,6 +18,7 @@ import (
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
 )
 
 // SparseR1CS represents a Plonk like circuit
@@ -27,13 +28,27 @@ type SparseR1CS struct {
 	NbInternalVariables int
 	NbPublicVariables   int
 	NbSecretVariables   int
+	PublicInputs        []string // names of the public inputs
+	SecretInputs        []string // names of the secret inputs
 
 	// Constraints
 	Constraints []SparseR1C // list of PLONK constraints that yield an output (for example v3 == v1 * v2, return v3)
 	Assertions  []SparseR1C // list of PLONK constraints that yield no output (for example ensuring v1 == v2)
 
+	// Levels groups the indexes of the Constraints, such that the constraints of a level only depend
+	// on wires computed at the previous levels: they can be solved concurrently (see BuildLevels)
+	Levels [][]int
+
 	// Logs (e.g. variables that have been printed using cs.Println)
 	Logs []LogEntry
+
+	// DebugInfo of the constraints of the circuit, before their conversion to plonk constraints;
+	// constraints recorded at the same call site share the same debug info
+	DebugInfo []LogEntry
+
+	// indexes in DebugInfo of the debug info of each constraint (resp. assertion)
+	ConstraintsDebugInfo []int
+	AssertionsDebugInfo  []int
 }
 
 // GetNbVariables return number of internal, secret and public variables
@@ -59,6 +74,11 @@ func (cs *SparseR1CS) FrSize() int {
 	panic("not implemented")
 }
 
+// SolveWires panics (can't solve untyped constraint system)
+func (cs *SparseR1CS) SolveWires(fullWitness io.Reader, opts ...backend.ProverOption) (backend.Wires, error) {
+	panic("not implemented")
+}
+
 // GetNbCoefficients return the number of unique coefficients needed in the R1CS
 func (cs *SparseR1CS) GetNbCoefficients() int {
 	panic("not implemented")
@@ -78,10 +98,3 @@ func (cs *SparseR1CS) WriteTo(w io.Writer) (n int64, err error) {
 func (cs *SparseR1CS) ReadFrom(r io.Reader) (n int64, err error) {
 	panic("not implemented")
 }
-
-// SetLoggerOutput replace existing logger output with provided one
-// default uses os.Stdout
-// if nil is provided, logs are not printed
-func (cs *SparseR1CS) SetLoggerOutput(w io.Writer) {
-	panic("not implemented")
-}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -44,10 +45,55 @@ func appendName(baseName, name string) string {
 	return baseName + "_" + name
 }
 
+// FieldInfo resolves the name and visibility of a struct field from its gnark tag (see Tag).
+//
+// omit is set if the field is tagged with "-". If the field is embedded, the returned name is empty
+// and the visibility is compiled.Unset. A parentVisibility other than compiled.Unset overrides the
+// visibility of the field.
+func FieldInfo(field reflect.StructField, parentVisibility compiled.Visibility) (name string, visibility compiled.Visibility, omit bool, err error) {
+	// get gnark tag
+	tag := field.Tag.Get(string(tagKey))
+	if tag == string(optOmit) {
+		return "", compiled.Unset, true, nil
+	}
+
+	visibility = compiled.Secret
+	name = field.Name
+
+	if tag != "" {
+		// gnark tag is set
+		var opts tagOptions
+		name, opts = parseTag(tag)
+		if !isValidTag(name) {
+			name = field.Name
+		}
+		opts = tagOptions(strings.TrimSpace(string(opts)))
+		if opts == "" || opts.Contains(string(optSecret)) {
+			visibility = compiled.Secret
+		} else if opts.Contains(string(optPublic)) {
+			visibility = compiled.Public
+		} else if opts.Contains(string(optEmbed)) {
+			name = ""
+			visibility = compiled.Unset
+		} else {
+			return "", compiled.Unset, false, errors.New("invalid gnark struct tag option. must be \"public\", \"secret\",\"embed\" or \"-\"")
+		}
+	}
+	if parentVisibility != compiled.Unset {
+		visibility = parentVisibility // parent visibility overhides
+	}
+	return
+}
+
 // LeafHandler is the handler function that will be called when Visit reaches leafs of the struct
 type LeafHandler func(visibility compiled.Visibility, name string, tValue reflect.Value) error
 
 // Visit using reflect, browse through exposed addressable fields from input, and calls handler() if leaf.type == target
+//
+// Unexported fields and empty slices are skipped, so that gadgets (for example mimc.MiMC) can keep their internal
+// state in a circuit. Pointers are followed; a nil pointer to a type that may contain target returns an error.
+// Maps must be keyed by strings, and their entries are visited in increasing key order. Since map values are
+// not addressable, each value is copied, visited, and then stored back in the map.
 func Visit(input interface{}, baseName string, parentVisibility compiled.Visibility, handler LeafHandler, target reflect.Type) error {
 
 	// types we are lOoutputoking for
@@ -55,11 +101,17 @@ func Visit(input interface{}, baseName string, parentVisibility compiled.Visibil
 	// tConstraintSytem := reflect.TypeOf(frontend.ConstraintSystem{})
 
 	tValue := reflect.ValueOf(input)
-	if tValue.Kind() == reflect.Ptr {
+	for tValue.Kind() == reflect.Ptr {
+		if tValue.IsNil() {
+			if containsType(tValue.Type().Elem(), target, make(map[reflect.Type]bool)) {
+				return fmt.Errorf("%s: nil pointer", displayName(baseName))
+			}
+			return nil
+		}
 		tValue = tValue.Elem()
 	}
 
-	// we either have a pointer, a struct, or a slice / array
+	// we either have a pointer, a struct, a map or a slice / array
 	// and recursively parse members / elements until we find a constraint to allOoutputcate in the circuit.
 	switch tValue.Kind() {
 	case reflect.Struct:
@@ -70,36 +122,12 @@ func Visit(input interface{}, baseName string, parentVisibility compiled.Visibil
 			for i := 0; i < tValue.NumField(); i++ {
 				field := tValue.Type().Field((i))
 
-				// get gnark tag
-				tag := field.Tag.Get(string(tagKey))
-				if tag == string(optOmit) {
-					continue // skipping "-"
-				}
-
-				visibility := compiled.Secret
-				name := field.Name
-
-				if tag != "" {
-					// gnark tag is set
-					var opts tagOptions
-					name, opts = parseTag(tag)
-					if !isValidTag(name) {
-						name = field.Name
-					}
-					opts = tagOptions(strings.TrimSpace(string(opts)))
-					if opts == "" || opts.Contains(string(optSecret)) {
-						visibility = compiled.Secret
-					} else if opts.Contains(string(optPublic)) {
-						visibility = compiled.Public
-					} else if opts.Contains(string(optEmbed)) {
-						name = ""
-						visibility = compiled.Unset
-					} else {
-						return errors.New("invalid gnark struct tag option. must be \"public\", \"secret\",\"embed\" or \"-\"")
-					}
+				name, visibility, omit, err := FieldInfo(field, parentVisibility)
+				if err != nil {
+					return err
 				}
-				if parentVisibility != compiled.Unset {
-					visibility = parentVisibility // parent visibility overhides
+				if omit {
+					continue // skipping "-"
 				}
 
 				fullName := appendName(baseName, name)
@@ -110,20 +138,12 @@ func Visit(input interface{}, baseName string, parentVisibility compiled.Visibil
 					if err := Visit(value, fullName, visibility, handler, target); err != nil {
 						return err
 					}
-				} else {
-					if f.Kind() == reflect.Ptr {
-						f = f.Elem()
-					}
-					if (f.Kind() == reflect.Struct) && (f.Type() == target) {
-						fmt.Println("warning: Variable is unexported or unadressable", fullName)
-					}
 				}
 			}
 		}
 
 	case reflect.Slice, reflect.Array:
 		if tValue.Len() == 0 {
-			fmt.Printf("%s: ignoring unitizalized slice (or empty array)\n", baseName)
 			return nil
 		}
 		for j := 0; j < tValue.Len(); j++ {
@@ -133,12 +153,59 @@ func Visit(input interface{}, baseName string, parentVisibility compiled.Visibil
 				if err := Visit(val.Addr().Interface(), appendName(baseName, strconv.Itoa(j)), parentVisibility, handler, target); err != nil {
 					return err
 				}
+			} else if containsType(val.Type(), target, make(map[reflect.Type]bool)) {
+				return fmt.Errorf("%s: array is unadressable", displayName(baseName))
 			}
 
 		}
 	case reflect.Map:
-		fmt.Println("warning: map values are not addressable, ignoring")
+		if tValue.Type().Key().Kind() != reflect.String {
+			if containsType(tValue.Type().Elem(), target, make(map[reflect.Type]bool)) {
+				return fmt.Errorf("%s: only maps keyed by strings are supported", displayName(baseName))
+			}
+			return nil
+		}
+		keys := tValue.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			// map values are not addressable: we visit a copy and store it back in the map
+			val := reflect.New(tValue.Type().Elem())
+			val.Elem().Set(tValue.MapIndex(key))
+			if err := Visit(val.Interface(), appendName(baseName, key.String()), parentVisibility, handler, target); err != nil {
+				return err
+			}
+			tValue.SetMapIndex(key, val.Elem())
+		}
 	}
 
 	return nil
 }
+
+// containsType returns true if a value of type t may contain a value of type target
+func containsType(t, target reflect.Type, visited map[reflect.Type]bool) bool {
+	if t == target {
+		return true
+	}
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return containsType(t.Elem(), target, visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath == "" && containsType(t.Field(i).Type, target, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "circuit"
+	}
+	return name
+}
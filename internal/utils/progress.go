@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/backend"
+)
+
+// Progress reports the completed phases of a proof computation to a backend.ProgressFunc
+//
+// It measures the time elapsed since its creation, and serializes the calls to the ProgressFunc.
+// A nil ProgressFunc is accepted, in which case the reports are ignored.
+type Progress struct {
+	f     backend.ProgressFunc
+	start time.Time
+	lock  sync.Mutex
+}
+
+// NewProgress returns a Progress reporting to f, starting now
+func NewProgress(f backend.ProgressFunc) *Progress {
+	return &Progress{f: f, start: time.Now()}
+}
+
+// Done reports that phase is completed
+func (p *Progress) Done(phase backend.ProverPhase) {
+	if p.f == nil {
+		return
+	}
+	p.lock.Lock()
+	p.f(phase, time.Since(p.start))
+	p.lock.Unlock()
+}
@@ -0,0 +1,212 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// An append-only tree of depth d holds up to 2^d-1 leaves, the next leaf being appended at the index Count.
+// Its frontier is the list of the roots of the complete subtrees on the left of the path of the next leaf:
+// at the level i, the left sibling of the path is in the frontier when the bit i of Count is set.
+// The other nodes of the tree on the right of the path are empty: the root of an empty subtree
+// at the level i is zeros[i], zeros[0] being the empty leaf.
+//
+// With the zero DomainSeparation and MiMC, the nodes are hashed as in VerifyProof.
+
+// Frontier is the state of an append-only tree in a circuit
+type Frontier struct {
+	Count frontend.Variable   // number of leaves in the tree
+	Nodes []frontend.Variable // Nodes[i] is the left sibling at the level i of the path of the next leaf, if the bit i of Count is set
+}
+
+// FrontierRoot returns the root of the append-only tree with frontier f
+//
+// zeros are the roots of the empty subtrees at each level (see IncrementalTree.Zeros), and must have
+// the length of f.Nodes. h is reset before each hash.
+//
+// The root doesn't bind f.Count: appending empty leaves (equal to zeros[0]) leaves the root unchanged,
+// so trees with different counts may have the same root. The caller must constrain f.Count separately,
+// for instance by making it a public input next to the root, or by hashing it with the root.
+func FrontierRoot(cs *frontend.ConstraintSystem, h hash.Hash, f Frontier, zeros []interface{}, ds DomainSeparation) frontend.Variable {
+	if len(zeros) != len(f.Nodes) {
+		panic("frontier: invalid number of empty subtree roots")
+	}
+
+	countBits := cs.ToBinary(f.Count, len(f.Nodes))
+	node := cs.Constant(zeros[0])
+	for i := range f.Nodes {
+		left := cs.Select(countBits[i], f.Nodes[i], node)
+		right := cs.Select(countBits[i], node, zeros[i])
+		node = hashWithPrefix(cs, h, ds.NodePrefix, left, right)
+	}
+
+	return node
+}
+
+// AppendLeaves returns the frontier of the append-only tree with frontier f after appending data
+//
+// the tree must not be full: the number of leaves stays below 2^len(f.Nodes). The leaves are hashed
+// as configured by ds, h is reset before each hash.
+//
+// f.Count is not checked against f.Nodes, and the root of the tree doesn't bind it (see FrontierRoot):
+// the caller must constrain it separately.
+func AppendLeaves(cs *frontend.ConstraintSystem, h hash.Hash, f Frontier, data []frontend.Variable, ds DomainSeparation) Frontier {
+	depth := len(f.Nodes)
+	res := Frontier{
+		Count: f.Count,
+		Nodes: make([]frontend.Variable, depth),
+	}
+	copy(res.Nodes, f.Nodes)
+
+	for _, d := range data {
+		node := d
+		if !ds.RawLeaves {
+			node = hashWithPrefix(cs, h, ds.LeafPrefix, d)
+		}
+
+		// the leaf goes up while its subtree is the right child of a complete subtree,
+		// and is stored in the frontier at the first level where it is a left child
+		countBits := cs.ToBinary(res.Count, depth)
+		carry := cs.Constant(1)
+		for i := 0; i < depth; i++ {
+			nextCarry := cs.Mul(carry, countBits[i])
+			store := cs.Sub(carry, nextCarry) // carry·(1-bit), no constraint is recorded
+			updated := cs.Add(res.Nodes[i], cs.Mul(store, cs.Sub(node, res.Nodes[i])))
+			if i < depth-1 {
+				node = hashWithPrefix(cs, h, ds.NodePrefix, res.Nodes[i], node)
+			}
+			res.Nodes[i] = updated
+			carry = nextCarry
+		}
+		res.Count = cs.Add(res.Count, 1)
+	}
+
+	// the last leaf must not fill the tree
+	cs.ToBinary(res.Count, depth)
+
+	return res
+}
+
+// VerifyAppend asserts that appending data to the append-only tree of root oldRoot and frontier old
+// gives the tree of root newRoot, and returns the new frontier (see FrontierRoot and AppendLeaves)
+//
+// oldRoot doesn't bind old.Count, nor newRoot the returned count (see FrontierRoot): the caller must
+// constrain old.Count separately.
+func VerifyAppend(cs *frontend.ConstraintSystem, h hash.Hash, oldRoot, newRoot frontend.Variable, old Frontier, data []frontend.Variable, zeros []interface{}, ds DomainSeparation) Frontier {
+	cs.AssertIsEqual(FrontierRoot(cs, h, old, zeros, ds), oldRoot)
+	res := AppendLeaves(cs, h, old, data, ds)
+	cs.AssertIsEqual(FrontierRoot(cs, h, res, zeros, ds), newRoot)
+	return res
+}
+
+// ErrTreeFull is returned when appending a leaf to a full append-only tree
+var ErrTreeFull = errors.New("merkle: append-only tree is full")
+
+// IncrementalTree is a native append-only tree storing its frontier only
+//
+// the nodes are hashed as in the circuit (see Frontier), the leaves are the leaf nodes:
+// the hashes of the data, unless the leaves are raw (see DomainSeparation.RawLeaves).
+type IncrementalTree struct {
+	hashNode func(left, right []byte) []byte
+	count    uint64
+	nodes    [][]byte // frontier
+	zeros    [][]byte // roots of the empty subtrees
+}
+
+// NewIncrementalTree returns an empty append-only tree of the given depth
+//
+// emptyLeaf is the empty leaf node, hashNode returns the parent of two nodes.
+func NewIncrementalTree(depth int, emptyLeaf []byte, hashNode func(left, right []byte) []byte) *IncrementalTree {
+	t := &IncrementalTree{
+		hashNode: hashNode,
+		nodes:    make([][]byte, depth),
+		zeros:    make([][]byte, depth),
+	}
+	if depth > 0 {
+		t.zeros[0] = emptyLeaf
+	}
+	for i := 1; i < depth; i++ {
+		t.zeros[i] = hashNode(t.zeros[i-1], t.zeros[i-1])
+	}
+	return t
+}
+
+// Count returns the number of leaves in the tree
+func (t *IncrementalTree) Count() uint64 {
+	return t.count
+}
+
+// Append appends a leaf to the tree
+func (t *IncrementalTree) Append(leaf []byte) error {
+	if t.count+1 >= 1<<uint(len(t.nodes)) {
+		return ErrTreeFull
+	}
+	node := leaf
+	for i := range t.nodes {
+		if (t.count>>uint(i))&1 == 0 {
+			t.nodes[i] = node
+			break
+		}
+		node = t.hashNode(t.nodes[i], node)
+	}
+	t.count++
+	return nil
+}
+
+// Root returns the root of the tree
+//
+// the root doesn't depend on the number of leaves when the last leaves are empty (see FrontierRoot).
+func (t *IncrementalTree) Root() []byte {
+	if len(t.nodes) == 0 {
+		return nil
+	}
+	node := t.zeros[0]
+	for i := range t.nodes {
+		if (t.count>>uint(i))&1 == 1 {
+			node = t.hashNode(t.nodes[i], node)
+		} else {
+			node = t.hashNode(node, t.zeros[i])
+		}
+	}
+	return node
+}
+
+// Frontier returns the nodes of the frontier of the tree (see Frontier.Nodes)
+//
+// the levels whose node is not in the frontier hold the root of an empty subtree.
+func (t *IncrementalTree) Frontier() [][]byte {
+	res := make([][]byte, len(t.nodes))
+	for i := range res {
+		if (t.count>>uint(i))&1 == 1 {
+			res[i] = t.nodes[i]
+		} else {
+			res[i] = t.zeros[i]
+		}
+	}
+	return res
+}
+
+// Zeros returns the roots of the empty subtrees at each level, from the empty leaf
+func (t *IncrementalTree) Zeros() [][]byte {
+	res := make([][]byte, len(t.zeros))
+	copy(res, t.zeros)
+	return res
+}
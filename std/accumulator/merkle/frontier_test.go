@@ -0,0 +1,129 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+const frontierDepth = 3
+
+type appendCircuit struct {
+	OldRoot, NewRoot frontend.Variable `gnark:",public"`
+	Old              Frontier
+	Data             [3]frontend.Variable
+	zeros            []interface{}
+}
+
+func (circuit *appendCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	VerifyAppend(cs, &h, circuit.OldRoot, circuit.NewRoot, circuit.Old, circuit.Data[:], circuit.zeros, DomainSeparation{})
+	return nil
+}
+
+func TestVerifyAppend(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	data := make([][]byte, (1<<frontierDepth)-1)
+	for i := range data {
+		var e fr.Element
+		e.SetUint64(uint64(100 + i))
+		b := e.Bytes()
+		data[i] = b[:]
+	}
+	var emptyLeaf fr.Element
+	b := emptyLeaf.Bytes()
+	hashNode := func(left, right []byte) []byte {
+		return nativeHash(nil, left, right)
+	}
+
+	// the root of the incremental tree is the root of the full tree padded with empty leaves
+	tree := NewIncrementalTree(frontierDepth, b[:], hashNode)
+	for i := range data {
+		if err := tree.Append(nativeHash(nil, data[i])); err != nil {
+			t.Fatal(err)
+		}
+		leaves := make([][]byte, 1<<frontierDepth)
+		for j := range leaves {
+			if j <= i {
+				leaves[j] = nativeHash(nil, data[j])
+			} else {
+				leaves[j] = b[:]
+			}
+		}
+		root, _, err := BuildMultiProof(leaves, []int{0}, hashNode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(root, tree.Root(), "root after appending leaf %d", i)
+	}
+	assert.Equal(ErrTreeFull, tree.Append(b[:]))
+
+	zeros := make([]interface{}, frontierDepth)
+	for i, z := range tree.Zeros() {
+		zeros[i] = z
+	}
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &appendCircuit{Old: Frontier{Nodes: make([]frontend.Variable, frontierDepth)}, zeros: zeros})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// appends data[start:start+3] to the tree holding data[:start]
+	newWitness := func(start int) *appendCircuit {
+		tree := NewIncrementalTree(frontierDepth, b[:], hashNode)
+		for i := 0; i < start; i++ {
+			_ = tree.Append(nativeHash(nil, data[i]))
+		}
+		witness := appendCircuit{Old: Frontier{Nodes: make([]frontend.Variable, frontierDepth)}}
+		witness.OldRoot.Assign(tree.Root())
+		witness.Old.Count.Assign(start)
+		for i, node := range tree.Frontier() {
+			witness.Old.Nodes[i].Assign(node)
+		}
+		for i := range witness.Data {
+			witness.Data[i].Assign(data[start+i])
+			_ = tree.Append(nativeHash(nil, data[start+i]))
+		}
+		witness.NewRoot.Assign(tree.Root())
+		return &witness
+	}
+
+	for start := 0; start <= len(data)-3; start++ {
+		assert.SolvingSucceeded(r1cs, newWitness(start))
+	}
+
+	{
+		witness := newWitness(2)
+		witness.Data[1].Assign(data[0])
+		assert.SolvingFailed(r1cs, witness)
+	}
+	{
+		witness := newWitness(2)
+		witness.Old.Count.Assign(3)
+		assert.SolvingFailed(r1cs, witness)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"errors"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// A multiproof proves that several leaves are in a tree, with the nodes required to compute the root
+// from all the leaves at once: the nodes shared by the paths of the leaves are hashed once.
+//
+// The nodes of the multiproof are ordered by level, from the leaves to the root, and by index in a level:
+// starting from the leaves, the parents of the known nodes of a level are computed from left to right, each
+// parent taking its missing child from the multiproof.
+
+// maxMultiProofDepth is the maximum depth of a tree with a multiproof, so that the indices of
+// its leaves fit in an int
+const maxMultiProofDepth = bits.UintSize - 2
+
+// multiProofWalk walks the tree of the given depth from the leaves at indices to the root; at each level,
+// from left to right, it calls parent with the index of each parent of the known nodes, and whether its
+// left and right children are known (a child which is not known is taken from the multiproof)
+func multiProofWalk(indices []int, depth int, parent func(level, index int, left, right bool)) error {
+	if len(indices) == 0 {
+		return errors.New("multiproof: no leaves")
+	}
+	if depth < 0 || depth > maxMultiProofDepth {
+		return errors.New("multiproof: depth out of range")
+	}
+	for i := range indices {
+		if indices[i] < 0 || indices[i] >= 1<<uint(depth) {
+			return errors.New("multiproof: leaf index out of range")
+		}
+		if i > 0 && indices[i] <= indices[i-1] {
+			return errors.New("multiproof: leaf indices must be sorted and distinct")
+		}
+	}
+
+	current := indices
+	for level := 0; level < depth; level++ {
+		var next []int
+		for i := 0; i < len(current); i++ {
+			switch {
+			case current[i]%2 == 0 && i+1 < len(current) && current[i+1] == current[i]+1:
+				parent(level, current[i]/2, true, true)
+				i++
+			case current[i]%2 == 0:
+				parent(level, current[i]/2, true, false)
+			default:
+				parent(level, current[i]/2, false, true)
+			}
+			next = append(next, current[i]/2)
+		}
+		current = next
+	}
+	return nil
+}
+
+// MultiProofSize returns the number of nodes of the multiproof of the leaves at indices
+// in a tree of the given depth
+func MultiProofSize(indices []int, depth int) (int, error) {
+	size := 0
+	err := multiProofWalk(indices, depth, func(level, index int, left, right bool) {
+		if !left || !right {
+			size++
+		}
+	})
+	return size, err
+}
+
+// BuildMultiProof returns the root of the tree with the given leaves, and the multiproof of the leaves at indices
+//
+// the number of leaves must be a power of 2; hashNode returns the parent of two nodes, as hashed by
+// the circuit (see DomainSeparation). The leaves are the leaf nodes: the hashes of the data, unless
+// the leaves are raw (see DomainSeparation.RawLeaves).
+func BuildMultiProof(leaves [][]byte, indices []int, hashNode func(left, right []byte) []byte) (root []byte, proof [][]byte, err error) {
+	if len(leaves) == 0 || len(leaves)&(len(leaves)-1) != 0 {
+		return nil, nil, errors.New("multiproof: the number of leaves must be a power of 2")
+	}
+	depth := bits.Len(uint(len(leaves))) - 1
+
+	// levels of the tree, from the leaves to the root
+	levels := [][][]byte{leaves}
+	for level := 0; level < depth; level++ {
+		nodes := make([][]byte, len(levels[level])/2)
+		for i := range nodes {
+			nodes[i] = hashNode(levels[level][2*i], levels[level][2*i+1])
+		}
+		levels = append(levels, nodes)
+	}
+
+	err = multiProofWalk(indices, depth, func(level, index int, left, right bool) {
+		if !left {
+			proof = append(proof, levels[level][2*index])
+		} else if !right {
+			proof = append(proof, levels[level][2*index+1])
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return levels[depth][0], proof, nil
+}
+
+// VerifyMultiProof asserts that data are the leaves at indices in the tree of root, of the given depth
+//
+// indices must be sorted and distinct; they define the structure of the circuit, and are known at compile time.
+// proof contains the nodes of the multiproof of the leaves (see BuildMultiProof and MultiProofSize).
+// The leaves and the nodes are hashed as configured by ds, h is reset before each hash.
+func VerifyMultiProof(cs *frontend.ConstraintSystem, h hash.Hash, root frontend.Variable, data []frontend.Variable, indices []int, depth int, proof []frontend.Variable, ds DomainSeparation) {
+	if len(data) != len(indices) {
+		panic("multiproof: the number of leaves and indices differ")
+	}
+	size, err := MultiProofSize(indices, depth)
+	if err != nil {
+		panic(err)
+	}
+	if size != len(proof) {
+		panic("multiproof: invalid number of proof nodes")
+	}
+
+	// known nodes of the current level, by index
+	current := make(map[int]frontend.Variable, len(data))
+	for i := range data {
+		if ds.RawLeaves {
+			current[indices[i]] = data[i]
+		} else {
+			current[indices[i]] = hashWithPrefix(cs, h, ds.LeafPrefix, data[i])
+		}
+	}
+	next := make(map[int]frontend.Variable)
+	if depth == 0 {
+		next = current
+	}
+
+	currentLevel := 0
+	_ = multiProofWalk(indices, depth, func(level, index int, left, right bool) {
+		if level != currentLevel {
+			current, next = next, make(map[int]frontend.Variable)
+			currentLevel = level
+		}
+		l, r := current[2*index], current[2*index+1]
+		if !left {
+			l, proof = proof[0], proof[1:]
+		} else if !right {
+			r, proof = proof[0], proof[1:]
+		}
+		next[index] = hashWithPrefix(cs, h, ds.NodePrefix, l, r)
+	})
+
+	cs.AssertIsEqual(next[0], root)
+}
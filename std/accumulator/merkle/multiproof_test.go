@@ -0,0 +1,142 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+var multiProofIndices = []int{1, 2, 3, 6}
+
+type multiProofCircuit struct {
+	Root  frontend.Variable `gnark:",public"`
+	Data  []frontend.Variable
+	Proof []frontend.Variable
+}
+
+func (circuit *multiProofCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	VerifyMultiProof(cs, &h, circuit.Root, circuit.Data, multiProofIndices, pathDepth, circuit.Proof, DomainSeparation{})
+	return nil
+}
+
+// pathsCircuit verifies the leaves at multiProofIndices with one path each
+type pathsCircuit struct {
+	Root  frontend.Variable `gnark:",public"`
+	Data  [4]frontend.Variable
+	Paths [4][pathDepth]frontend.Variable
+}
+
+func (circuit *pathsCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	for i, index := range multiProofIndices {
+		VerifyPath(cs, &h, circuit.Root, circuit.Data[i], cs.Constant(index), circuit.Paths[i][:], DomainSeparation{})
+	}
+	return nil
+}
+
+func newMultiProofCircuit() *multiProofCircuit {
+	size, err := MultiProofSize(multiProofIndices, pathDepth)
+	if err != nil {
+		panic(err)
+	}
+	return &multiProofCircuit{
+		Data:  make([]frontend.Variable, len(multiProofIndices)),
+		Proof: make([]frontend.Variable, size),
+	}
+}
+
+func TestVerifyMultiProof(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	data := make([][]byte, 1<<pathDepth)
+	for i := range data {
+		var e fr.Element
+		e.SetUint64(uint64(100 + i))
+		b := e.Bytes()
+		data[i] = b[:]
+	}
+	levels := nativeTree(data, nil, nil, false)
+
+	root, proof, err := BuildMultiProof(levels[0], multiProofIndices, func(left, right []byte) []byte {
+		return nativeHash(nil, left, right)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(levels[pathDepth][0], root, "root of BuildMultiProof")
+	// the nodes 0, 7 (leaves) and 2 (level 1)
+	assert.Equal(3, len(proof), "size of the multiproof")
+
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, newMultiProofCircuit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := frontend.Compile(ecc.BN254, backend.GROTH16, &pathsCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(r1cs.GetNbConstraints() < paths.GetNbConstraints(), "the multiproof should share the nodes of the paths")
+
+	newWitness := func() *multiProofCircuit {
+		witness := newMultiProofCircuit()
+		witness.Root.Assign(root)
+		for i, index := range multiProofIndices {
+			witness.Data[i].Assign(data[index])
+		}
+		for i := range proof {
+			witness.Proof[i].Assign(proof[i])
+		}
+		return witness
+	}
+
+	assert.SolvingSucceeded(r1cs, newWitness())
+
+	{
+		witness := newWitness()
+		witness.Data[0].Assign(data[0])
+		assert.SolvingFailed(r1cs, witness)
+	}
+	{
+		witness := newWitness()
+		witness.Proof[1].Assign(data[0])
+		assert.SolvingFailed(r1cs, witness)
+	}
+
+	if _, _, err := BuildMultiProof(levels[0], []int{2, 1}, nil); err == nil {
+		t.Fatal("unsorted indices should be rejected")
+	}
+	if _, err := MultiProofSize([]int{0}, 64); err == nil {
+		t.Fatal("a depth overflowing the indices should be rejected")
+	}
+	if _, err := MultiProofSize([]int{0}, -1); err == nil {
+		t.Fatal("a negative depth should be rejected")
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// DomainSeparation configures how the leaves and the nodes of a tree are hashed (see ComputeRoot)
+//
+// With the zero value, a leaf is H(data) and a node H(left, right). Trees hashing the nodes
+// as H(left, right) without hashing the leaves, such as the circomlib or Semaphore trees,
+// are verified with RawLeaves set.
+type DomainSeparation struct {
+	LeafPrefix interface{} // if not nil, the leaves are hashed as H(LeafPrefix, data)
+	NodePrefix interface{} // if not nil, the nodes are hashed as H(NodePrefix, left, right)
+	RawLeaves  bool        // if set, the leaves are not hashed: the data is the leaf (LeafPrefix is ignored)
+}
+
+// ComputeRoot returns the root of the tree in which data is the leaf at index
+//
+// path contains the siblings of the nodes from the leaf to the root; index is decomposed
+// in len(path) bits, the bit i being set if the node at the level i is a right child.
+// h is reset before each hash.
+func ComputeRoot(cs *frontend.ConstraintSystem, h hash.Hash, data, index frontend.Variable, path []frontend.Variable, ds DomainSeparation) frontend.Variable {
+	node := data
+	if !ds.RawLeaves {
+		node = hashWithPrefix(cs, h, ds.LeafPrefix, data)
+	}
+
+	indexBits := cs.ToBinary(index, len(path))
+	for i := range path {
+		left := cs.Select(indexBits[i], path[i], node)
+		right := cs.Sub(cs.Add(node, path[i]), left) // no constraint is recorded
+		node = hashWithPrefix(cs, h, ds.NodePrefix, left, right)
+	}
+
+	return node
+}
+
+// VerifyPath asserts that data is the leaf at index in the tree of root (see ComputeRoot)
+func VerifyPath(cs *frontend.ConstraintSystem, h hash.Hash, root, data, index frontend.Variable, path []frontend.Variable, ds DomainSeparation) {
+	cs.AssertIsEqual(ComputeRoot(cs, h, data, index, path, ds), root)
+}
+
+// hashWithPrefix returns H(prefix, data...), or H(data...) if prefix is nil
+func hashWithPrefix(cs *frontend.ConstraintSystem, h hash.Hash, prefix interface{}, data ...frontend.Variable) frontend.Variable {
+	h.Reset()
+	if prefix != nil {
+		h.Write(cs.Constant(prefix))
+	}
+	h.Write(data...)
+	return h.Sum()
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+const pathDepth = 3
+
+type pathCircuit struct {
+	Root        frontend.Variable `gnark:",public"`
+	Data, Index frontend.Variable
+	Path        [pathDepth]frontend.Variable
+	ds          DomainSeparation
+}
+
+func (circuit *pathCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	VerifyPath(cs, &h, circuit.Root, circuit.Data, circuit.Index, circuit.Path[:], circuit.ds)
+	return nil
+}
+
+// nativeHash returns the MiMC hash (BN254) of the elements, preceded by prefix if not nil
+func nativeHash(prefix *uint64, elements ...[]byte) []byte {
+	h := hash.MIMC_BN254.New("seed")
+	if prefix != nil {
+		var p fr.Element
+		p.SetUint64(*prefix)
+		b := p.Bytes()
+		h.Write(b[:])
+	}
+	for _, e := range elements {
+		h.Write(e)
+	}
+	return h.Sum(nil)
+}
+
+// nativeTree returns the levels of the tree built with the leaves data, from the leaves to the root
+func nativeTree(data [][]byte, leafPrefix, nodePrefix *uint64, rawLeaves bool) [][][]byte {
+	leaves := make([][]byte, len(data))
+	for i := range data {
+		if rawLeaves {
+			leaves[i] = data[i]
+		} else {
+			leaves[i] = nativeHash(leafPrefix, data[i])
+		}
+	}
+	levels := [][][]byte{leaves}
+	for len(levels[len(levels)-1]) > 1 {
+		level := levels[len(levels)-1]
+		next := make([][]byte, len(level)/2)
+		for i := range next {
+			next[i] = nativeHash(nodePrefix, level[2*i], level[2*i+1])
+		}
+		levels = append(levels, next)
+	}
+	return levels
+}
+
+func TestVerifyPath(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	data := make([][]byte, 1<<pathDepth)
+	for i := range data {
+		var e fr.Element
+		e.SetUint64(uint64(100 + i))
+		b := e.Bytes()
+		data[i] = b[:]
+	}
+
+	zero, one := uint64(0), uint64(1)
+	configs := []struct {
+		ds                     DomainSeparation
+		leafPrefix, nodePrefix *uint64
+	}{
+		{DomainSeparation{}, nil, nil},
+		{DomainSeparation{RawLeaves: true}, nil, nil},
+		{DomainSeparation{LeafPrefix: 0, NodePrefix: 1}, &zero, &one},
+	}
+
+	for _, config := range configs {
+		r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &pathCircuit{ds: config.ds})
+		if err != nil {
+			t.Fatal(err)
+		}
+		levels := nativeTree(data, config.leafPrefix, config.nodePrefix, config.ds.RawLeaves)
+		root := levels[pathDepth][0]
+
+		newWitness := func(leaf, index int) *pathCircuit {
+			var witness pathCircuit
+			witness.Root.Assign(root)
+			witness.Data.Assign(data[leaf])
+			witness.Index.Assign(index)
+			for i := range witness.Path {
+				witness.Path[i].Assign(levels[i][(leaf>>uint(i))^1])
+			}
+			return &witness
+		}
+
+		for i := range data {
+			assert.SolvingSucceeded(r1cs, newWitness(i, i))
+		}
+		assert.SolvingFailed(r1cs, newWitness(2, 3))
+		assert.SolvingFailed(r1cs, newWitness(5, 5+(1<<pathDepth)))
+
+		witness := newWitness(1, 1)
+		witness.Root = frontend.Variable{}
+		witness.Root.Assign(levels[pathDepth-1][0])
+		assert.SolvingFailed(r1cs, witness)
+	}
+}
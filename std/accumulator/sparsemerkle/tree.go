@@ -0,0 +1,231 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparsemerkle
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"math/big"
+)
+
+var (
+	// ErrKeyOutOfRange is returned when a key is not in [0, 2^depth) or not smaller than the modulus
+	ErrKeyOutOfRange = errors.New("key out of range")
+	// ErrValueOutOfRange is returned when a value is not in [0, modulus)
+	ErrValueOutOfRange = errors.New("value out of range")
+)
+
+// Tree is a native sparse Merkle tree, producing the proofs verified by the circuit functions of this package
+//
+// h must be the native MiMC hash of the curve of the circuit, with the same seed (for instance
+// hash.MIMC_BN254.New("seed") from gnark-crypto); the field elements are hashed in big endian,
+// on h.BlockSize() bytes.
+type Tree struct {
+	h       hash.Hash
+	modulus big.Int // modulus of the field of h: the keys and values must be smaller
+	depth   int
+	values  map[string]*big.Int // values stored in the tree, by key
+	nodes   []map[string][]byte // non-empty nodes by level (0 for the leaves) and index
+	empty   [][]byte            // empty[level] is the root of an empty subtree of the level
+}
+
+// NewTree returns an empty tree of the given depth, storing values at keys in [0, 2^depth)
+//
+// modulus is the modulus of the field of h, the scalar field of the curve (for instance fr.Modulus()
+// from gnark-crypto/ecc/bn254/fr); it must fit in h.BlockSize() bytes. depth must be smaller than the bit
+// length of modulus, so that the keys have a unique decomposition in the circuit.
+func NewTree(h hash.Hash, modulus *big.Int, depth int) (*Tree, error) {
+	if modulus.Sign() <= 0 || modulus.BitLen() > 8*h.BlockSize() {
+		return nil, errors.New("the modulus doesn't fit in a block of the hash function")
+	}
+	if depth < 0 || depth >= modulus.BitLen() {
+		return nil, errors.New("the depth must be in [0, bit length of the modulus)")
+	}
+	t := &Tree{
+		h:      h,
+		depth:  depth,
+		values: make(map[string]*big.Int),
+		nodes:  make([]map[string][]byte, depth+1),
+		empty:  make([][]byte, depth+1),
+	}
+	t.modulus.Set(modulus)
+	t.empty[0] = make([]byte, h.BlockSize())
+	for level := 0; level <= depth; level++ {
+		t.nodes[level] = make(map[string][]byte)
+		if level > 0 {
+			node, err := t.hash(t.empty[level-1], t.empty[level-1])
+			if err != nil {
+				return nil, err
+			}
+			t.empty[level] = node
+		}
+	}
+	return t, nil
+}
+
+// Depth returns the depth of the tree, which is the number of siblings in a proof
+func (t *Tree) Depth() int {
+	return t.depth
+}
+
+// Root returns the root of the tree
+func (t *Tree) Root() []byte {
+	return t.node(t.depth, new(big.Int))
+}
+
+// Get returns the value stored at key, and false if the leaf of key is empty
+func (t *Tree) Get(key *big.Int) (*big.Int, bool) {
+	v, ok := t.values[string(key.Bytes())]
+	if !ok {
+		return nil, false
+	}
+	return new(big.Int).Set(v), true
+}
+
+// Set stores value at key
+//
+// value must be in [0, modulus), otherwise ErrValueOutOfRange is returned.
+func (t *Tree) Set(key, value *big.Int) error {
+	if err := t.checkKey(key); err != nil {
+		return err
+	}
+	leaf, err := t.Leaf(key, value)
+	if err != nil {
+		return err
+	}
+	if err := t.setLeaf(key, leaf); err != nil {
+		return err
+	}
+	t.values[string(key.Bytes())] = new(big.Int).Set(value)
+	return nil
+}
+
+// Delete empties the leaf of key
+func (t *Tree) Delete(key *big.Int) error {
+	if err := t.checkKey(key); err != nil {
+		return err
+	}
+	if err := t.setLeaf(key, t.empty[0]); err != nil {
+		return err
+	}
+	delete(t.values, string(key.Bytes()))
+	return nil
+}
+
+// Prove returns the siblings of the nodes on the path from the leaf of key to the root
+//
+// the siblings prove the membership of the value stored at key (see VerifyMembership), or the
+// non-membership of key if its leaf is empty (see VerifyNonMembership); they also prove an update
+// of the leaf (see VerifyUpdate), as the siblings are not modified by the update.
+func (t *Tree) Prove(key *big.Int) ([][]byte, error) {
+	if err := t.checkKey(key); err != nil {
+		return nil, err
+	}
+	siblings := make([][]byte, t.depth)
+	var index, sibling big.Int
+	index.Set(key)
+	for level := range siblings {
+		sibling.SetBit(&index, 0, index.Bit(0)^1)
+		siblings[level] = t.node(level, &sibling)
+		index.Rsh(&index, 1)
+	}
+	return siblings, nil
+}
+
+// Leaf returns the leaf storing value at key, H(key, value)
+func (t *Tree) Leaf(key, value *big.Int) ([]byte, error) {
+	if err := t.checkKey(key); err != nil {
+		return nil, err
+	}
+	if value.Sign() < 0 || value.Cmp(&t.modulus) >= 0 {
+		return nil, ErrValueOutOfRange
+	}
+	return t.hash(t.element(key), t.element(value))
+}
+
+// setLeaf sets the leaf of key and updates the nodes on its path to the root; the tree
+// is not modified if the hash function fails
+func (t *Tree) setLeaf(key *big.Int, leaf []byte) error {
+	// path[level] is the new node of the level on the path
+	path := make([][]byte, t.depth+1)
+	path[0] = leaf
+	var index, sibling big.Int
+	index.Set(key)
+	for level := 0; level < t.depth; level++ {
+		sibling.SetBit(&index, 0, index.Bit(0)^1)
+		left, right := path[level], t.node(level, &sibling)
+		if index.Bit(0) == 1 {
+			left, right = right, left
+		}
+		node, err := t.hash(left, right)
+		if err != nil {
+			return err
+		}
+		path[level+1] = node
+		index.Rsh(&index, 1)
+	}
+
+	index.Set(key)
+	for level := range path {
+		t.setNode(level, &index, path[level])
+		index.Rsh(&index, 1)
+	}
+	return nil
+}
+
+// node returns the node of the level at index
+func (t *Tree) node(level int, index *big.Int) []byte {
+	if n, ok := t.nodes[level][string(index.Bytes())]; ok {
+		return n
+	}
+	return t.empty[level]
+}
+
+// setNode sets the node of the level at index; the empty nodes are not stored
+func (t *Tree) setNode(level int, index *big.Int, node []byte) {
+	if bytes.Equal(node, t.empty[level]) {
+		delete(t.nodes[level], string(index.Bytes()))
+		return
+	}
+	t.nodes[level][string(index.Bytes())] = node
+}
+
+// hash returns H(a, b)
+func (t *Tree) hash(a, b []byte) ([]byte, error) {
+	t.h.Reset()
+	if _, err := t.h.Write(a); err != nil {
+		return nil, err
+	}
+	if _, err := t.h.Write(b); err != nil {
+		return nil, err
+	}
+	return t.h.Sum(nil), nil
+}
+
+// element returns x in big endian, on h.BlockSize() bytes; x must be in [0, modulus)
+func (t *Tree) element(x *big.Int) []byte {
+	res := make([]byte, t.h.BlockSize())
+	return x.FillBytes(res)
+}
+
+func (t *Tree) checkKey(key *big.Int) error {
+	if key.Sign() < 0 || key.BitLen() > t.depth || key.Cmp(&t.modulus) >= 0 {
+		return ErrKeyOutOfRange
+	}
+	return nil
+}
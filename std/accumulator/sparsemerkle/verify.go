@@ -0,0 +1,138 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sparsemerkle provides ZKP-circuit functions to verify proofs in key-indexed sparse Merkle trees,
+// and a native implementation of the trees producing matching proofs.
+//
+// The tree has a fixed depth: the leaf of a key in [0, 2^depth) is at the position given by the bits of
+// the key, the bit i selecting the left (0) or right (1) child at the level i from the leaves. An empty leaf
+// is 0, the leaf storing a value is H(key, value), and a node is H(left, right), with H the MiMC hash.
+package sparsemerkle
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	frbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	frbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	frbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	frbn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	frbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	frbw6672 "github.com/consensys/gnark-crypto/ecc/bw6-672/fr"
+	frbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// Leaf returns the leaf storing value at key, H(key, value)
+func Leaf(cs *frontend.ConstraintSystem, h mimc.MiMC, key, value frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(key, value)
+	return h.Sum()
+}
+
+// VerifyMembership asserts that the tree of root stores value at key
+//
+// siblings are the siblings of the nodes on the path from the leaf to the root;
+// the depth of the tree is len(siblings), which must be smaller than the bit length of the field.
+func VerifyMembership(cs *frontend.ConstraintSystem, h mimc.MiMC, root, key, value frontend.Variable, siblings []frontend.Variable) {
+	keyBits := toBinary(cs, h, key, len(siblings))
+	cs.AssertIsEqual(computeRoot(cs, h, keyBits, Leaf(cs, h, key, value), siblings), root)
+}
+
+// VerifyNonMembership asserts that the leaf of key is empty in the tree of root
+//
+// siblings are the siblings of the nodes on the path from the leaf to the root;
+// the depth of the tree is len(siblings), which must be smaller than the bit length of the field.
+func VerifyNonMembership(cs *frontend.ConstraintSystem, h mimc.MiMC, root, key frontend.Variable, siblings []frontend.Variable) {
+	keyBits := toBinary(cs, h, key, len(siblings))
+	cs.AssertIsEqual(computeRoot(cs, h, keyBits, 0, siblings), root)
+}
+
+// VerifyUpdate asserts that the leaf of key stores oldValue in the tree of oldRoot, and that newRoot
+// is the root of the same tree in which the leaf stores newValue
+//
+// the leaves are computed with Leaf from key and the values, so that they are bound to key. oldExists (resp.
+// newExists) must be 0 if the leaf is empty before (resp. after) the update, for an insertion (resp. a
+// deletion), and 1 otherwise: they are constrained to be boolean, and the value of an empty leaf is ignored.
+// Both roots are computed in the same walk of the path, with the same siblings (the siblings of the nodes
+// on the path from the leaf to the root); the depth of the tree is len(siblings), which must be smaller than
+// the bit length of the field.
+func VerifyUpdate(cs *frontend.ConstraintSystem, h mimc.MiMC, oldRoot, newRoot, key, oldValue, newValue, oldExists, newExists frontend.Variable, siblings []frontend.Variable) {
+	keyBits := toBinary(cs, h, key, len(siblings))
+
+	oldNode := cs.Select(oldExists, Leaf(cs, h, key, oldValue), 0)
+	newNode := cs.Select(newExists, Leaf(cs, h, key, newValue), 0)
+	for i := range siblings {
+		oldNode = parent(cs, h, keyBits[i], oldNode, siblings[i])
+		newNode = parent(cs, h, keyBits[i], newNode, siblings[i])
+	}
+
+	cs.AssertIsEqual(oldNode, oldRoot)
+	cs.AssertIsEqual(newNode, newRoot)
+}
+
+// toBinary returns the depth bits of key; depth must be smaller than the bit length of the field, so that
+// the decomposition is unique, otherwise key and key + modulus would have the same path
+func toBinary(cs *frontend.ConstraintSystem, h mimc.MiMC, key frontend.Variable, depth int) []frontend.Variable {
+	modulus := fieldModulus(h.CurveID())
+	if depth >= modulus.BitLen() {
+		panic("sparsemerkle: the depth must be smaller than the bit length of the field")
+	}
+	return cs.ToBinary(key, depth)
+}
+
+// fieldModulus returns the modulus of the scalar field of curveID
+func fieldModulus(curveID ecc.ID) *big.Int {
+	switch curveID {
+	case ecc.BN254:
+		return frbn254.Modulus()
+	case ecc.BLS12_377:
+		return frbls12377.Modulus()
+	case ecc.BLS12_381:
+		return frbls12381.Modulus()
+	case ecc.BW6_761:
+		return frbw6761.Modulus()
+	case ecc.BLS24_315:
+		return frbls24315.Modulus()
+	case ecc.BW6_672:
+		return frbw6672.Modulus()
+	case ecc.BW6_633:
+		return frbw6633.Modulus()
+	default:
+		panic("not implemented")
+	}
+}
+
+// computeRoot returns the root of the tree with leaf at the position given by keyBits
+func computeRoot(cs *frontend.ConstraintSystem, h mimc.MiMC, keyBits []frontend.Variable, leaf interface{}, siblings []frontend.Variable) frontend.Variable {
+	node := cs.Constant(leaf)
+	for i := range siblings {
+		node = parent(cs, h, keyBits[i], node, siblings[i])
+	}
+	return node
+}
+
+// parent returns H(node, sibling) if bit is 0, H(sibling, node) otherwise
+func parent(cs *frontend.ConstraintSystem, h mimc.MiMC, bit, node, sibling frontend.Variable) frontend.Variable {
+	left := cs.Select(bit, sibling, node)
+	right := cs.Sub(cs.Add(node, sibling), left) // no constraint is recorded
+
+	h.Reset()
+	h.Write(left, right)
+	return h.Sum()
+}
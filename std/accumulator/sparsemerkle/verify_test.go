@@ -0,0 +1,273 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparsemerkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+const testDepth = 8
+
+type membershipCircuit struct {
+	Root, Key, Value frontend.Variable `gnark:",public"`
+	Siblings         [testDepth]frontend.Variable
+}
+
+func (circuit *membershipCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	VerifyMembership(cs, h, circuit.Root, circuit.Key, circuit.Value, circuit.Siblings[:])
+	return nil
+}
+
+type nonMembershipCircuit struct {
+	Root, Key frontend.Variable `gnark:",public"`
+	Siblings  [testDepth]frontend.Variable
+}
+
+func (circuit *nonMembershipCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	VerifyNonMembership(cs, h, circuit.Root, circuit.Key, circuit.Siblings[:])
+	return nil
+}
+
+type updateCircuit struct {
+	OldRoot, NewRoot, Key frontend.Variable `gnark:",public"`
+	OldValue, NewValue    frontend.Variable
+	OldExists, NewExists  frontend.Variable
+	Siblings              [testDepth]frontend.Variable
+}
+
+func (circuit *updateCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	VerifyUpdate(cs, h, circuit.OldRoot, circuit.NewRoot, circuit.Key, circuit.OldValue, circuit.NewValue, circuit.OldExists, circuit.NewExists, circuit.Siblings[:])
+	return nil
+}
+
+// prove returns the proof of key in the tree, as a witness
+func prove(t *testing.T, tree *Tree, key int64) (siblings [testDepth]frontend.Variable) {
+	proof, err := tree.Prove(big.NewInt(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range siblings {
+		siblings[i].Assign(proof[i])
+	}
+	return
+}
+
+func TestSparseMerkle(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	tree, err := NewTree(hash.MIMC_BN254.New("seed"), fr.Modulus(), testDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[int64]int64{3: 10, 200: 20, 17: 30, 255: 0} {
+		if err := tree.Set(big.NewInt(key), big.NewInt(value)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tree.Set(big.NewInt(256), big.NewInt(1)); err != ErrKeyOutOfRange {
+		t.Fatal("expected ErrKeyOutOfRange, got", err)
+	}
+	if err := tree.Set(big.NewInt(1), fr.Modulus()); err != ErrValueOutOfRange {
+		t.Fatal("expected ErrValueOutOfRange, got", err)
+	}
+	if err := tree.Set(big.NewInt(1), big.NewInt(-1)); err != ErrValueOutOfRange {
+		t.Fatal("expected ErrValueOutOfRange, got", err)
+	}
+
+	// membership
+	{
+		r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &membershipCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		newWitness := func(key, value int64) *membershipCircuit {
+			var witness membershipCircuit
+			witness.Root.Assign(tree.Root())
+			witness.Key.Assign(key)
+			witness.Value.Assign(value)
+			witness.Siblings = prove(t, tree, key)
+			return &witness
+		}
+		assert.SolvingSucceeded(r1cs, newWitness(200, 20))
+		assert.SolvingSucceeded(r1cs, newWitness(255, 0)) // 0 is stored in a non-empty leaf
+		assert.SolvingFailed(r1cs, newWitness(200, 21))
+		assert.SolvingFailed(r1cs, newWitness(4, 0))
+	}
+
+	// non-membership
+	{
+		r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &nonMembershipCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		newWitness := func(key int64) *nonMembershipCircuit {
+			var witness nonMembershipCircuit
+			witness.Root.Assign(tree.Root())
+			witness.Key.Assign(key)
+			witness.Siblings = prove(t, tree, key)
+			return &witness
+		}
+		assert.SolvingSucceeded(r1cs, newWitness(4))
+		assert.SolvingSucceeded(r1cs, newWitness(0))
+		assert.SolvingFailed(r1cs, newWitness(3))
+		assert.SolvingFailed(r1cs, newWitness(255))
+	}
+
+	// updates: insertion, modification and deletion
+	{
+		r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &updateCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		update := func(key int64, oldValue, newValue *big.Int) (good, bad *updateCircuit) {
+			newWitness := func() *updateCircuit {
+				var witness updateCircuit
+				witness.OldRoot.Assign(tree.Root())
+				witness.Key.Assign(key)
+				witness.Siblings = prove(t, tree, key)
+				witness.OldExists.Assign(0)
+				witness.OldValue.Assign(0)
+				if oldValue != nil {
+					witness.OldExists = frontend.Variable{}
+					witness.OldExists.Assign(1)
+					witness.OldValue = frontend.Variable{}
+					witness.OldValue.Assign(oldValue)
+				}
+				return &witness
+			}
+			good, bad = newWitness(), newWitness()
+
+			if newValue != nil {
+				if err := tree.Set(big.NewInt(key), newValue); err != nil {
+					t.Fatal(err)
+				}
+			} else if err := tree.Delete(big.NewInt(key)); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, witness := range []*updateCircuit{good, bad} {
+				witness.NewRoot.Assign(tree.Root())
+				if newValue != nil {
+					witness.NewExists.Assign(1)
+					witness.NewValue.Assign(newValue)
+				} else {
+					witness.NewExists.Assign(0)
+					witness.NewValue.Assign(0)
+				}
+			}
+			bad.NewValue = frontend.Variable{}
+			bad.NewValue.Assign(1234)
+			bad.NewExists = frontend.Variable{}
+			bad.NewExists.Assign(1)
+			return
+		}
+
+		good, bad := update(4, nil, big.NewInt(40))
+		assert.SolvingSucceeded(r1cs, good)
+		assert.SolvingFailed(r1cs, bad)
+
+		good, bad = update(3, big.NewInt(10), big.NewInt(11))
+		assert.SolvingSucceeded(r1cs, good)
+		assert.SolvingFailed(r1cs, bad)
+
+		good, bad = update(17, big.NewInt(30), nil)
+		assert.SolvingSucceeded(r1cs, good)
+		assert.SolvingFailed(r1cs, bad)
+	}
+
+	// the tree is the same as a tree built with the final values only
+	other, err := NewTree(hash.MIMC_BN254.New("seed"), fr.Modulus(), testDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[int64]int64{3: 11, 4: 40, 200: 20, 255: 0} {
+		if err := other.Set(big.NewInt(key), big.NewInt(value)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if string(other.Root()) != string(tree.Root()) {
+		t.Fatal("the roots of the trees differ")
+	}
+	if v, ok := tree.Get(big.NewInt(3)); !ok || v.Int64() != 11 {
+		t.Fatal("unexpected value at key 3", v, ok)
+	}
+	if _, ok := tree.Get(big.NewInt(17)); ok {
+		t.Fatal("key 17 should be deleted")
+	}
+}
+
+type depthCircuit struct {
+	Root, Key frontend.Variable `gnark:",public"`
+	Siblings  []frontend.Variable
+}
+
+func (circuit *depthCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	VerifyNonMembership(cs, h, circuit.Root, circuit.Key, circuit.Siblings)
+	return nil
+}
+
+func TestSparseMerkleDepth(t *testing.T) {
+	// with as many levels as bits in the field, key and key + modulus would have the same path
+	depth := fr.Modulus().BitLen()
+	if _, err := NewTree(hash.MIMC_BN254.New("seed"), fr.Modulus(), depth); err == nil {
+		t.Fatal("expected an error for a depth of the bit length of the modulus")
+	}
+	if _, err := frontend.Compile(ecc.BN254, backend.GROTH16, &depthCircuit{Siblings: make([]frontend.Variable, depth)}); err == nil {
+		t.Fatal("expected an error for a depth of the bit length of the field")
+	}
+
+	// at the maximal depth, the aliases of a key are out of range
+	tree, err := NewTree(hash.MIMC_BN254.New("seed"), fr.Modulus(), depth-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Set(big.NewInt(3), big.NewInt(10)); err != nil {
+		t.Fatal(err)
+	}
+	alias := new(big.Int).Add(fr.Modulus(), big.NewInt(3))
+	if _, err := tree.Prove(alias); err != ErrKeyOutOfRange {
+		t.Fatal("expected ErrKeyOutOfRange, got", err)
+	}
+	if err := tree.Set(alias, big.NewInt(0)); err != ErrKeyOutOfRange {
+		t.Fatal("expected ErrKeyOutOfRange, got", err)
+	}
+}
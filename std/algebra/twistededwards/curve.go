@@ -31,6 +31,8 @@ import (
 	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
 	frbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
 	edbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/twistededwards"
+	frbw6672 "github.com/consensys/gnark-crypto/ecc/bw6-672/fr"
+	edbw6672 "github.com/consensys/gnark-crypto/ecc/bw6-672/twistededwards"
 	frbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
 	edbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/twistededwards"
 	"github.com/consensys/gnark/frontend"
@@ -52,6 +54,7 @@ func init() {
 	newTwistedEdwards[ecc.BW6_761] = newEdBW761
 	newTwistedEdwards[ecc.BLS24_315] = newEdBLS315
 	newTwistedEdwards[ecc.BW6_633] = newEdBW633
+	newTwistedEdwards[ecc.BW6_672] = newEdBW672
 }
 
 // NewEdCurve returns an Edwards curve parameters
@@ -62,6 +65,12 @@ func NewEdCurve(id ecc.ID) (EdCurve, error) {
 	return EdCurve{}, errors.New("unknown curve id")
 }
 
+// scalarSize returns the number of bits in which the scalars are decomposed: the size
+// of the snark field, rounded up to a multiple of 64
+func (curve EdCurve) scalarSize() int {
+	return (curve.Modulus.BitLen() + 63) / 64 * 64
+}
+
 // -------------------------------------------------------------------------------------------------
 // constructors
 
@@ -185,3 +194,23 @@ func newEdBW633() EdCurve {
 
 	return res
 }
+
+func newEdBW672() EdCurve {
+
+	edcurve := edbw6672.GetEdwardsCurve()
+	var cofactorReg big.Int
+	edcurve.Cofactor.ToBigInt(&cofactorReg)
+
+	res := EdCurve{
+		A:        frontend.FromInterface(edcurve.A),
+		D:        frontend.FromInterface(edcurve.D),
+		Cofactor: frontend.FromInterface(cofactorReg),
+		Order:    frontend.FromInterface(edcurve.Order),
+		BaseX:    frontend.FromInterface(edcurve.Base.X),
+		BaseY:    frontend.FromInterface(edcurve.Base.Y),
+		ID:       ecc.BW6_672,
+	}
+	res.Modulus.Set(frbw6672.Modulus())
+
+	return res
+}
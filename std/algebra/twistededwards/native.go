@@ -0,0 +1,83 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twistededwards
+
+import "math/big"
+
+// NativePoint is a point of the twisted Edwards curve, out of a circuit
+type NativePoint struct {
+	X, Y big.Int
+}
+
+// IsOnCurve returns true if p is a point of the curve with reduced coordinates:
+// a*x^2 + y^2 = 1 + d*x^2*y^2
+func (p *NativePoint) IsOnCurve(curve EdCurve) bool {
+	m := &curve.Modulus
+	if p.X.Sign() < 0 || p.X.Cmp(m) >= 0 || p.Y.Sign() < 0 || p.Y.Cmp(m) >= 0 {
+		return false
+	}
+
+	var xx, yy, lhs, rhs big.Int
+	xx.Mul(&p.X, &p.X)
+	yy.Mul(&p.Y, &p.Y)
+	lhs.Mul(&curve.A, &xx).Add(&lhs, &yy).Mod(&lhs, m)
+	rhs.Mul(&curve.D, &xx).Mul(&rhs, &yy).Add(&rhs, big.NewInt(1)).Mod(&rhs, m)
+	return lhs.Cmp(&rhs) == 0
+}
+
+// NativeAdd returns the sum of the points p1 and p2 of the curve, out of a circuit
+func NativeAdd(curve EdCurve, p1, p2 NativePoint) NativePoint {
+	m := &curve.Modulus
+
+	var x1y2, y1x2, x1x2, y1y2, dxy big.Int
+	x1y2.Mul(&p1.X, &p2.Y)
+	y1x2.Mul(&p1.Y, &p2.X)
+	x1x2.Mul(&p1.X, &p2.X)
+	y1y2.Mul(&p1.Y, &p2.Y)
+	dxy.Mul(&x1x2, &y1y2).Mul(&dxy, &curve.D).Mod(&dxy, m)
+
+	var res NativePoint
+	var num, den big.Int
+
+	// x3 = (x1*y2 + y1*x2) / (1 + d*x1*x2*y1*y2)
+	num.Add(&x1y2, &y1x2)
+	den.Add(big.NewInt(1), &dxy).ModInverse(&den, m)
+	res.X.Mul(&num, &den).Mod(&res.X, m)
+
+	// y3 = (y1*y2 - a*x1*x2) / (1 - d*x1*x2*y1*y2)
+	num.Mul(&curve.A, &x1x2).Sub(&y1y2, &num)
+	den.Sub(big.NewInt(1), &dxy).Mod(&den, m).ModInverse(&den, m)
+	res.Y.Mul(&num, &den).Mod(&res.Y, m)
+
+	return res
+}
+
+// NativeScalarMul returns [s]p1 on the curve, out of a circuit, s being non negative
+//
+// it runs in variable time, on math/big integers: s must not be a secret exposed to an attacker.
+// It is meant to compute the constants and the witnesses of the circuits, and test vectors.
+func NativeScalarMul(curve EdCurve, p1 NativePoint, s *big.Int) NativePoint {
+	res := NativePoint{}
+	res.Y.SetUint64(1)
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res = NativeAdd(curve, res, res)
+		if s.Bit(i) == 1 {
+			res = NativeAdd(curve, res, p1)
+		}
+	}
+	return res
+}
@@ -19,7 +19,6 @@ package twistededwards
 import (
 	"math/big"
 
-	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/frontend"
 )
 
@@ -46,6 +45,28 @@ func (p *Point) MustBeOnCurve(cs *frontend.ConstraintSystem, curve EdCurve) {
 
 }
 
+// MustBeScalar checks that scalar is smaller than the order of the subgroup of the curve
+//
+// The scalar multiplications decompose their scalar on the size of the snark field, so that scalar and
+// scalar + curve.Order give the same point; MustBeScalar makes the scalar unique. The bits of scalar are
+// compared to those of curve.Order - 1, from the most significant one.
+func MustBeScalar(cs *frontend.ConstraintSystem, scalar frontend.Variable, curve EdCurve) {
+	var bound big.Int
+	bound.Sub(&curve.Order, big.NewInt(1))
+	bits := cs.ToBinary(scalar, bound.BitLen())
+
+	// p is 1 while the bits of scalar are equal to those of bound: a bit of scalar can't be set
+	// where the bit of bound is not, unless p is 0
+	p := cs.Constant(1)
+	for i := len(bits) - 1; i >= 0; i-- {
+		if bound.Bit(i) == 1 {
+			p = cs.Mul(p, bits[i])
+		} else {
+			cs.AssertIsEqualIf(bits[i], p, 0)
+		}
+	}
+}
+
 // AddFixedPoint Adds two points, among which is one fixed point (the base), on a twisted edwards curve (eg jubjub)
 // p1, base, ecurve are respectively: the point to add, a known base point, and the parameters of the twisted edwards curve
 func (p *Point) AddFixedPoint(cs *frontend.ConstraintSystem, p1 *Point /*basex*/, x /*basey*/, y interface{}, curve EdCurve) *Point {
@@ -124,16 +145,7 @@ func (p *Point) Double(cs *frontend.ConstraintSystem, p1 *Point, curve EdCurve)
 func (p *Point) ScalarMulNonFixedBase(cs *frontend.ConstraintSystem, p1 *Point, scalar frontend.Variable, curve EdCurve) *Point {
 
 	// first unpack the scalar
-	// TODO handle this properly (put the size in curve struct probably)
-	var frSize int
-	if curve.ID == ecc.BW6_761 {
-		frSize = 384
-	} else if curve.ID == ecc.BW6_633 {
-		frSize = 320
-	} else {
-		frSize = 256
-	}
-	b := cs.ToBinary(scalar, frSize)
+	b := cs.ToBinary(scalar, curve.scalarSize())
 
 	res := Point{
 		cs.Constant(0),
@@ -161,16 +173,7 @@ func (p *Point) ScalarMulNonFixedBase(cs *frontend.ConstraintSystem, p1 *Point,
 func (p *Point) ScalarMulFixedBase(cs *frontend.ConstraintSystem, x, y interface{}, scalar frontend.Variable, curve EdCurve) *Point {
 
 	// first unpack the scalar
-	// TODO handle this properly (put the size in curve struct probably)
-	var frSize int
-	if curve.ID == ecc.BW6_761 {
-		frSize = 384
-	} else if curve.ID == ecc.BW6_633 {
-		frSize = 320
-	} else {
-		frSize = 256
-	}
-	b := cs.ToBinary(scalar, frSize)
+	b := cs.ToBinary(scalar, curve.scalarSize())
 
 	res := Point{
 		cs.Constant(0),
@@ -333,3 +333,42 @@ func TestNeg(t *testing.T) {
 	assert.SolvingSucceeded(r1cs, &witness)
 
 }
+
+func TestNativeScalarMul(t *testing.T) {
+
+	params, err := NewEdCurve(ecc.BN254)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var base, expected twistededwards.PointAffine
+	base.X.SetBigInt(&params.BaseX)
+	base.Y.SetBigInt(&params.BaseY)
+	r := big.NewInt(230928302)
+	expected.ScalarMul(&base, r)
+
+	var p NativePoint
+	p.X.Set(&params.BaseX)
+	p.Y.Set(&params.BaseY)
+	if !p.IsOnCurve(params) {
+		t.Fatal("the base point should be on the curve")
+	}
+
+	res := NativeScalarMul(params, p, r)
+	if res.X.String() != expected.X.String() || res.Y.String() != expected.Y.String() {
+		t.Fatal("unexpected scalar multiplication", res.X.String(), res.Y.String())
+	}
+	if !res.IsOnCurve(params) {
+		t.Fatal("the result should be on the curve")
+	}
+
+	// the base point is in the subgroup of order params.Order
+	res = NativeScalarMul(params, p, &params.Order)
+	if res.X.Sign() != 0 || res.Y.Cmp(big.NewInt(1)) != 0 {
+		t.Fatal("[order]base should be the identity")
+	}
+
+	p.Y.Add(&p.Y, big.NewInt(1))
+	if p.IsOnCurve(params) {
+		t.Fatal("the point should not be on the curve")
+	}
+}
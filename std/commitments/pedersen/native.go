@@ -0,0 +1,197 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// ErrScalarOutOfRange is returned when a committed value or a blinding factor is not in [0, curve.Order)
+var ErrScalarOutOfRange = errors.New("pedersen: scalar out of range")
+
+const (
+	domainCommitValue    = "gnark/pedersen/commit/value"
+	domainCommitBlinding = "gnark/pedersen/commit/blinding"
+	domainHash           = "gnark/pedersen/hash"
+)
+
+// Generator returns the generator of the given domain and index
+//
+// it hashes (with SHA256) the domain, the index and a counter to the coordinate y of a point
+// of the curve, incrementing the counter until a point is found, and clears its cofactor.
+// Its discrete logarithm in any other base is unknown.
+func Generator(curve twistededwards.EdCurve, domain string, index int) twistededwards.NativePoint {
+	p := &curve.Modulus
+	var buf [8]byte
+	binary.BigEndian.PutUint32(buf[:4], uint32(index))
+
+	for counter := uint32(0); ; counter++ {
+		binary.BigEndian.PutUint32(buf[4:], counter)
+		h := sha256.New()
+		h.Write([]byte(domain))
+		h.Write(buf[:])
+
+		var y, yy, num, den big.Int
+		y.SetBytes(h.Sum(nil)).Mod(&y, p)
+
+		// a*x^2 + y^2 = 1 + d*x^2*y^2, so x^2 = (1 - y^2) / (a - d*y^2)
+		yy.Mul(&y, &y)
+		num.Sub(big.NewInt(1), &yy).Mod(&num, p)
+		den.Mul(&curve.D, &yy).Sub(&curve.A, &den).Mod(&den, p)
+		if den.ModInverse(&den, p) == nil {
+			continue
+		}
+		var x big.Int
+		num.Mul(&num, &den).Mod(&num, p)
+		if x.ModSqrt(&num, p) == nil {
+			continue
+		}
+		if x.Bit(0) == 1 {
+			x.Sub(p, &x)
+		}
+
+		var res twistededwards.NativePoint
+		res.X.Set(&x)
+		res.Y.Set(&y)
+		res = twistededwards.NativeScalarMul(curve, res, &curve.Cofactor)
+		if res.X.Sign() == 0 {
+			continue // point of small order
+		}
+		return res
+	}
+}
+
+// CommitmentGenerators returns the generators G_i of n values and H of the blinding factor of the commitments
+func CommitmentGenerators(curve twistededwards.EdCurve, n int) ([]twistededwards.NativePoint, twistededwards.NativePoint) {
+	gs := make([]twistededwards.NativePoint, n)
+	for i := range gs {
+		gs[i] = Generator(curve, domainCommitValue, i)
+	}
+	return gs, Generator(curve, domainCommitBlinding, 0)
+}
+
+// NativeCommit returns the Pedersen commitment sum([values[i]]G_i) + [blinding]H, as computed by Commit
+//
+// the values and the blinding factor must be in [0, curve.Order), otherwise ErrScalarOutOfRange is returned.
+func NativeCommit(curve twistededwards.EdCurve, values []*big.Int, blinding *big.Int) (twistededwards.NativePoint, error) {
+	if !isScalar(curve, blinding) {
+		return twistededwards.NativePoint{}, ErrScalarOutOfRange
+	}
+	gs, h := CommitmentGenerators(curve, len(values))
+	res := twistededwards.NativeScalarMul(curve, h, blinding)
+	for i := range values {
+		if !isScalar(curve, values[i]) {
+			return twistededwards.NativePoint{}, ErrScalarOutOfRange
+		}
+		res = twistededwards.NativeAdd(curve, res, twistededwards.NativeScalarMul(curve, gs[i], values[i]))
+	}
+	return res, nil
+}
+
+// isScalar returns true if s is in [0, curve.Order)
+func isScalar(curve twistededwards.EdCurve, s *big.Int) bool {
+	return s.Sign() >= 0 && s.Cmp(&curve.Order) < 0
+}
+
+// NativeHash returns the Pedersen hash of the bits of data, as computed by Hash
+func NativeHash(curve twistededwards.EdCurve, data ...*big.Int) twistededwards.NativePoint {
+	nbBits := curve.Modulus.BitLen()
+	bits := make([]bool, 0, len(data)*nbBits)
+	for _, d := range data {
+		var reduced big.Int
+		reduced.Mod(d, &curve.Modulus)
+		for i := 0; i < nbBits; i++ {
+			bits = append(bits, reduced.Bit(i) == 1)
+		}
+	}
+	return NativeHashBits(curve, bits)
+}
+
+// NativeHashBits returns the Pedersen hash of bits, as computed by HashBits
+func NativeHashBits(curve twistededwards.EdCurve, bits []bool) twistededwards.NativePoint {
+	c := chunksPerSegment(curve)
+	nbChunks := (len(bits) + 2) / 3
+
+	res := twistededwards.NativePoint{}
+	res.Y.SetUint64(1)
+	for j := 0; j*c < nbChunks; j++ {
+		// s = sum(enc_i * 2^(4i)) over the chunks of the segment
+		var s, enc, shift big.Int
+		shift.SetUint64(1)
+		for i := j * c; i < nbChunks && i < (j+1)*c; i++ {
+			enc.SetUint64(1)
+			if 3*i < len(bits) && bits[3*i] {
+				enc.Add(&enc, big.NewInt(1))
+			}
+			if 3*i+1 < len(bits) && bits[3*i+1] {
+				enc.Add(&enc, big.NewInt(2))
+			}
+			if 3*i+2 < len(bits) && bits[3*i+2] {
+				enc.Neg(&enc)
+			}
+			enc.Mul(&enc, &shift)
+			s.Add(&s, &enc)
+			shift.Lsh(&shift, 4)
+		}
+		s.Mod(&s, &curve.Order)
+		res = twistededwards.NativeAdd(curve, res, twistededwards.NativeScalarMul(curve, Generator(curve, domainHash, j), &s))
+	}
+
+	return res
+}
+
+// chunksPerSegment returns the largest number of chunks c such that 4*sum(2^(4i), i < c) < (order-1)/2:
+// the scalars of a segment are then distinct modulo the order
+func chunksPerSegment(curve twistededwards.EdCurve) int {
+	var bound, max, shift big.Int
+	bound.Sub(&curve.Order, big.NewInt(1)).Rsh(&bound, 1)
+	shift.SetUint64(4)
+	c := 0
+	for {
+		max.Add(&max, &shift)
+		if max.Cmp(&bound) >= 0 {
+			return c
+		}
+		c++
+		shift.Lsh(&shift, 4)
+	}
+}
+
+// hashTables returns, for each of the nbChunks chunks of a hash, the points [k*2^(4i)]G_j for k = 1..4,
+// where j is the segment of the chunk and i its index in the segment
+func hashTables(curve twistededwards.EdCurve, nbChunks int) [][4]twistededwards.NativePoint {
+	c := chunksPerSegment(curve)
+	tables := make([][4]twistededwards.NativePoint, nbChunks)
+	var base twistededwards.NativePoint
+	for i := range tables {
+		if i%c == 0 {
+			base = Generator(curve, domainHash, i/c)
+		} else {
+			base = twistededwards.NativeScalarMul(curve, base, big.NewInt(16))
+		}
+		tables[i][0] = base
+		for k := 1; k < 4; k++ {
+			tables[i][k] = twistededwards.NativeAdd(curve, tables[i][k-1], base)
+		}
+	}
+	return tables
+}
@@ -0,0 +1,166 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pedersen provides ZKP-circuit functions to compute Pedersen commitments and Pedersen hashes
+// on the twisted Edwards curve embedded in the snark field, with native implementations giving the same points.
+//
+// The generators are derived deterministically from a domain and an index (see Generator), and are
+// constants of the circuits.
+//
+// The Pedersen hash follows the one of Zcash (Sapling): the bits of the message are split in chunks
+// of 3 bits (s0, s1, s2), encoded as enc = (1 - 2*s2)*(1 + s0 + 2*s1), and grouped in segments of c chunks.
+// The hash is the sum over the segments j of [sum(enc_i * 2^(4i))]G_j, c being the largest number of chunks
+// for which the scalars of a segment are distinct modulo the order of the subgroup of the generators.
+// In the circuit, the multiples of the generators are precomputed: each chunk costs a lookup in a table
+// of 4 points, a conditional negation and a point addition.
+package pedersen
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Commit returns the Pedersen commitment sum([values[i]]G_i) + [blinding]H
+// (see CommitmentGenerators)
+//
+// the values and the blinding factor must be in [0, curve.Order): they are constrained as such, so
+// that a commitment can't be opened to two different values.
+func Commit(cs *frontend.ConstraintSystem, curve twistededwards.EdCurve, values []frontend.Variable, blinding frontend.Variable) twistededwards.Point {
+	gs, h := CommitmentGenerators(curve, len(values))
+
+	var bound big.Int
+	bound.Sub(&curve.Order, big.NewInt(1))
+
+	res := scalarMulBits(cs, curve, h, toBinary(cs, blinding, &bound))
+	for i := range values {
+		term := scalarMulBits(cs, curve, gs[i], toBinary(cs, values[i], &bound))
+		res.AddGeneric(cs, &res, &term, curve)
+	}
+
+	return res
+}
+
+// Hash returns the Pedersen hash of the bits of data, each element being decomposed
+// in the number of bits of the snark field, least significant bit first
+//
+// the decomposition is constrained to be the canonical one, of the element reduced modulo the snark field.
+func Hash(cs *frontend.ConstraintSystem, curve twistededwards.EdCurve, data ...frontend.Variable) twistededwards.Point {
+	var bound big.Int
+	bound.Sub(&curve.Modulus, big.NewInt(1))
+
+	bits := make([]frontend.Variable, 0, len(data)*bound.BitLen())
+	for _, d := range data {
+		bits = append(bits, toBinary(cs, d, &bound)...)
+	}
+	return HashBits(cs, curve, bits)
+}
+
+// HashBits returns the Pedersen hash of bits
+//
+// the bits are constrained to be boolean; the last chunk is padded with zeros.
+func HashBits(cs *frontend.ConstraintSystem, curve twistededwards.EdCurve, bits []frontend.Variable) twistededwards.Point {
+	if len(bits) == 0 {
+		panic("pedersen: no bits to hash")
+	}
+
+	tables := hashTables(curve, (len(bits)+2)/3)
+
+	var res twistededwards.Point
+	for i := range tables {
+		chunk := bits[3*i:]
+		if len(chunk) > 3 {
+			chunk = chunk[:3]
+		}
+		p := lookupChunk(cs, chunk, tables[i])
+		if i == 0 {
+			res = p
+		} else {
+			res.AddGeneric(cs, &res, &p, curve)
+		}
+	}
+
+	return res
+}
+
+// lookupChunk returns [enc(chunk)]P, where table contains [1]P, [2]P, [3]P and [4]P
+func lookupChunk(cs *frontend.ConstraintSystem, chunk []frontend.Variable, table [4]twistededwards.NativePoint) twistededwards.Point {
+	var res twistededwards.Point
+	if len(chunk) == 1 {
+		cs.AssertIsBoolean(chunk[0])
+		var dx, dy big.Int
+		dx.Sub(&table[1].X, &table[0].X)
+		dy.Sub(&table[1].Y, &table[0].Y)
+		res.X = cs.Add(cs.Mul(chunk[0], &dx), &table[0].X) // no constraint is recorded
+		res.Y = cs.Add(cs.Mul(chunk[0], &dy), &table[0].Y)
+	} else {
+		res.X = selector.Lookup2(cs, chunk[0], chunk[1], table[0].X, table[1].X, table[2].X, table[3].X)
+		res.Y = selector.Lookup2(cs, chunk[0], chunk[1], table[0].Y, table[1].Y, table[2].Y, table[3].Y)
+	}
+
+	// the negation of (x, y) is (-x, y)
+	if len(chunk) == 3 {
+		cs.AssertIsBoolean(chunk[2])
+		res.X = cs.Mul(res.X, cs.Sub(1, cs.Mul(chunk[2], 2)))
+	}
+
+	return res
+}
+
+// toBinary returns the bound.BitLen() bits of v, least significant bit first, v being constrained to be at most bound
+//
+// the bits are compared to bound as in frontend.ConstraintSystem.AssertIsLessOrEqual, so that the decomposition
+// is unique when bound is smaller than the modulus of the snark field.
+func toBinary(cs *frontend.ConstraintSystem, v frontend.Variable, bound *big.Int) []frontend.Variable {
+	bits := cs.ToBinary(v, bound.BitLen())
+
+	// from the most significant bit, p is 1 while the bits of v are equal to those of bound:
+	// a bit of v can't be set where the bit of bound is not, unless p is 0
+	p := cs.Constant(1)
+	for i := len(bits) - 1; i >= 0; i-- {
+		if bound.Bit(i) == 1 {
+			p = cs.Mul(p, bits[i])
+		} else {
+			cs.AssertIsEqualIf(bits[i], p, 0)
+		}
+	}
+
+	return bits
+}
+
+// scalarMulBits returns [s]base, s being given by its bits, least significant bit first
+//
+// the multiples [2^i]base are constants: each bit costs the addition of a constant point and a selection.
+func scalarMulBits(cs *frontend.ConstraintSystem, curve twistededwards.EdCurve, base twistededwards.NativePoint, bits []frontend.Variable) twistededwards.Point {
+	// res = [bits[0]]base, the identity being (0, 1)
+	var res twistededwards.Point
+	var dy big.Int
+	dy.Sub(&base.Y, big.NewInt(1))
+	res.X = cs.Mul(bits[0], &base.X)        // no constraint is recorded
+	res.Y = cs.Add(cs.Mul(bits[0], &dy), 1) // no constraint is recorded
+
+	multiple := base
+	for i := 1; i < len(bits); i++ {
+		multiple = twistededwards.NativeAdd(curve, multiple, multiple)
+		var sum twistededwards.Point
+		sum.AddFixedPoint(cs, &res, multiple.X, multiple.Y, curve)
+		res.X = cs.Select(bits[i], sum.X, res.X)
+		res.Y = cs.Select(bits[i], sum.Y, res.Y)
+	}
+	return res
+}
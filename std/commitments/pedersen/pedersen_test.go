@@ -0,0 +1,158 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+type commitCircuit struct {
+	Values     [3]frontend.Variable
+	Blinding   frontend.Variable
+	Commitment twistededwards.Point `gnark:",public"`
+}
+
+func (circuit *commitCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	curve, err := twistededwards.NewEdCurve(curveID)
+	if err != nil {
+		return err
+	}
+	res := Commit(cs, curve, circuit.Values[:], circuit.Blinding)
+	cs.AssertIsEqual(res.X, circuit.Commitment.X)
+	cs.AssertIsEqual(res.Y, circuit.Commitment.Y)
+	return nil
+}
+
+type hashCircuit struct {
+	Data   [2]frontend.Variable
+	Bits   frontend.Variable
+	Digest twistededwards.Point `gnark:",public"`
+	Short  twistededwards.Point `gnark:",public"`
+}
+
+func (circuit *hashCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	curve, err := twistededwards.NewEdCurve(curveID)
+	if err != nil {
+		return err
+	}
+	res := Hash(cs, curve, circuit.Data[:]...)
+	cs.AssertIsEqual(res.X, circuit.Digest.X)
+	cs.AssertIsEqual(res.Y, circuit.Digest.Y)
+
+	// 7 bits: the last chunk is made of a single bit
+	res = HashBits(cs, curve, cs.ToBinary(circuit.Bits, 7))
+	cs.AssertIsEqual(res.X, circuit.Short.X)
+	cs.AssertIsEqual(res.Y, circuit.Short.Y)
+	return nil
+}
+
+// testCurves are the curves of the tests
+var testCurves = []ecc.ID{ecc.BN254, ecc.BLS12_381}
+
+func TestCommit(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	for _, id := range testCurves {
+		curve, err := twistededwards.NewEdCurve(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		maxScalar := new(big.Int).Sub(&curve.Order, big.NewInt(1))
+		values := []*big.Int{big.NewInt(1), big.NewInt(0), maxScalar}
+		blinding := big.NewInt(123456789)
+		commitment, err := NativeCommit(curve, values, blinding)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var circuit commitCircuit
+		r1cs, err := frontend.Compile(id, backend.GROTH16, &circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		newWitness := func(values []*big.Int, blinding *big.Int) *commitCircuit {
+			var witness commitCircuit
+			for i := range witness.Values {
+				witness.Values[i].Assign(values[i])
+			}
+			witness.Blinding.Assign(blinding)
+			witness.Commitment.X.Assign(commitment.X)
+			witness.Commitment.Y.Assign(commitment.Y)
+			return &witness
+		}
+
+		assert.SolvingSucceeded(r1cs, newWitness(values, blinding))
+		assert.SolvingFailed(r1cs, newWitness(values, big.NewInt(123456788)))
+
+		// the commitment can't be opened to a value shifted by the order of the generators
+		shifted := []*big.Int{new(big.Int).Add(values[0], &curve.Order), values[1], values[2]}
+		assert.SolvingFailed(r1cs, newWitness(shifted, blinding))
+		assert.SolvingFailed(r1cs, newWitness(values, new(big.Int).Add(blinding, &curve.Order)))
+
+		if _, err := NativeCommit(curve, shifted, blinding); err != ErrScalarOutOfRange {
+			t.Fatal("expected ErrScalarOutOfRange, got", err)
+		}
+		if _, err := NativeCommit(curve, values, big.NewInt(-1)); err != ErrScalarOutOfRange {
+			t.Fatal("expected ErrScalarOutOfRange, got", err)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	for _, id := range testCurves {
+		curve, err := twistededwards.NewEdCurve(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := []*big.Int{big.NewInt(42), new(big.Int).Sub(&curve.Modulus, big.NewInt(42))}
+		digest := NativeHash(curve, data...)
+		short := NativeHashBits(curve, []bool{true, false, true, true, true, false, true}) // 93
+
+		var circuit hashCircuit
+		r1cs, err := frontend.Compile(id, backend.GROTH16, &circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		newWitness := func(data []*big.Int, bits int) *hashCircuit {
+			var witness hashCircuit
+			for i := range witness.Data {
+				witness.Data[i].Assign(data[i])
+			}
+			witness.Bits.Assign(bits)
+			witness.Digest.X.Assign(digest.X)
+			witness.Digest.Y.Assign(digest.Y)
+			witness.Short.X.Assign(short.X)
+			witness.Short.Y.Assign(short.Y)
+			return &witness
+		}
+
+		assert.SolvingSucceeded(r1cs, newWitness(data, 93))
+		assert.SolvingFailed(r1cs, newWitness([]*big.Int{data[1], data[0]}, 93))
+		assert.SolvingFailed(r1cs, newWitness(data, 29))
+	}
+}
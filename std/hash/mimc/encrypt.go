@@ -25,6 +25,7 @@ import (
 	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/mimc"
 	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
 	bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/mimc"
+	bw6672 "github.com/consensys/gnark-crypto/ecc/bw6-672/fr/mimc"
 	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/mimc"
 
 	"github.com/consensys/gnark/frontend"
@@ -41,6 +42,7 @@ func init() {
 	encryptFuncs[ecc.BW6_761] = encryptBW761
 	encryptFuncs[ecc.BLS24_315] = encryptBLS315
 	encryptFuncs[ecc.BW6_633] = encryptBW633
+	encryptFuncs[ecc.BW6_672] = encryptBW672
 
 	newMimc = make(map[ecc.ID]func(string, *frontend.ConstraintSystem) MiMC)
 	newMimc[ecc.BN254] = newMimcBN254
@@ -49,6 +51,7 @@ func init() {
 	newMimc[ecc.BW6_761] = newMimcBW761
 	newMimc[ecc.BLS24_315] = newMimcBLS315
 	newMimc[ecc.BW6_633] = newMimcBW633
+	newMimc[ecc.BW6_672] = newMimcBW672
 }
 
 // -------------------------------------------------------------------------------------------------
@@ -124,7 +127,7 @@ func newMimcBLS315(seed string, cs *frontend.ConstraintSystem) MiMC {
 	return res
 }
 
-func newMimcBW633(seed string) MiMC {
+func newMimcBW633(seed string, cs *frontend.ConstraintSystem) MiMC {
 	res := MiMC{}
 	params := bw6633.NewParams(seed)
 	for _, v := range params {
@@ -133,6 +136,22 @@ func newMimcBW633(seed string) MiMC {
 		res.params = append(res.params, cpy)
 	}
 	res.id = ecc.BW6_633
+	res.h = cs.Constant(0)
+	res.cs = cs
+	return res
+}
+
+func newMimcBW672(seed string, cs *frontend.ConstraintSystem) MiMC {
+	res := MiMC{}
+	params := bw6672.NewParams(seed)
+	for _, v := range params {
+		var cpy big.Int
+		v.ToBigIntRegular(&cpy)
+		res.params = append(res.params, cpy)
+	}
+	res.id = ecc.BW6_672
+	res.h = cs.Constant(0)
+	res.cs = cs
 	return res
 }
 
@@ -232,3 +251,20 @@ func encryptBW633(cs *frontend.ConstraintSystem, h MiMC, message frontend.Variab
 	return res
 
 }
+
+// execution of a mimc run expressed as r1cs
+func encryptBW672(cs *frontend.ConstraintSystem, h MiMC, message frontend.Variable, key frontend.Variable) frontend.Variable {
+
+	res := message
+
+	for i := 0; i < len(h.params); i++ {
+		tmp := cs.Add(res, key, h.params[i])
+		// res = (res+k+c)^5
+		res = cs.Mul(tmp, tmp) // square
+		res = cs.Mul(res, res) // square
+		res = cs.Mul(res, tmp) // mul
+	}
+	res = cs.Add(res, key)
+	return res
+
+}
@@ -42,6 +42,11 @@ func NewMiMC(seed string, id ecc.ID, cs *frontend.ConstraintSystem) (MiMC, error
 	return MiMC{}, errors.New("unknown curve id")
 }
 
+// CurveID returns the curve on whose scalar field h operates
+func (h *MiMC) CurveID() ecc.ID {
+	return h.id
+}
+
 // Write adds more data to the running hash.
 func (h *MiMC) Write(data ...frontend.Variable) {
 	h.data = append(h.data, data...)
@@ -59,6 +59,7 @@ func TestMimcAll(t *testing.T) {
 		ecc.BW6_761:   hash.MIMC_BW6_761,
 		ecc.BLS24_315: hash.MIMC_BLS24_315,
 		ecc.BW6_633:   hash.MIMC_BW6_633,
+		ecc.BW6_672:   hash.MIMC_BW6_672,
 	}
 
 	for curve, hashFunc := range curves {
@@ -87,3 +88,42 @@ func TestMimcAll(t *testing.T) {
 	}
 
 }
+
+// the unexported state of an embedded MiMC is not part of the circuit inputs
+type embeddedMimcCircuit struct {
+	MiMC
+	ExpectedResult frontend.Variable `gnark:"data,public"`
+	Data           frontend.Variable
+}
+
+func (circuit *embeddedMimcCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	var err error
+	circuit.MiMC, err = NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	circuit.Write(circuit.Data)
+	cs.AssertIsEqual(circuit.Sum(), circuit.ExpectedResult)
+	return nil
+}
+
+func TestMimcEmbedded(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	var data big.Int
+	data.SetString("7808462342289447506325013279997289618334122576263655295146895675168642919487", 10)
+
+	var circuit, witness embeddedMimcCircuit
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goMimc := hash.MIMC_BN254.New("seed")
+	goMimc.Write(data.Bytes())
+	b := goMimc.Sum(nil)
+
+	witness.Data.Assign(data)
+	witness.ExpectedResult.Assign(b)
+	assert.SolvingSucceeded(r1cs, &witness)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uints
+
+import "github.com/consensys/gnark/frontend"
+
+// U32 is an unsigned 32 bits integer, represented by its bits in little endian
+type U32 [32]frontend.Variable
+
+// SetVariable sets z to the 32 bits integer v; v is constrained to be in [0, 2^32)
+func (z *U32) SetVariable(cs *frontend.ConstraintSystem, v frontend.Variable) *U32 {
+	setVariable(cs, z[:], v)
+	return z
+}
+
+// SetConstant sets z to c
+func (z *U32) SetConstant(cs *frontend.ConstraintSystem, c uint32) *U32 {
+	setConstant(cs, z[:], uint64(c))
+	return z
+}
+
+// ToVariable returns the value of x as a Variable (no constraint is recorded)
+func (x *U32) ToVariable(cs *frontend.ConstraintSystem) frontend.Variable {
+	return toVariable(cs, x[:])
+}
+
+// Add sets z to x + y + others mod 2^32
+func (z *U32) Add(cs *frontend.ConstraintSystem, x, y *U32, others ...*U32) *U32 {
+	xs := make([][]frontend.Variable, 0, 2+len(others))
+	xs = append(xs, x[:], y[:])
+	for _, o := range others {
+		xs = append(xs, o[:])
+	}
+	add(cs, z[:], xs...)
+	return z
+}
+
+// Xor sets z to x ^ y
+func (z *U32) Xor(cs *frontend.ConstraintSystem, x, y *U32) *U32 {
+	xor(cs, z[:], x[:], y[:])
+	return z
+}
+
+// And sets z to x & y
+func (z *U32) And(cs *frontend.ConstraintSystem, x, y *U32) *U32 {
+	and(cs, z[:], x[:], y[:])
+	return z
+}
+
+// Or sets z to x | y
+func (z *U32) Or(cs *frontend.ConstraintSystem, x, y *U32) *U32 {
+	or(cs, z[:], x[:], y[:])
+	return z
+}
+
+// Not sets z to ^x
+func (z *U32) Not(cs *frontend.ConstraintSystem, x *U32) *U32 {
+	not(cs, z[:], x[:])
+	return z
+}
+
+// Lrot sets z to x rotated left by k bits
+func (z *U32) Lrot(x *U32, k int) *U32 {
+	lrot(z[:], x[:], k)
+	return z
+}
+
+// Rrot sets z to x rotated right by k bits
+func (z *U32) Rrot(x *U32, k int) *U32 {
+	lrot(z[:], x[:], -k)
+	return z
+}
+
+// Lsh sets z to x << k mod 2^32
+func (z *U32) Lsh(cs *frontend.ConstraintSystem, x *U32, k int) *U32 {
+	lsh(cs, z[:], x[:], k)
+	return z
+}
+
+// Rsh sets z to x >> k
+func (z *U32) Rsh(cs *frontend.ConstraintSystem, x *U32, k int) *U32 {
+	lsh(cs, z[:], x[:], -k)
+	return z
+}
+
+// Bytes returns the bytes of x in big endian
+func (x *U32) Bytes() [4]U8 {
+	var res [4]U8
+	for i := range res {
+		copy(res[4-1-i][:], x[8*i:8*i+8])
+	}
+	return res
+}
+
+// BytesLE returns the bytes of x in little endian
+func (x *U32) BytesLE() [4]U8 {
+	var res [4]U8
+	for i := range res {
+		copy(res[i][:], x[8*i:8*i+8])
+	}
+	return res
+}
+
+// SetBytes sets z to the integer with bytes b in big endian
+func (z *U32) SetBytes(b [4]U8) *U32 {
+	for i := range b {
+		copy(z[8*i:8*i+8], b[4-1-i][:])
+	}
+	return z
+}
+
+// SetBytesLE sets z to the integer with bytes b in little endian
+func (z *U32) SetBytesLE(b [4]U8) *U32 {
+	for i := range b {
+		copy(z[8*i:8*i+8], b[i][:])
+	}
+	return z
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uints
+
+import "github.com/consensys/gnark/frontend"
+
+// U8 is an unsigned 8 bits integer, represented by its bits in little endian
+type U8 [8]frontend.Variable
+
+// SetVariable sets z to the 8 bits integer v; v is constrained to be in [0, 2^8)
+func (z *U8) SetVariable(cs *frontend.ConstraintSystem, v frontend.Variable) *U8 {
+	setVariable(cs, z[:], v)
+	return z
+}
+
+// SetConstant sets z to c
+func (z *U8) SetConstant(cs *frontend.ConstraintSystem, c uint8) *U8 {
+	setConstant(cs, z[:], uint64(c))
+	return z
+}
+
+// ToVariable returns the value of x as a Variable (no constraint is recorded)
+func (x *U8) ToVariable(cs *frontend.ConstraintSystem) frontend.Variable {
+	return toVariable(cs, x[:])
+}
+
+// Add sets z to x + y + others mod 2^8
+func (z *U8) Add(cs *frontend.ConstraintSystem, x, y *U8, others ...*U8) *U8 {
+	xs := make([][]frontend.Variable, 0, 2+len(others))
+	xs = append(xs, x[:], y[:])
+	for _, o := range others {
+		xs = append(xs, o[:])
+	}
+	add(cs, z[:], xs...)
+	return z
+}
+
+// Xor sets z to x ^ y
+func (z *U8) Xor(cs *frontend.ConstraintSystem, x, y *U8) *U8 {
+	xor(cs, z[:], x[:], y[:])
+	return z
+}
+
+// And sets z to x & y
+func (z *U8) And(cs *frontend.ConstraintSystem, x, y *U8) *U8 {
+	and(cs, z[:], x[:], y[:])
+	return z
+}
+
+// Or sets z to x | y
+func (z *U8) Or(cs *frontend.ConstraintSystem, x, y *U8) *U8 {
+	or(cs, z[:], x[:], y[:])
+	return z
+}
+
+// Not sets z to ^x
+func (z *U8) Not(cs *frontend.ConstraintSystem, x *U8) *U8 {
+	not(cs, z[:], x[:])
+	return z
+}
+
+// Lrot sets z to x rotated left by k bits
+func (z *U8) Lrot(x *U8, k int) *U8 {
+	lrot(z[:], x[:], k)
+	return z
+}
+
+// Rrot sets z to x rotated right by k bits
+func (z *U8) Rrot(x *U8, k int) *U8 {
+	lrot(z[:], x[:], -k)
+	return z
+}
+
+// Lsh sets z to x << k mod 2^8
+func (z *U8) Lsh(cs *frontend.ConstraintSystem, x *U8, k int) *U8 {
+	lsh(cs, z[:], x[:], k)
+	return z
+}
+
+// Rsh sets z to x >> k
+func (z *U8) Rsh(cs *frontend.ConstraintSystem, x *U8, k int) *U8 {
+	lsh(cs, z[:], x[:], -k)
+	return z
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package uints provides fixed-width unsigned integers (U8, U32, U64) for bit-oriented gadgets,
+// such as SHA, Blake or Keccak.
+//
+// The integers are represented by their bits, in little endian (the bit i has weight 2^i); all the bits
+// are constrained to be boolean when the integer is created (SetVariable, SetConstant), and the operations
+// preserve it. For n bits integers, Xor, And and Or cost n constraints, the addition of k integers
+// about n + log2(k) constraints, and Not, the rotations, the shifts and the conversions from and to bytes are free.
+package uints
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// setVariable sets z to the bits of v, which is constrained to len(z) bits
+func setVariable(cs *frontend.ConstraintSystem, z []frontend.Variable, v frontend.Variable) {
+	copy(z, cs.ToBinary(v, len(z)))
+}
+
+// setConstant sets z to the bits of c
+func setConstant(cs *frontend.ConstraintSystem, z []frontend.Variable, c uint64) {
+	for i := range z {
+		z[i] = cs.Constant((c >> uint(i)) & 1)
+	}
+}
+
+// toVariable returns Σ 2^i ⋅ x[i] (no constraint is recorded)
+func toVariable(cs *frontend.ConstraintSystem, x []frontend.Variable) frontend.Variable {
+	res := cs.Constant(0)
+	var c big.Int
+	c.SetUint64(1)
+	for i := range x {
+		res = cs.Add(res, cs.Mul(x[i], &c))
+		c.Lsh(&c, 1)
+	}
+	return res
+}
+
+// add sets z to Σ xs mod 2^len(z)
+//
+// the sum is decomposed in len(z) bits plus the bits of the carry, which are dropped
+func add(cs *frontend.ConstraintSystem, z []frontend.Variable, xs ...[]frontend.Variable) {
+	sum := cs.Constant(0)
+	for _, x := range xs {
+		sum = cs.Add(sum, toVariable(cs, x))
+	}
+	nbCarryBits := bits.Len(uint(len(xs) - 1))
+	copy(z, cs.ToBinary(sum, len(z)+nbCarryBits))
+}
+
+// xor sets z to x ^ y, with x ^ y = x + y - 2 ⋅ x ⋅ y on bits
+func xor(cs *frontend.ConstraintSystem, z, x, y []frontend.Variable) {
+	for i := range z {
+		xy := cs.Mul(x[i], y[i])
+		z[i] = cs.Sub(cs.Add(x[i], y[i]), cs.Mul(xy, 2))
+	}
+}
+
+// and sets z to x & y, with x & y = x ⋅ y on bits
+func and(cs *frontend.ConstraintSystem, z, x, y []frontend.Variable) {
+	for i := range z {
+		z[i] = cs.Mul(x[i], y[i])
+	}
+}
+
+// or sets z to x | y, with x | y = x + y - x ⋅ y on bits
+func or(cs *frontend.ConstraintSystem, z, x, y []frontend.Variable) {
+	for i := range z {
+		xy := cs.Mul(x[i], y[i])
+		z[i] = cs.Sub(cs.Add(x[i], y[i]), xy)
+	}
+}
+
+// not sets z to ^x, with ^x = 1 - x on bits
+func not(cs *frontend.ConstraintSystem, z, x []frontend.Variable) {
+	for i := range z {
+		z[i] = cs.Sub(1, x[i])
+	}
+}
+
+// lrot sets z to x rotated left by k bits (right if k < 0)
+func lrot(z, x []frontend.Variable, k int) {
+	n := len(z)
+	k = ((k % n) + n) % n
+	tmp := make([]frontend.Variable, n)
+	for i := range tmp {
+		tmp[(i+k)%n] = x[i]
+	}
+	copy(z, tmp)
+}
+
+// lsh sets z to x shifted left by k bits (right if k < 0)
+func lsh(cs *frontend.ConstraintSystem, z, x []frontend.Variable, k int) {
+	n := len(z)
+	tmp := make([]frontend.Variable, n)
+	for i := range tmp {
+		if j := i - k; j >= 0 && j < n {
+			tmp[i] = x[j]
+		} else {
+			tmp[i] = cs.Constant(0)
+		}
+	}
+	copy(z, tmp)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uints
+
+import (
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+)
+
+const k32 = 0x428a2f98
+
+type u32Circuit struct {
+	X, Y, Z                                      frontend.Variable
+	Add, Xor, And, Or, Not, Lrot, Rrot, Lsh, Rsh frontend.Variable `gnark:",public"`
+	Swapped, Chained                             frontend.Variable `gnark:",public"`
+}
+
+func (circuit *u32Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	var x, y, z, k, res U32
+	x.SetVariable(cs, circuit.X)
+	y.SetVariable(cs, circuit.Y)
+	z.SetVariable(cs, circuit.Z)
+	k.SetConstant(cs, k32)
+
+	cs.AssertIsEqual(res.Add(cs, &x, &y, &z, &k).ToVariable(cs), circuit.Add)
+	cs.AssertIsEqual(res.Xor(cs, &x, &y).ToVariable(cs), circuit.Xor)
+	cs.AssertIsEqual(res.And(cs, &x, &y).ToVariable(cs), circuit.And)
+	cs.AssertIsEqual(res.Or(cs, &x, &y).ToVariable(cs), circuit.Or)
+	cs.AssertIsEqual(res.Not(cs, &x).ToVariable(cs), circuit.Not)
+	cs.AssertIsEqual(res.Lrot(&x, 7).ToVariable(cs), circuit.Lrot)
+	cs.AssertIsEqual(res.Rrot(&x, 3).ToVariable(cs), circuit.Rrot)
+	cs.AssertIsEqual(res.Lsh(cs, &x, 5).ToVariable(cs), circuit.Lsh)
+	cs.AssertIsEqual(res.Rsh(cs, &x, 9).ToVariable(cs), circuit.Rsh)
+
+	// x with its bytes in reverse order
+	cs.AssertIsEqual(res.SetBytesLE(x.Bytes()).ToVariable(cs), circuit.Swapped)
+
+	// operations on the results of other operations: ^(x ^ (y >>> 2)) + y
+	res.Rrot(&y, 2)
+	res.Xor(cs, &x, &res)
+	res.Not(cs, &res)
+	res.Add(cs, &res, &y)
+	cs.AssertIsEqual(res.ToVariable(cs), circuit.Chained)
+
+	return nil
+}
+
+func TestU32(t *testing.T) {
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &u32Circuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sparseR1CS, err := frontend.Compile(ecc.BN254, backend.PLONK, &u32Circuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	groth16Assert, plonkAssert := groth16.NewAssert(t), plonk.NewAssert(t)
+
+	newWitness := func(x, y, z uint32) *u32Circuit {
+		var witness u32Circuit
+		witness.X.Assign(x)
+		witness.Y.Assign(y)
+		witness.Z.Assign(z)
+		witness.Add.Assign(x + y + z + k32)
+		witness.Xor.Assign(x ^ y)
+		witness.And.Assign(x & y)
+		witness.Or.Assign(x | y)
+		witness.Not.Assign(^x)
+		witness.Lrot.Assign(bits.RotateLeft32(x, 7))
+		witness.Rrot.Assign(bits.RotateLeft32(x, -3))
+		witness.Lsh.Assign(x << 5)
+		witness.Rsh.Assign(x >> 9)
+		witness.Swapped.Assign(bits.ReverseBytes32(x))
+		witness.Chained.Assign(^(x ^ bits.RotateLeft32(y, -2)) + y)
+		return &witness
+	}
+
+	rng := rand.New(rand.NewSource(42))
+	values := [][3]uint32{
+		{0, 0, 0},
+		{0xffffffff, 0xffffffff, 0xffffffff},
+		{rng.Uint32(), rng.Uint32(), rng.Uint32()},
+		{rng.Uint32(), rng.Uint32(), rng.Uint32()},
+	}
+	for _, v := range values {
+		witness := newWitness(v[0], v[1], v[2])
+		groth16Assert.SolvingSucceeded(r1cs, witness)
+		plonkAssert.SolvingSucceeded(sparseR1CS, witness)
+	}
+
+	// wrong result
+	bad := newWitness(1, 2, 3)
+	bad.Add = frontend.Variable{}
+	bad.Add.Assign(7)
+	groth16Assert.SolvingFailed(r1cs, bad)
+	plonkAssert.SolvingFailed(sparseR1CS, bad)
+
+	// inputs out of range
+	bad = newWitness(1, 2, 3)
+	bad.X = frontend.Variable{}
+	bad.X.Assign(uint64(1) << 32)
+	groth16Assert.SolvingFailed(r1cs, bad)
+	plonkAssert.SolvingFailed(sparseR1CS, bad)
+}
+
+type u64Circuit struct {
+	X, Y     frontend.Variable
+	Add, Xor frontend.Variable    `gnark:",public"`
+	Bytes    [8]frontend.Variable `gnark:",public"`
+}
+
+func (circuit *u64Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	var x, y, res U64
+	x.SetVariable(cs, circuit.X)
+	y.SetVariable(cs, circuit.Y)
+
+	cs.AssertIsEqual(res.Add(cs, &x, &y).ToVariable(cs), circuit.Add)
+
+	// xor computed byte per byte
+	xBytes, yBytes := x.BytesLE(), y.BytesLE()
+	var xorBytes [8]U8
+	for i := range xorBytes {
+		xorBytes[i].Xor(cs, &xBytes[i], &yBytes[i])
+	}
+	cs.AssertIsEqual(res.SetBytesLE(xorBytes).ToVariable(cs), circuit.Xor)
+
+	bytes := x.Bytes()
+	for i := range bytes {
+		cs.AssertIsEqual(bytes[i].ToVariable(cs), circuit.Bytes[i])
+	}
+	return nil
+}
+
+func TestU64(t *testing.T) {
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &u64Circuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert := groth16.NewAssert(t)
+
+	x, y := uint64(0xfedcba9876543210), uint64(0x8000000000000001)
+	var witness u64Circuit
+	witness.X.Assign(x)
+	witness.Y.Assign(y)
+	witness.Add.Assign(x + y)
+	witness.Xor.Assign(x ^ y)
+	for i := range witness.Bytes {
+		witness.Bytes[i].Assign(uint8(x >> (56 - 8*uint(i))))
+	}
+	assert.SolvingSucceeded(r1cs, &witness)
+
+	witness.Add = frontend.Variable{}
+	witness.Add.Assign(x + y + 1)
+	assert.SolvingFailed(r1cs, &witness)
+}
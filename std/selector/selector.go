@@ -0,0 +1,195 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package selector provides gadgets to select values of a slice by a variable index or key.
+//
+// The costs given in the documentation are in rank-1 constraints; linear combinations are free
+// in R1CS, and cost one constraint per addition in SparseR1CS (PLONK).
+package selector
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Mux returns inputs[sel]
+//
+// sel is decomposed in binary to select the input with a tree of cs.Select (len(inputs) - 1 constraints);
+// sel must be in [0, len(inputs)): it is constrained as such.
+func Mux(cs *frontend.ConstraintSystem, sel frontend.Variable, inputs ...frontend.Variable) frontend.Variable {
+	n := len(inputs)
+	if n == 0 {
+		panic("Mux: no inputs")
+	}
+	if n == 1 {
+		cs.AssertIsEqual(sel, 0)
+		return inputs[0]
+	}
+
+	nbBits := bits.Len(uint(n - 1))
+	selBits := cs.ToBinary(sel, nbBits)
+	if n != 1<<nbBits {
+		// ToBinary ensures sel < 2^nbBits only
+		cs.AssertIsEqual(cs.IsLessOrEqual(sel, n-1, nbBits), 1)
+	}
+
+	// at each level, the bit i of sel selects between the inputs of consecutive pairs; as sel < n,
+	// the last input of an odd level is selected only if the bit is 0, and is carried to the next level
+	level := inputs
+	for _, b := range selBits {
+		next := make([]frontend.Variable, (len(level)+1)/2)
+		for j := range next {
+			if 2*j+1 < len(level) {
+				next[j] = cs.Select(b, level[2*j+1], level[2*j])
+			} else {
+				next[j] = level[2*j]
+			}
+		}
+		level = next
+	}
+
+	return level[0]
+}
+
+// Lookup2 returns i0, i1, i2 or i3 for b0 + 2*b1 = 0, 1, 2 or 3
+//
+// b0 and b1 must be boolean: they are constrained as such. If i0, i1, i2 and i3 are constants,
+// the lookup costs a single constraint; otherwise it uses 3 cs.Select.
+func Lookup2(cs *frontend.ConstraintSystem, b0, b1 frontend.Variable, i0, i1, i2, i3 interface{}) frontend.Variable {
+	if isConstant(i0) && isConstant(i1) && isConstant(i2) && isConstant(i3) {
+		cs.AssertIsBoolean(b0)
+		cs.AssertIsBoolean(b1)
+
+		c0, c1, c2, c3 := frontend.FromInterface(i0), frontend.FromInterface(i1), frontend.FromInterface(i2), frontend.FromInterface(i3)
+
+		// res = c0 + b0 ⋅ (c1 - c0) + b1 ⋅ (c2 - c0) + b0 ⋅ b1 ⋅ (c3 - c2 - c1 + c0)
+		var d1, d2, d3 big.Int
+		d1.Sub(&c1, &c0)
+		d2.Sub(&c2, &c0)
+		d3.Sub(&c3, &c2).Sub(&d3, &c1).Add(&d3, &c0)
+
+		b0b1 := cs.Mul(b0, b1)
+		return cs.Add(&c0, cs.Mul(b0, &d1), cs.Mul(b1, &d2), cs.Mul(b0b1, &d3)) // no constraint is recorded
+	}
+
+	tmp0 := cs.Select(b0, i1, i0)
+	tmp1 := cs.Select(b0, i3, i2)
+	return cs.Select(b1, tmp1, tmp0)
+}
+
+// Map returns the value associated to queryKey, values[i] such that keys[i] == queryKey
+//
+// the keys must be distinct, and queryKey must be one of them: it is constrained as such.
+// The lookup costs 4 constraints per key.
+func Map(cs *frontend.ConstraintSystem, queryKey frontend.Variable, keys, values []frontend.Variable) frontend.Variable {
+	if len(keys) != len(values) {
+		panic("Map: the number of keys and values differ")
+	}
+	if len(keys) == 0 {
+		panic("Map: no keys")
+	}
+
+	indicators := make([]interface{}, len(keys))
+	products := make([]interface{}, len(keys))
+	for i := range keys {
+		indicators[i] = isEqual(cs, queryKey, keys[i])
+		products[i] = cs.Mul(indicators[i], values[i])
+	}
+	cs.AssertIsEqual(sum(cs, indicators), 1)
+
+	return sum(cs, products)
+}
+
+// Slice returns a copy of input in which the elements out of [start, end) are set to 0
+//
+// start and end must satisfy 0 <= start <= end <= len(input): they are constrained as such (if input
+// is empty, start and end must be 0). The slice costs about 7 constraints per element.
+func Slice(cs *frontend.ConstraintSystem, start, end frontend.Variable, input []frontend.Variable) []frontend.Variable {
+	if len(input) == 0 {
+		cs.AssertIsEqual(start, 0)
+		cs.AssertIsEqual(end, 0)
+		return nil
+	}
+
+	nbBits := bits.Len(uint(len(input)))
+	cs.AssertIsEqual(cs.IsLessOrEqual(start, end, nbBits), 1)
+
+	startMask := stepMask(cs, start, len(input))
+	endMask := stepMask(cs, end, len(input))
+
+	res := make([]frontend.Variable, len(input))
+	for i := range input {
+		res[i] = cs.Mul(input[i], cs.Sub(startMask[i], endMask[i]))
+	}
+	return res
+}
+
+// PrefixMask returns the n values mask[i] = 1 if i < length, 0 otherwise
+//
+// length must be in [0, n]: it is constrained as such. The mask costs about 3 constraints per element.
+func PrefixMask(cs *frontend.ConstraintSystem, length frontend.Variable, n int) []frontend.Variable {
+	mask := stepMask(cs, length, n)
+	for i := range mask {
+		mask[i] = cs.Sub(1, mask[i]) // no constraint is recorded
+	}
+	return mask
+}
+
+// stepMask returns the n values mask[i] = 1 if i >= pos, 0 otherwise
+//
+// pos must be in [0, n]: it is constrained as such.
+func stepMask(cs *frontend.ConstraintSystem, pos frontend.Variable, n int) []frontend.Variable {
+	// indicators of pos == i, for i in [0, n]; exactly one of them is set
+	indicators := make([]interface{}, n+1)
+	for i := range indicators {
+		indicators[i] = isEqual(cs, pos, i)
+	}
+	cs.AssertIsEqual(sum(cs, indicators), 1)
+
+	// mask[i] = Σ_{j <= i} (pos == j)
+	mask := make([]frontend.Variable, n)
+	acc := cs.Constant(0)
+	for i := range mask {
+		acc = cs.Add(acc, indicators[i]) // no constraint is recorded
+		mask[i] = acc
+	}
+	return mask
+}
+
+// isEqual returns 1 if a == b, 0 otherwise, in 3 constraints
+//
+// with x = a - b, inv = x / x² is the inverse of x if x != 0. If x == 0, the constraint x² ⋅ inv = x holds
+// for any inv, which is then not computed by the solver, and 1 - x ⋅ inv = 1.
+func isEqual(cs *frontend.ConstraintSystem, a, b interface{}) frontend.Variable {
+	x := cs.Sub(a, b) // no constraint is recorded
+	inv := cs.Div(x, cs.Mul(x, x))
+	return cs.Sub(1, cs.Mul(x, inv))
+}
+
+// sum returns the sum of the terms (no constraint is recorded)
+func sum(cs *frontend.ConstraintSystem, terms []interface{}) frontend.Variable {
+	if len(terms) == 1 {
+		return cs.Add(terms[0], 0)
+	}
+	return cs.Add(terms[0], terms[1], terms[2:]...)
+}
+
+func isConstant(v interface{}) bool {
+	_, ok := v.(frontend.Variable)
+	return !ok
+}
@@ -0,0 +1,220 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+)
+
+// assertSolving checks the good and bad witnesses against the circuit compiled for groth16 and plonk
+func assertSolving(t *testing.T, circuit frontend.Circuit, good, bad []frontend.Circuit) {
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sparseR1CS, err := frontend.Compile(ecc.BN254, backend.PLONK, circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groth16Assert, plonkAssert := groth16.NewAssert(t), plonk.NewAssert(t)
+	for _, witness := range good {
+		groth16Assert.SolvingSucceeded(r1cs, witness)
+		plonkAssert.SolvingSucceeded(sparseR1CS, witness)
+	}
+	for _, witness := range bad {
+		groth16Assert.SolvingFailed(r1cs, witness)
+		plonkAssert.SolvingFailed(sparseR1CS, witness)
+	}
+}
+
+type muxCircuit struct {
+	Sel frontend.Variable
+	In  [5]frontend.Variable
+	Out frontend.Variable `gnark:",public"`
+}
+
+func (circuit *muxCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(Mux(cs, circuit.Sel, circuit.In[:]...), circuit.Out)
+	return nil
+}
+
+func TestMux(t *testing.T) {
+	newWitness := func(sel, out int) frontend.Circuit {
+		var witness muxCircuit
+		witness.Sel.Assign(sel)
+		for i := range witness.In {
+			witness.In[i].Assign(10 + i)
+		}
+		witness.Out.Assign(out)
+		return &witness
+	}
+
+	var good, bad []frontend.Circuit
+	for sel := 0; sel < 5; sel++ {
+		good = append(good, newWitness(sel, 10+sel))
+		bad = append(bad, newWitness(sel, 9+sel))
+	}
+	// sel out of range
+	bad = append(bad, newWitness(5, 14), newWitness(7, 14))
+
+	assertSolving(t, &muxCircuit{}, good, bad)
+}
+
+type lookup2Circuit struct {
+	B0, B1           frontend.Variable
+	In               [4]frontend.Variable
+	OutConst, OutVar frontend.Variable `gnark:",public"`
+}
+
+func (circuit *lookup2Circuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(Lookup2(cs, circuit.B0, circuit.B1, 10, 11, 12, 13), circuit.OutConst)
+	cs.AssertIsEqual(Lookup2(cs, circuit.B0, circuit.B1, circuit.In[0], circuit.In[1], circuit.In[2], circuit.In[3]), circuit.OutVar)
+	return nil
+}
+
+func TestLookup2(t *testing.T) {
+	newWitness := func(b0, b1, out int) frontend.Circuit {
+		var witness lookup2Circuit
+		witness.B0.Assign(b0)
+		witness.B1.Assign(b1)
+		for i := range witness.In {
+			witness.In[i].Assign(10 + i)
+		}
+		witness.OutConst.Assign(out)
+		witness.OutVar.Assign(out)
+		return &witness
+	}
+
+	var good, bad []frontend.Circuit
+	for i := 0; i < 4; i++ {
+		good = append(good, newWitness(i&1, i>>1, 10+i))
+		bad = append(bad, newWitness(i&1, i>>1, 11+i))
+	}
+	// non boolean selectors
+	bad = append(bad, newWitness(2, 0, 12))
+
+	assertSolving(t, &lookup2Circuit{}, good, bad)
+}
+
+type mapCircuit struct {
+	Key          frontend.Variable
+	Keys, Values [4]frontend.Variable
+	Out          frontend.Variable `gnark:",public"`
+}
+
+func (circuit *mapCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(Map(cs, circuit.Key, circuit.Keys[:], circuit.Values[:]), circuit.Out)
+	return nil
+}
+
+func TestMap(t *testing.T) {
+	keys := []int{7, 11, 0, 3}
+	newWitness := func(key, out int) frontend.Circuit {
+		var witness mapCircuit
+		witness.Key.Assign(key)
+		for i := range witness.Keys {
+			witness.Keys[i].Assign(keys[i])
+			witness.Values[i].Assign(100 + i)
+		}
+		witness.Out.Assign(out)
+		return &witness
+	}
+
+	var good, bad []frontend.Circuit
+	for i, key := range keys {
+		good = append(good, newWitness(key, 100+i))
+		bad = append(bad, newWitness(key, 101+i))
+	}
+	// unknown key
+	bad = append(bad, newWitness(5, 0), newWitness(5, 100))
+
+	assertSolving(t, &mapCircuit{}, good, bad)
+}
+
+type sliceCircuit struct {
+	Start, End frontend.Variable
+	In         [4]frontend.Variable
+	Out        [4]frontend.Variable `gnark:",public"`
+}
+
+func (circuit *sliceCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	out := Slice(cs, circuit.Start, circuit.End, circuit.In[:])
+	for i := range out {
+		cs.AssertIsEqual(out[i], circuit.Out[i])
+	}
+	return nil
+}
+
+func TestSlice(t *testing.T) {
+	newWitness := func(start, end int, out [4]int) frontend.Circuit {
+		var witness sliceCircuit
+		witness.Start.Assign(start)
+		witness.End.Assign(end)
+		for i := range witness.In {
+			witness.In[i].Assign(10 + i)
+			witness.Out[i].Assign(out[i])
+		}
+		return &witness
+	}
+
+	good := []frontend.Circuit{
+		newWitness(0, 4, [4]int{10, 11, 12, 13}),
+		newWitness(1, 3, [4]int{0, 11, 12, 0}),
+		newWitness(2, 2, [4]int{0, 0, 0, 0}),
+		newWitness(3, 4, [4]int{0, 0, 0, 13}),
+		newWitness(4, 4, [4]int{0, 0, 0, 0}),
+	}
+	bad := []frontend.Circuit{
+		newWitness(1, 3, [4]int{10, 11, 12, 0}),
+		newWitness(3, 1, [4]int{0, 0, 0, 0}),     // start > end
+		newWitness(0, 5, [4]int{10, 11, 12, 13}), // end out of range
+	}
+
+	assertSolving(t, &sliceCircuit{}, good, bad)
+}
+
+type emptySliceCircuit struct {
+	Start, End frontend.Variable
+}
+
+func (circuit *emptySliceCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	if out := Slice(cs, circuit.Start, circuit.End, nil); len(out) != 0 {
+		return errors.New("the slice of an empty input should be empty")
+	}
+	return nil
+}
+
+func TestSliceEmpty(t *testing.T) {
+	newWitness := func(start, end int) frontend.Circuit {
+		var witness emptySliceCircuit
+		witness.Start.Assign(start)
+		witness.End.Assign(end)
+		return &witness
+	}
+
+	good := []frontend.Circuit{newWitness(0, 0)}
+	bad := []frontend.Circuit{newWitness(0, 1), newWitness(1, 1)}
+
+	assertSolving(t, &emptySliceCircuit{}, good, bad)
+}
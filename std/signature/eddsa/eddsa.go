@@ -18,10 +18,19 @@ limitations under the License.
 package eddsa
 
 import (
+	"errors"
+
 	"github.com/consensys/gnark-crypto/ecc"
+	edbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	edbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	edbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	edbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/twistededwards"
+	edbw6672 "github.com/consensys/gnark-crypto/ecc/bw6-672/twistededwards"
+	edbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/twistededwards"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/twistededwards"
-	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash"
 )
 
 // PublicKey stores an eddsa public key (to be used in gnark circuit)
@@ -32,67 +41,39 @@ type PublicKey struct {
 
 // Signature stores a signature  (to be used in gnark circuit)
 // An EdDSA signature is a tuple (R,S) where R is a point on the twisted Edwards curve
-// and S a scalar. S can be greater than r, the size of the zk snark field, and must
-// not be reduced modulo r. Therefore it is split in S1 and S2, such that if r is n-bits long,
-// S = 2^(n/2)*S1 + S2. In other words, S is written S1S2 in basis 2^(n/2).
+// and S a scalar. S is smaller than the order of the subgroup of the twisted Edwards curve
+// (Verify constrains it as such, so that the signatures are not malleable), which is smaller
+// than r, the size of the zk snark field: it fits in a single variable.
 type Signature struct {
-	R      twistededwards.Point
-	S1, S2 frontend.Variable
+	R twistededwards.Point
+	S frontend.Variable
 }
 
-// Verify verifies an eddsa signature
+// Verify verifies an eddsa signature of the message msg, made of one or several field elements
 // cf https://en.wikipedia.org/wiki/EdDSA
-func Verify(cs *frontend.ConstraintSystem, sig Signature, msg frontend.Variable, pubKey PublicKey) error {
+//
+// h is the hash used by the signer to compute H(R, A, M): it is reset, then R.X, R.Y, A.X, A.Y and
+// the elements of msg are written to it. With MiMC, this matches the signatures of gnark-crypto
+// on the concatenation of the elements of msg. R and A are constrained to be on the curve, and S
+// to be smaller than the order of the subgroup.
+func Verify(cs *frontend.ConstraintSystem, h hash.Hash, sig Signature, pubKey PublicKey, msg ...frontend.Variable) error {
 
-	// compute H(R, A, M), all parameters in data are in Montgomery form
-	data := []frontend.Variable{
-		sig.R.X,
-		sig.R.Y,
-		pubKey.A.X,
-		pubKey.A.Y,
-		msg,
-	}
+	sig.R.MustBeOnCurve(cs, pubKey.Curve)
+	pubKey.A.MustBeOnCurve(cs, pubKey.Curve)
+	twistededwards.MustBeScalar(cs, sig.S, pubKey.Curve)
 
-	hash, err := mimc.NewMiMC("seed", pubKey.Curve.ID, cs)
-	if err != nil {
-		return err
-	}
-	hash.Write(data...)
-	//hramConstant := hash.Sum(data...)
-	hramConstant := hash.Sum()
+	// compute H(R, A, M), all parameters in data are in Montgomery form
+	h.Reset()
+	h.Write(sig.R.X, sig.R.Y, pubKey.A.X, pubKey.A.Y)
+	h.Write(msg...)
+	hramConstant := h.Sum()
 
 	// lhs = cofactor*SB
 	cofactorConstant := cs.Constant(pubKey.Curve.Cofactor)
 	lhs := twistededwards.Point{}
 
-	var basis frontend.Variable
-	switch pubKey.Curve.ID {
-	case ecc.BN254:
-		basis = cs.Constant("340282366920938463463374607431768211456") // 2**128
-	case ecc.BLS12_381:
-		basis = cs.Constant("340282366920938463463374607431768211456")
-	case ecc.BLS12_377:
-		basis = cs.Constant("340282366920938463463374607431768211456")
-	case ecc.BW6_761:
-		basis = cs.Constant("6277101735386680763835789423207666416102355444464034512896") // 2**192
-	case ecc.BLS24_315:
-		basis = cs.Constant("340282366920938463463374607431768211456")
-	case ecc.BW6_633:
-		basis = cs.Constant("1461501637330902918203684832716283019655932542976") // 2**160
-	default:
-		panic("curve is not supported")
-	}
-
-	// [cofactor*(2^basis*S1 +  S2)]G
-	lhs.ScalarMulFixedBase(cs, pubKey.Curve.BaseX, pubKey.Curve.BaseY, sig.S1, pubKey.Curve).
-		ScalarMulNonFixedBase(cs, &lhs, basis, pubKey.Curve)
-
-	tmp := twistededwards.Point{}
-	tmp.ScalarMulFixedBase(cs, pubKey.Curve.BaseX, pubKey.Curve.BaseY, sig.S2, pubKey.Curve)
-
-	lhs.AddGeneric(cs, &lhs, &tmp, pubKey.Curve)
-
-	lhs.ScalarMulNonFixedBase(cs, &lhs, cofactorConstant, pubKey.Curve)
+	lhs.ScalarMulFixedBase(cs, pubKey.Curve.BaseX, pubKey.Curve.BaseY, sig.S, pubKey.Curve).
+		ScalarMulNonFixedBase(cs, &lhs, cofactorConstant, pubKey.Curve)
 
 	lhs.MustBeOnCurve(cs, pubKey.Curve)
 
@@ -108,3 +89,85 @@ func Verify(cs *frontend.ConstraintSystem, sig Signature, msg frontend.Variable,
 
 	return nil
 }
+
+// Assign assigns the public key from its binary representation in gnark-crypto
+// (a compressed point of the twisted Edwards curve of curveID)
+//
+// an error is returned if the curve is not supported or if buf doesn't start with a valid point;
+// the public key is not modified in that case.
+func (pubKey *PublicKey) Assign(curveID ecc.ID, buf []byte) error {
+	x, y, _, err := parsePoint(curveID, buf)
+	if err != nil {
+		return err
+	}
+	pubKey.A.X.Assign(x)
+	pubKey.A.Y.Assign(y)
+	return nil
+}
+
+// Assign assigns the signature from its binary representation in gnark-crypto
+// (the compressed point R of the twisted Edwards curve of curveID, followed by S)
+//
+// an error is returned if the curve is not supported, if buf doesn't start with a valid point
+// or if S is missing; the signature is not modified in that case.
+func (sig *Signature) Assign(curveID ecc.ID, buf []byte) error {
+	x, y, n, err := parsePoint(curveID, buf)
+	if err != nil {
+		return err
+	}
+	if len(buf) <= n {
+		return errors.New("eddsa: signature too short, S is missing")
+	}
+	sig.R.X.Assign(x)
+	sig.R.Y.Assign(y)
+	sig.S.Assign(buf[n:])
+	return nil
+}
+
+// parsePoint decompresses the point of the twisted Edwards curve of curveID at the beginning of buf,
+// and returns its coordinates and the number of bytes read
+func parsePoint(curveID ecc.ID, buf []byte) (x, y []byte, n int, err error) {
+	switch curveID {
+	case ecc.BN254:
+		var p edbn254.PointAffine
+		n, err = p.SetBytes(buf)
+		bx, by := p.X.Bytes(), p.Y.Bytes()
+		x, y = bx[:], by[:]
+	case ecc.BLS12_381:
+		var p edbls12381.PointAffine
+		n, err = p.SetBytes(buf)
+		bx, by := p.X.Bytes(), p.Y.Bytes()
+		x, y = bx[:], by[:]
+	case ecc.BLS12_377:
+		var p edbls12377.PointAffine
+		n, err = p.SetBytes(buf)
+		bx, by := p.X.Bytes(), p.Y.Bytes()
+		x, y = bx[:], by[:]
+	case ecc.BW6_761:
+		var p edbw6761.PointAffine
+		n, err = p.SetBytes(buf)
+		bx, by := p.X.Bytes(), p.Y.Bytes()
+		x, y = bx[:], by[:]
+	case ecc.BLS24_315:
+		var p edbls24315.PointAffine
+		n, err = p.SetBytes(buf)
+		bx, by := p.X.Bytes(), p.Y.Bytes()
+		x, y = bx[:], by[:]
+	case ecc.BW6_633:
+		var p edbw6633.PointAffine
+		n, err = p.SetBytes(buf)
+		bx, by := p.X.Bytes(), p.Y.Bytes()
+		x, y = bx[:], by[:]
+	case ecc.BW6_672:
+		var p edbw6672.PointAffine
+		n, err = p.SetBytes(buf)
+		bx, by := p.X.Bytes(), p.Y.Bytes()
+		x, y = bx[:], by[:]
+	default:
+		return nil, nil, 0, errors.New("eddsa: curve is not supported")
+	}
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return
+}
@@ -22,17 +22,13 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
-	edwardsbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
 	eddsabls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards/eddsa"
-	edwardsbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
 	eddsabls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards/eddsa"
-	edwardsbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
 	eddsabls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards/eddsa"
-	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	eddsabn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
-	edwardsbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/twistededwards"
 	eddsabw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/twistededwards/eddsa"
-	edwardsbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/twistededwards"
+	eddsabw6672 "github.com/consensys/gnark-crypto/ecc/bw6-672/twistededwards/eddsa"
 	eddsabw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/twistededwards/eddsa"
 	"github.com/consensys/gnark-crypto/hash"
 	"github.com/consensys/gnark-crypto/signature"
@@ -40,6 +36,7 @@ import (
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
 )
 
 type eddsaCircuit struct {
@@ -48,100 +45,6 @@ type eddsaCircuit struct {
 	Message   frontend.Variable `gnark:",public"`
 }
 
-func parseSignature(id ecc.ID, buf []byte) ([]byte, []byte, []byte, []byte) {
-
-	var pointbn254 edwardsbn254.PointAffine
-	var pointbls12381 edwardsbls12381.PointAffine
-	var pointbls12377 edwardsbls12377.PointAffine
-	var pointbw6761 edwardsbw6761.PointAffine
-	var pointbls24315 edwardsbls24315.PointAffine
-	var pointbw6633 edwardsbw6633.PointAffine
-
-	switch id {
-	case ecc.BN254:
-		pointbn254.SetBytes(buf[:32])
-		a, b := parsePoint(id, buf)
-		s1 := buf[32:48] // r is 256 bits, so s = 2^128*s1 + s2
-		s2 := buf[48:]
-		return a[:], b[:], s1, s2
-	case ecc.BLS12_381:
-		pointbls12381.SetBytes(buf[:32])
-		a, b := parsePoint(id, buf)
-		s1 := buf[32:48]
-		s2 := buf[48:]
-		return a[:], b[:], s1, s2
-	case ecc.BLS12_377:
-		pointbls12377.SetBytes(buf[:32])
-		a, b := parsePoint(id, buf)
-		s1 := buf[32:48]
-		s2 := buf[48:]
-		return a[:], b[:], s1, s2
-	case ecc.BW6_761:
-		pointbw6761.SetBytes(buf[:48]) // r is 384 bits, so s = 2^192*s1 + s2
-		a, b := parsePoint(id, buf)
-		s1 := buf[48:72]
-		s2 := buf[72:]
-		return a[:], b[:], s1, s2
-	case ecc.BLS24_315:
-		pointbls24315.SetBytes(buf[:32])
-		a, b := parsePoint(id, buf)
-		s1 := buf[32:48]
-		s2 := buf[48:]
-		return a[:], b[:], s1, s2
-	case ecc.BW6_633:
-		pointbw6633.SetBytes(buf[:40]) // r is 320 bits, so s = 2^160*s1 + s2
-		a, b := parsePoint(id, buf)
-		s1 := buf[40:60]
-		s2 := buf[60:]
-		return a[:], b[:], s1, s2
-	default:
-		return buf, buf, buf, buf
-	}
-}
-
-func parsePoint(id ecc.ID, buf []byte) ([]byte, []byte) {
-	var pointbn254 edwardsbn254.PointAffine
-	var pointbls12381 edwardsbls12381.PointAffine
-	var pointbls12377 edwardsbls12377.PointAffine
-	var pointbw6761 edwardsbw6761.PointAffine
-	var pointbls24315 edwardsbls24315.PointAffine
-	var pointbw6633 edwardsbw6633.PointAffine
-	switch id {
-	case ecc.BN254:
-		pointbn254.SetBytes(buf[:32])
-		a := pointbn254.X.Bytes()
-		b := pointbn254.Y.Bytes()
-		return a[:], b[:]
-	case ecc.BLS12_381:
-		pointbls12381.SetBytes(buf[:32])
-		a := pointbls12381.X.Bytes()
-		b := pointbls12381.Y.Bytes()
-		return a[:], b[:]
-	case ecc.BLS12_377:
-		pointbls12377.SetBytes(buf[:32])
-		a := pointbls12377.X.Bytes()
-		b := pointbls12377.Y.Bytes()
-		return a[:], b[:]
-	case ecc.BW6_761:
-		pointbw6761.SetBytes(buf[:48])
-		a := pointbw6761.X.Bytes()
-		b := pointbw6761.Y.Bytes()
-		return a[:], b[:]
-	case ecc.BLS24_315:
-		pointbls24315.SetBytes(buf[:32])
-		a := pointbls24315.X.Bytes()
-		b := pointbls24315.Y.Bytes()
-		return a[:], b[:]
-	case ecc.BW6_633:
-		pointbw6633.SetBytes(buf[:40])
-		a := pointbw6633.X.Bytes()
-		b := pointbw6633.Y.Bytes()
-		return a[:], b[:]
-	default:
-		return buf, buf
-	}
-}
-
 func (circuit *eddsaCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
 
 	params, err := twistededwards.NewEdCurve(curveID)
@@ -150,10 +53,13 @@ func (circuit *eddsaCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSyste
 	}
 	circuit.PublicKey.Curve = params
 
-	// verify the signature in the cs
-	Verify(cs, circuit.Signature, circuit.Message, circuit.PublicKey)
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	// verify the signature in the cs
+	return Verify(cs, &h, circuit.Signature, circuit.PublicKey, circuit.Message)
 }
 
 func TestEddsa(t *testing.T) {
@@ -171,6 +77,7 @@ func TestEddsa(t *testing.T) {
 	signature.Register(signature.EDDSA_BW6_761, eddsabw6761.GenerateKeyInterfaces)
 	signature.Register(signature.EDDSA_BLS24_315, eddsabls24315.GenerateKeyInterfaces)
 	signature.Register(signature.EDDSA_BW6_633, eddsabw6633.GenerateKeyInterfaces)
+	signature.Register(signature.EDDSA_BW6_672, eddsabw6672.GenerateKeyInterfaces)
 
 	confs := map[ecc.ID]confSig{
 		ecc.BN254:     {hash.MIMC_BN254, signature.EDDSA_BN254},
@@ -179,6 +86,7 @@ func TestEddsa(t *testing.T) {
 		ecc.BW6_761:   {hash.MIMC_BW6_761, signature.EDDSA_BW6_761},
 		ecc.BLS24_315: {hash.MIMC_BLS24_315, signature.EDDSA_BLS24_315},
 		ecc.BW6_633:   {hash.MIMC_BW6_633, signature.EDDSA_BW6_633},
+		ecc.BW6_672:   {hash.MIMC_BW6_672, signature.EDDSA_BW6_672},
 	}
 	for id, conf := range confs {
 
@@ -224,18 +132,12 @@ func TestEddsa(t *testing.T) {
 			var witness eddsaCircuit
 			witness.Message.Assign(frMsg)
 
-			pubkeyAx, pubkeyAy := parsePoint(id, pubKey.Bytes())
-			var pbAx, pbAy big.Int
-			pbAx.SetBytes(pubkeyAx)
-			pbAy.SetBytes(pubkeyAy)
-			witness.PublicKey.A.X.Assign(pubkeyAx)
-			witness.PublicKey.A.Y.Assign(pubkeyAy)
-
-			sigRx, sigRy, sigS1, sigS2 := parseSignature(id, signature)
-			witness.Signature.R.X.Assign(sigRx)
-			witness.Signature.R.Y.Assign(sigRy)
-			witness.Signature.S1.Assign(sigS1)
-			witness.Signature.S2.Assign(sigS2)
+			if err := witness.PublicKey.Assign(id, pubKey.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+			if err := witness.Signature.Assign(id, signature); err != nil {
+				t.Fatal(err)
+			}
 
 			assert.SolvingSucceeded(r1cs, &witness)
 		}
@@ -245,18 +147,182 @@ func TestEddsa(t *testing.T) {
 			var witness eddsaCircuit
 			witness.Message.Assign("44717650746155748460101257525078853138837311576962212923649547644148297035979")
 
-			pubkeyAx, pubkeyAy := parsePoint(id, pubKey.Bytes())
-			witness.PublicKey.A.X.Assign(pubkeyAx)
-			witness.PublicKey.A.Y.Assign(pubkeyAy)
-
-			sigRx, sigRy, sigS1, sigS2 := parseSignature(id, signature)
-			witness.Signature.R.X.Assign(sigRx)
-			witness.Signature.R.Y.Assign(sigRy)
-			witness.Signature.S1.Assign(sigS1)
-			witness.Signature.S2.Assign(sigS2)
+			if err := witness.PublicKey.Assign(id, pubKey.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+			if err := witness.Signature.Assign(id, signature); err != nil {
+				t.Fatal(err)
+			}
 
 			assert.SolvingFailed(r1cs, &witness)
 		}
 
 	}
 }
+
+type eddsaMultiCircuit struct {
+	PublicKey PublicKey
+	Signature Signature
+	Message   [3]frontend.Variable `gnark:",public"`
+}
+
+func (circuit *eddsaMultiCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+
+	params, err := twistededwards.NewEdCurve(curveID)
+	if err != nil {
+		return err
+	}
+	circuit.PublicKey.Curve = params
+
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+
+	return Verify(cs, &h, circuit.Signature, circuit.PublicKey, circuit.Message[:]...)
+}
+
+func TestEddsaMultiElementMessage(t *testing.T) {
+
+	assert := groth16.NewAssert(t)
+
+	privKey, err := eddsabn254.GenerateKey(rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privKey.Public()
+
+	// the message is the concatenation of the field elements
+	var msg []byte
+	for i := 0; i < 3; i++ {
+		var e fr.Element
+		e.SetUint64(uint64(42 + i))
+		b := e.Bytes()
+		msg = append(msg, b[:]...)
+	}
+	signature, err := privKey.Sign(msg, hash.MIMC_BN254.New("seed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var circuit eddsaMultiCircuit
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newWitness := func() *eddsaMultiCircuit {
+		var witness eddsaMultiCircuit
+		if err := witness.PublicKey.Assign(ecc.BN254, pubKey.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if err := witness.Signature.Assign(ecc.BN254, signature); err != nil {
+			t.Fatal(err)
+		}
+		for i := range witness.Message {
+			witness.Message[i].Assign(msg[32*i : 32*(i+1)])
+		}
+		return &witness
+	}
+
+	assert.SolvingSucceeded(r1cs, newWitness())
+
+	// the elements of the message must be in order
+	witness := newWitness()
+	witness.Message[0], witness.Message[1] = witness.Message[1], witness.Message[0]
+	assert.SolvingFailed(r1cs, witness)
+}
+
+func TestEddsaAssignInvalid(t *testing.T) {
+	privKey, err := eddsabn254.GenerateKey(rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privKey.Public().Bytes()
+	signature, err := privKey.Sign([]byte{42}, hash.MIMC_BN254.New("seed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p PublicKey
+	var sig Signature
+
+	// unknown curve
+	if err := p.Assign(ecc.UNKNOWN, pubKey); err == nil {
+		t.Fatal("assigning a public key of an unknown curve should fail")
+	}
+	if err := sig.Assign(ecc.UNKNOWN, signature); err == nil {
+		t.Fatal("assigning a signature of an unknown curve should fail")
+	}
+
+	// short input
+	if err := p.Assign(ecc.BN254, pubKey[:len(pubKey)-1]); err == nil {
+		t.Fatal("assigning a truncated public key should fail")
+	}
+	if err := sig.Assign(ecc.BN254, signature[:len(pubKey)]); err == nil {
+		t.Fatal("assigning a signature without S should fail")
+	}
+}
+
+func TestEddsaMalformed(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	curve, err := twistededwards.NewEdCurve(ecc.BN254)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privKey, err := eddsabn254.GenerateKey(rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privKey.Public().Bytes()
+
+	var e fr.Element
+	e.SetUint64(42)
+	msg := e.Bytes()
+	signature, err := privKey.Sign(msg[:], hash.MIMC_BN254.New("seed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var circuit eddsaCircuit
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var good eddsaCircuit
+	if err := good.PublicKey.Assign(ecc.BN254, pubKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := good.Signature.Assign(ecc.BN254, signature); err != nil {
+		t.Fatal(err)
+	}
+	good.Message.Assign(msg[:])
+	assert.SolvingSucceeded(r1cs, &good)
+
+	value := func(v frontend.Variable) big.Int {
+		return frontend.FromInterface(frontend.GetAssignedValue(v))
+	}
+	newWitness := func(ry, s big.Int) *eddsaCircuit {
+		var witness eddsaCircuit
+		if err := witness.PublicKey.Assign(ecc.BN254, pubKey); err != nil {
+			t.Fatal(err)
+		}
+		witness.Signature.R.X.Assign(value(good.Signature.R.X))
+		witness.Signature.R.Y.Assign(ry)
+		witness.Signature.S.Assign(s)
+		witness.Message.Assign(msg[:])
+		return &witness
+	}
+
+	// R off the curve
+	ry := value(good.Signature.R.Y)
+	ry.Add(&ry, big.NewInt(1))
+	assert.SolvingFailed(r1cs, newWitness(ry, value(good.Signature.S)))
+
+	// S + Order gives the same point, but the signature must be unique
+	s := value(good.Signature.S)
+	s.Add(&s, &curve.Order)
+	assert.SolvingFailed(r1cs, newWitness(value(good.Signature.R.Y), s))
+}
@@ -0,0 +1,235 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// Native implementation of the Schnorr signatures verified by the circuits, for any curve of
+// std/algebra/twistededwards. The hash is fed with the field elements as big endian
+// byte slices of the size of the snark field (for instance, 32 bytes for ecc.BN254), as MiMC in gnark-crypto.
+//
+// The native implementation is meant to produce test vectors and witnesses: the scalar multiplications
+// run in variable time, on math/big integers, and leak the secret keys and nonces through timing.
+// It must not be used to sign with secret keys exposed to an attacker.
+
+// NativePublicKey is a Schnorr public key, out of a circuit
+type NativePublicKey struct {
+	X, Y big.Int
+}
+
+// NativeSignature is a Schnorr signature, out of a circuit
+type NativeSignature struct {
+	RX, RY, S big.Int
+}
+
+// PrivateKey is a Schnorr private key, with its public key
+type PrivateKey struct {
+	Public NativePublicKey
+	secret big.Int
+	curve  twistededwards.EdCurve
+}
+
+// Assign assigns the public key to the circuit public key
+func (pubKey *PublicKey) Assign(native NativePublicKey) {
+	pubKey.A.X.Assign(native.X)
+	pubKey.A.Y.Assign(native.Y)
+}
+
+// Assign assigns the signature to the circuit signature
+func (sig *Signature) Assign(native NativeSignature) {
+	sig.R.X.Assign(native.RX)
+	sig.R.Y.Assign(native.RY)
+	sig.S.Assign(native.S)
+}
+
+// GenerateKey returns a private key on curve, drawing the secret from r
+//
+// the public key is computed in variable time: see twistededwards.NativeScalarMul.
+func GenerateKey(curve twistededwards.EdCurve, r io.Reader) (*PrivateKey, error) {
+	secret, err := randomScalar(curve, r)
+	if err != nil {
+		return nil, err
+	}
+	res := &PrivateKey{curve: curve}
+	res.secret.Set(secret)
+	a := twistededwards.NativeScalarMul(curve, basePoint(curve), secret)
+	res.Public.X.Set(&a.X)
+	res.Public.Y.Set(&a.Y)
+	return res, nil
+}
+
+// Sign returns the signature of the message msg, made of one or several field elements,
+// the nonce being drawn from r
+//
+// the signature is computed in variable time: see twistededwards.NativeScalarMul.
+func (privKey *PrivateKey) Sign(h hash.Hash, r io.Reader, msg ...*big.Int) (NativeSignature, error) {
+	return signAggregated(privKey.curve, h, r, []*PrivateKey{privKey}, []*big.Int{big.NewInt(1)}, privKey.Public, msg)
+}
+
+// SignAggregated returns the signature of the message msg under the MuSig aggregation of the public keys
+// of privKeys (see AggregateNativeKeys), as produced by the signers together, the nonces being drawn from r
+//
+// the private keys must be on the same curve.
+func SignAggregated(h hash.Hash, r io.Reader, privKeys []*PrivateKey, msg ...*big.Int) (NativeSignature, error) {
+	if len(privKeys) == 0 {
+		return NativeSignature{}, errors.New("schnorr: no private key")
+	}
+	curve := privKeys[0].curve
+	pubKeys := make([]NativePublicKey, len(privKeys))
+	for i := range privKeys {
+		pubKeys[i] = privKeys[i].Public
+	}
+	pubKey, coeffs := aggregateKeys(curve, h, pubKeys)
+	return signAggregated(curve, h, r, privKeys, coeffs, pubKey, msg)
+}
+
+// AggregateNativeKeys returns the MuSig aggregation of the public keys on curve
+func AggregateNativeKeys(curve twistededwards.EdCurve, h hash.Hash, pubKeys []NativePublicKey) NativePublicKey {
+	res, _ := aggregateKeys(curve, h, pubKeys)
+	return res
+}
+
+// Verify returns true if sig is a valid signature of the message msg under the public key on curve
+//
+// R and the public key must be points of the curve, and S must be in [0, curve.Order).
+func (pubKey NativePublicKey) Verify(curve twistededwards.EdCurve, h hash.Hash, sig NativeSignature, msg ...*big.Int) bool {
+	r, a := point(&sig.RX, &sig.RY), point(&pubKey.X, &pubKey.Y)
+	if !r.IsOnCurve(curve) || !a.IsOnCurve(curve) {
+		return false
+	}
+	if sig.S.Sign() < 0 || sig.S.Cmp(&curve.Order) >= 0 {
+		return false
+	}
+
+	e := challenge(curve, h, &sig.RX, &sig.RY, pubKey, msg)
+
+	lhs := twistededwards.NativeScalarMul(curve, basePoint(curve), &sig.S)
+	lhs = twistededwards.NativeScalarMul(curve, lhs, &curve.Cofactor)
+
+	rhs := twistededwards.NativeScalarMul(curve, a, e)
+	rhs = twistededwards.NativeAdd(curve, rhs, r)
+	rhs = twistededwards.NativeScalarMul(curve, rhs, &curve.Cofactor)
+
+	return lhs.X.Cmp(&rhs.X) == 0 && lhs.Y.Cmp(&rhs.Y) == 0
+}
+
+// signAggregated signs msg for pubKey, the secret of privKeys[i] being multiplied by coeffs[i]
+func signAggregated(curve twistededwards.EdCurve, h hash.Hash, r io.Reader, privKeys []*PrivateKey, coeffs []*big.Int, pubKey NativePublicKey, msg []*big.Int) (NativeSignature, error) {
+
+	// each signer draws a nonce k_i, R = sum([k_i]B)
+	nonces := make([]*big.Int, len(privKeys))
+	rPoint := point(big.NewInt(0), big.NewInt(1))
+	for i := range privKeys {
+		k, err := randomScalar(curve, r)
+		if err != nil {
+			return NativeSignature{}, err
+		}
+		nonces[i] = k
+		rPoint = twistededwards.NativeAdd(curve, rPoint, twistededwards.NativeScalarMul(curve, basePoint(curve), k))
+	}
+
+	e := challenge(curve, h, &rPoint.X, &rPoint.Y, pubKey, msg)
+
+	// S = sum(k_i + e*a_i*x_i) mod l
+	var res NativeSignature
+	res.RX.Set(&rPoint.X)
+	res.RY.Set(&rPoint.Y)
+	var tmp big.Int
+	for i := range privKeys {
+		tmp.Mul(e, coeffs[i]).
+			Mul(&tmp, &privKeys[i].secret).
+			Add(&tmp, nonces[i])
+		res.S.Add(&res.S, &tmp)
+	}
+	res.S.Mod(&res.S, &curve.Order)
+
+	return res, nil
+}
+
+// aggregateKeys returns the MuSig aggregation of the public keys and the coefficients of the keys
+func aggregateKeys(curve twistededwards.EdCurve, h hash.Hash, pubKeys []NativePublicKey) (NativePublicKey, []*big.Int) {
+	var elements []*big.Int
+	for i := range pubKeys {
+		elements = append(elements, &pubKeys[i].X, &pubKeys[i].Y)
+	}
+	l := hashElements(curve, h, elements...)
+
+	coeffs := make([]*big.Int, len(pubKeys))
+	a := point(big.NewInt(0), big.NewInt(1))
+	for i := range pubKeys {
+		coeffs[i] = hashElements(curve, h, l, &pubKeys[i].X, &pubKeys[i].Y)
+		term := twistededwards.NativeScalarMul(curve, point(&pubKeys[i].X, &pubKeys[i].Y), coeffs[i])
+		a = twistededwards.NativeAdd(curve, a, term)
+	}
+
+	var res NativePublicKey
+	res.X.Set(&a.X)
+	res.Y.Set(&a.Y)
+	return res, coeffs
+}
+
+// challenge returns H(R, A, M)
+func challenge(curve twistededwards.EdCurve, h hash.Hash, rx, ry *big.Int, pubKey NativePublicKey, msg []*big.Int) *big.Int {
+	elements := append([]*big.Int{rx, ry, &pubKey.X, &pubKey.Y}, msg...)
+	return hashElements(curve, h, elements...)
+}
+
+// hashElements resets h and returns the hash of the field elements
+func hashElements(curve twistededwards.EdCurve, h hash.Hash, elements ...*big.Int) *big.Int {
+	size := (curve.Modulus.BitLen() + 7) / 8
+	h.Reset()
+	for _, e := range elements {
+		var reduced big.Int
+		reduced.Mod(e, &curve.Modulus)
+		buf := make([]byte, size)
+		reduced.FillBytes(buf)
+		h.Write(buf)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// randomScalar returns a random non-zero scalar modulo the order of the subgroup of the base point
+func randomScalar(curve twistededwards.EdCurve, r io.Reader) (*big.Int, error) {
+	var max big.Int
+	max.Sub(&curve.Order, big.NewInt(1))
+	k, err := rand.Int(r, &max)
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(k, big.NewInt(1)), nil
+}
+
+// point returns the point (x, y)
+func point(x, y *big.Int) twistededwards.NativePoint {
+	var res twistededwards.NativePoint
+	res.X.Set(x)
+	res.Y.Set(y)
+	return res
+}
+
+// basePoint returns the base point of the curve
+func basePoint(curve twistededwards.EdCurve) twistededwards.NativePoint {
+	return point(&curve.BaseX, &curve.BaseY)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schnorr provides ZKP-circuit functions to verify Schnorr signatures over twisted Edwards curves,
+// with a single public key or with an aggregation of public keys (MuSig).
+//
+// A signature of a message M under the public key A = [x]B is a tuple (R, S), where R = [k]B and
+// S = k + e*x mod l, l being the order of the subgroup of the base point B and e = H(R, A, M) the challenge.
+// It is valid if [cofactor*S]B = [cofactor](R + [e]A).
+//
+// With MuSig, the public keys A_i are aggregated in A = sum([a_i]A_i), where a_i = H(L, A_i) and
+// L = H(A_1, ..., A_n); the signers sign for A with the secret key a_i*x_i each, and sum their signatures.
+package schnorr
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// PublicKey stores a Schnorr public key (to be used in gnark circuit)
+type PublicKey struct {
+	A     twistededwards.Point
+	Curve twistededwards.EdCurve
+}
+
+// Signature stores a Schnorr signature (to be used in gnark circuit)
+// S is smaller than the order of the subgroup of the twisted Edwards curve (Verify constrains it
+// as such, so that the signatures are not malleable), which is smaller than r, the size of the zk snark field.
+type Signature struct {
+	R twistededwards.Point
+	S frontend.Variable
+}
+
+// Verify verifies a Schnorr signature of the message msg, made of one or several field elements
+//
+// the challenge is computed with h, which is reset, then fed with R.X, R.Y, A.X, A.Y and the elements of msg.
+// R and A are constrained to be on the curve, and S to be smaller than the order of the subgroup.
+func Verify(cs *frontend.ConstraintSystem, h hash.Hash, sig Signature, pubKey PublicKey, msg ...frontend.Variable) error {
+
+	sig.R.MustBeOnCurve(cs, pubKey.Curve)
+	pubKey.A.MustBeOnCurve(cs, pubKey.Curve)
+	twistededwards.MustBeScalar(cs, sig.S, pubKey.Curve)
+
+	// e = H(R, A, M)
+	h.Reset()
+	h.Write(sig.R.X, sig.R.Y, pubKey.A.X, pubKey.A.Y)
+	h.Write(msg...)
+	e := h.Sum()
+
+	cofactor := cs.Constant(pubKey.Curve.Cofactor)
+
+	// lhs = [cofactor*S]B
+	lhs := twistededwards.Point{}
+	lhs.ScalarMulFixedBase(cs, pubKey.Curve.BaseX, pubKey.Curve.BaseY, sig.S, pubKey.Curve).
+		ScalarMulNonFixedBase(cs, &lhs, cofactor, pubKey.Curve)
+	lhs.MustBeOnCurve(cs, pubKey.Curve)
+
+	// rhs = [cofactor](R + [e]A)
+	rhs := twistededwards.Point{}
+	rhs.ScalarMulNonFixedBase(cs, &pubKey.A, e, pubKey.Curve).
+		AddGeneric(cs, &rhs, &sig.R, pubKey.Curve).
+		ScalarMulNonFixedBase(cs, &rhs, cofactor, pubKey.Curve)
+	rhs.MustBeOnCurve(cs, pubKey.Curve)
+
+	cs.AssertIsEqual(lhs.X, rhs.X)
+	cs.AssertIsEqual(lhs.Y, rhs.Y)
+
+	return nil
+}
+
+// AggregateKeys returns the MuSig aggregation of the public keys, on the curve of the first key
+//
+// the coefficients of the keys are computed with h, which is reset before each hash. The keys are
+// constrained to be on the curve.
+func AggregateKeys(cs *frontend.ConstraintSystem, h hash.Hash, pubKeys []PublicKey) PublicKey {
+	if len(pubKeys) == 0 {
+		panic("schnorr: no public key to aggregate")
+	}
+	curve := pubKeys[0].Curve
+
+	// L = H(A_1, ..., A_n)
+	h.Reset()
+	for _, pubKey := range pubKeys {
+		pubKey.A.MustBeOnCurve(cs, curve)
+		h.Write(pubKey.A.X, pubKey.A.Y)
+	}
+	l := h.Sum()
+
+	res := PublicKey{Curve: curve}
+	for i, pubKey := range pubKeys {
+		// a_i = H(L, A_i)
+		h.Reset()
+		h.Write(l, pubKey.A.X, pubKey.A.Y)
+		a := h.Sum()
+
+		var term twistededwards.Point
+		term.ScalarMulNonFixedBase(cs, &pubKey.A, a, curve)
+		if i == 0 {
+			res.A = term
+		} else {
+			res.A.AddGeneric(cs, &res.A, &term, curve)
+		}
+	}
+
+	return res
+}
+
+// VerifyAggregated verifies a Schnorr signature of the message msg under the MuSig aggregation
+// of the public keys (see AggregateKeys and Verify)
+func VerifyAggregated(cs *frontend.ConstraintSystem, h hash.Hash, sig Signature, pubKeys []PublicKey, msg ...frontend.Variable) error {
+	return Verify(cs, h, sig, AggregateKeys(cs, h, pubKeys), msg...)
+}
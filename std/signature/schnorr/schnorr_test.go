@@ -0,0 +1,218 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schnorr
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+const nbSigners = 3
+
+type schnorrCircuit struct {
+	PublicKey PublicKey
+	Signature Signature
+	Message   [2]frontend.Variable `gnark:",public"`
+}
+
+func (circuit *schnorrCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	curve, err := twistededwards.NewEdCurve(curveID)
+	if err != nil {
+		return err
+	}
+	circuit.PublicKey.Curve = curve
+
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	return Verify(cs, &h, circuit.Signature, circuit.PublicKey, circuit.Message[:]...)
+}
+
+type aggregatedCircuit struct {
+	PublicKeys [nbSigners]PublicKey `gnark:",public"`
+	Signature  Signature
+	Message    frontend.Variable `gnark:",public"`
+}
+
+func (circuit *aggregatedCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	curve, err := twistededwards.NewEdCurve(curveID)
+	if err != nil {
+		return err
+	}
+	for i := range circuit.PublicKeys {
+		circuit.PublicKeys[i].Curve = curve
+	}
+
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	return VerifyAggregated(cs, &h, circuit.Signature, circuit.PublicKeys[:], circuit.Message)
+}
+
+func TestVerify(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	curve, err := twistededwards.NewEdCurve(ecc.BN254)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := hash.MIMC_BN254.New("seed")
+	r := rand.New(rand.NewSource(0))
+
+	privKey, err := GenerateKey(curve, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []*big.Int{big.NewInt(42), big.NewInt(43)}
+	sig, err := privKey.Sign(h, r, msg...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !privKey.Public.Verify(curve, h, sig, msg...) {
+		t.Fatal("native verification failed")
+	}
+
+	// R and the public key must be on the curve
+	var offCurve NativeSignature
+	offCurve.RX.Set(&sig.RX)
+	offCurve.RY.Add(&sig.RY, big.NewInt(1))
+	offCurve.S.Set(&sig.S)
+	if privKey.Public.Verify(curve, h, offCurve, msg...) {
+		t.Fatal("native verification should fail with R off the curve")
+	}
+	var offCurveKey NativePublicKey
+	offCurveKey.X.Set(&privKey.Public.X)
+	offCurveKey.Y.Add(&privKey.Public.Y, big.NewInt(1))
+	if offCurveKey.Verify(curve, h, sig, msg...) {
+		t.Fatal("native verification should fail with a public key off the curve")
+	}
+
+	// S + Order gives the same point, but the signature must be unique
+	var shifted NativeSignature
+	shifted.RX.Set(&sig.RX)
+	shifted.RY.Set(&sig.RY)
+	shifted.S.Add(&sig.S, &curve.Order)
+	if privKey.Public.Verify(curve, h, shifted, msg...) {
+		t.Fatal("native verification should fail with S not smaller than the order")
+	}
+
+	var circuit schnorrCircuit
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newWitness := func() *schnorrCircuit {
+		var witness schnorrCircuit
+		witness.PublicKey.Assign(privKey.Public)
+		witness.Signature.Assign(sig)
+		witness.Message[0].Assign(msg[0])
+		witness.Message[1].Assign(msg[1])
+		return &witness
+	}
+
+	assert.SolvingSucceeded(r1cs, newWitness())
+
+	{
+		witness := newWitness()
+		witness.Message[1] = frontend.Variable{}
+		witness.Message[1].Assign(44)
+		assert.SolvingFailed(r1cs, witness)
+	}
+	{
+		otherKey, err := GenerateKey(curve, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		witness := newWitness()
+		witness.PublicKey = PublicKey{}
+		witness.PublicKey.Assign(otherKey.Public)
+		assert.SolvingFailed(r1cs, witness)
+	}
+	{
+		witness := newWitness()
+		witness.Signature = Signature{}
+		witness.Signature.Assign(offCurve)
+		assert.SolvingFailed(r1cs, witness)
+	}
+	{
+		witness := newWitness()
+		witness.Signature = Signature{}
+		witness.Signature.Assign(shifted)
+		assert.SolvingFailed(r1cs, witness)
+	}
+}
+
+func TestVerifyAggregated(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	curve, err := twistededwards.NewEdCurve(ecc.BN254)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := hash.MIMC_BN254.New("seed")
+	r := rand.New(rand.NewSource(0))
+
+	privKeys := make([]*PrivateKey, nbSigners)
+	pubKeys := make([]NativePublicKey, nbSigners)
+	for i := range privKeys {
+		if privKeys[i], err = GenerateKey(curve, r); err != nil {
+			t.Fatal(err)
+		}
+		pubKeys[i] = privKeys[i].Public
+	}
+	msg := big.NewInt(42)
+	sig, err := SignAggregated(h, r, privKeys, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !AggregateNativeKeys(curve, h, pubKeys).Verify(curve, h, sig, msg) {
+		t.Fatal("native verification failed")
+	}
+
+	var circuit aggregatedCircuit
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newWitness := func(pubKeys []NativePublicKey) *aggregatedCircuit {
+		var witness aggregatedCircuit
+		for i := range witness.PublicKeys {
+			witness.PublicKeys[i].Assign(pubKeys[i])
+		}
+		witness.Signature.Assign(sig)
+		witness.Message.Assign(msg)
+		return &witness
+	}
+
+	assert.SolvingSucceeded(r1cs, newWitness(pubKeys))
+
+	// the aggregation depends on the order of the keys
+	assert.SolvingFailed(r1cs, newWitness([]NativePublicKey{pubKeys[1], pubKeys[0], pubKeys[2]}))
+}
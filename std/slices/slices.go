@@ -0,0 +1,158 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package slices provides variable-length arrays for circuits: arrays of fixed capacity
+// with a secret length.
+package slices
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Slice is an array of capacity len(Values) with a secret length Len
+//
+// The elements Values[Len:] are padding: they are expected to be 0 (see AssertIsPadded). In a circuit
+// definition, Values must be allocated with the capacity of the slice before compiling the circuit.
+type Slice struct {
+	Values []frontend.Variable
+	Len    frontend.Variable
+}
+
+// Capacity returns the maximum length of s
+func (s *Slice) Capacity() int {
+	return len(s.Values)
+}
+
+// AssertIsPadded constrains s.Len to be in [0, s.Capacity()] and the padding elements to be 0
+func (s *Slice) AssertIsPadded(cs *frontend.ConstraintSystem) {
+	mask := selector.PrefixMask(cs, s.Len, len(s.Values))
+	for i := range s.Values {
+		// (1 - mask[i]) ⋅ Values[i] == 0
+		cs.AssertIsEqual(cs.Mul(cs.Sub(1, mask[i]), s.Values[i]), 0)
+	}
+}
+
+// Hash returns the MiMC hash of the first s.Len elements of s
+//
+// h is reset before hashing; the padding elements are ignored. s.Len is constrained
+// to be in [0, s.Capacity()].
+func (s *Slice) Hash(cs *frontend.ConstraintSystem, h mimc.MiMC) frontend.Variable {
+	h.Reset()
+
+	// states[i] is the hash of the first i elements
+	states := make([]frontend.Variable, len(s.Values)+1)
+	states[0] = h.Sum()
+	for i := range s.Values {
+		h.Write(s.Values[i])
+		states[i+1] = h.Sum()
+	}
+
+	return selector.Mux(cs, s.Len, states...)
+}
+
+// AssertIsEqual asserts that s and other have the same length and the same elements;
+// the padding elements are not compared
+func (s *Slice) AssertIsEqual(cs *frontend.ConstraintSystem, other *Slice) {
+	cs.AssertIsEqual(s.Len, other.Len)
+
+	mask := selector.PrefixMask(cs, s.Len, len(s.Values))
+	for i := range mask {
+		if i < len(other.Values) {
+			cs.AssertIsEqual(cs.Mul(mask[i], cs.Sub(s.Values[i], other.Values[i])), 0)
+		} else {
+			// s.Len <= other.Capacity()
+			cs.AssertIsEqual(mask[i], 0)
+			break
+		}
+	}
+}
+
+// AssertHasPrefix asserts that prefix.Len <= s.Len, and that the first prefix.Len elements
+// of s and prefix are equal; the padding elements are not compared
+func (s *Slice) AssertHasPrefix(cs *frontend.ConstraintSystem, prefix *Slice) {
+	nbBits := bits.Len(uint(len(s.Values)))
+	if l := bits.Len(uint(len(prefix.Values))); l > nbBits {
+		nbBits = l
+	}
+	if nbBits == 0 {
+		nbBits = 1
+	}
+	cs.AssertIsEqual(cs.IsLessOrEqual(prefix.Len, s.Len, nbBits), 1)
+
+	mask := selector.PrefixMask(cs, prefix.Len, len(prefix.Values))
+	for i := range mask {
+		if i < len(s.Values) {
+			cs.AssertIsEqual(cs.Mul(mask[i], cs.Sub(s.Values[i], prefix.Values[i])), 0)
+		} else {
+			// prefix.Len <= s.Capacity()
+			cs.AssertIsEqual(mask[i], 0)
+			break
+		}
+	}
+}
+
+// Concat returns the concatenation of a and b, of capacity a.Capacity() + b.Capacity()
+//
+// the padding elements of a and b are ignored, the result is padded with zeros. a.Len and b.Len
+// are constrained to be in [0, a.Capacity()] and [0, b.Capacity()].
+func Concat(cs *frontend.ConstraintSystem, a, b *Slice) Slice {
+	n := len(a.Values) + len(b.Values)
+	maskA := selector.PrefixMask(cs, a.Len, len(a.Values))
+	maskB := selector.PrefixMask(cs, b.Len, len(b.Values))
+
+	// the elements of b are shifted by a.Len with a barrel shifter on the bits of a.Len:
+	// at step t, the elements are shifted by 2^t if the bit t of a.Len is set
+	shifted := make([]frontend.Variable, n)
+	for i := range shifted {
+		if i < len(b.Values) {
+			shifted[i] = cs.Mul(maskB[i], b.Values[i])
+		} else {
+			shifted[i] = cs.Constant(0)
+		}
+	}
+	// if a has no capacity, a.Len is constrained to 0 by maskA and b is not shifted
+	var lenBits []frontend.Variable
+	if len(a.Values) != 0 {
+		lenBits = cs.ToBinary(a.Len, bits.Len(uint(len(a.Values))))
+	}
+	for t, bit := range lenBits {
+		next := make([]frontend.Variable, n)
+		for i := range next {
+			if j := i - 1<<uint(t); j >= 0 {
+				next[i] = cs.Select(bit, shifted[j], shifted[i])
+			} else {
+				next[i] = cs.Select(bit, 0, shifted[i])
+			}
+		}
+		shifted = next
+	}
+
+	// the shifted elements of b are 0 before a.Len
+	values := make([]frontend.Variable, n)
+	for i := range values {
+		if i < len(a.Values) {
+			values[i] = cs.Add(cs.Mul(maskA[i], a.Values[i]), shifted[i])
+		} else {
+			values[i] = shifted[i]
+		}
+	}
+
+	return Slice{Values: values, Len: cs.Add(a.Len, b.Len)}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slices
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+type slicesCircuit struct {
+	A, B, AB, Prefix Slice
+	HashA            frontend.Variable `gnark:",public"`
+}
+
+func newSlicesCircuit() *slicesCircuit {
+	return &slicesCircuit{
+		A:      Slice{Values: make([]frontend.Variable, 4)},
+		B:      Slice{Values: make([]frontend.Variable, 3)},
+		AB:     Slice{Values: make([]frontend.Variable, 7)},
+		Prefix: Slice{Values: make([]frontend.Variable, 2)},
+	}
+}
+
+func (circuit *slicesCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	circuit.A.AssertIsPadded(cs)
+	circuit.B.AssertIsPadded(cs)
+
+	h, err := mimc.NewMiMC("seed", curveID, cs)
+	if err != nil {
+		return err
+	}
+	cs.AssertIsEqual(circuit.A.Hash(cs, h), circuit.HashA)
+
+	ab := Concat(cs, &circuit.A, &circuit.B)
+	ab.AssertIsEqual(cs, &circuit.AB)
+
+	circuit.A.AssertHasPrefix(cs, &circuit.Prefix)
+	return nil
+}
+
+// assign assigns the values to s, padded with zeros
+func assign(s *Slice, values ...uint64) {
+	for i := range s.Values {
+		if i < len(values) {
+			s.Values[i].Assign(values[i])
+		} else {
+			s.Values[i].Assign(0)
+		}
+	}
+	s.Len.Assign(len(values))
+}
+
+// mimcHash returns the MiMC hash (BN254) of the values
+func mimcHash(values ...uint64) []byte {
+	h := hash.MIMC_BN254.New("seed")
+	for _, v := range values {
+		var e fr.Element
+		e.SetUint64(v)
+		b := e.Bytes()
+		h.Write(b[:])
+	}
+	return h.Sum(nil)
+}
+
+func TestSlices(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, newSlicesCircuit())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newWitness := func(a, b, ab, prefix []uint64) *slicesCircuit {
+		witness := newSlicesCircuit()
+		assign(&witness.A, a...)
+		assign(&witness.B, b...)
+		assign(&witness.AB, ab...)
+		assign(&witness.Prefix, prefix...)
+		witness.HashA.Assign(mimcHash(a...))
+		return witness
+	}
+
+	assert.SolvingSucceeded(r1cs, newWitness([]uint64{1, 2, 3}, []uint64{4, 5}, []uint64{1, 2, 3, 4, 5}, []uint64{1, 2}))
+	assert.SolvingSucceeded(r1cs, newWitness([]uint64{1, 2, 3, 4}, []uint64{5, 6, 7}, []uint64{1, 2, 3, 4, 5, 6, 7}, []uint64{1}))
+	assert.SolvingSucceeded(r1cs, newWitness(nil, []uint64{4, 5, 6}, []uint64{4, 5, 6}, nil))
+	assert.SolvingSucceeded(r1cs, newWitness(nil, nil, nil, nil))
+
+	// wrong concatenation, length or prefix
+	assert.SolvingFailed(r1cs, newWitness([]uint64{1, 2, 3}, []uint64{4, 5}, []uint64{1, 2, 3, 5, 4}, []uint64{1, 2}))
+	assert.SolvingFailed(r1cs, newWitness([]uint64{1, 2, 3}, []uint64{4, 5}, []uint64{1, 2, 3, 4}, []uint64{1, 2}))
+	assert.SolvingFailed(r1cs, newWitness([]uint64{1, 2, 3}, []uint64{4, 5}, []uint64{1, 2, 3, 4, 5}, []uint64{2, 2}))
+	assert.SolvingFailed(r1cs, newWitness([]uint64{1}, []uint64{4, 5}, []uint64{1, 4, 5}, []uint64{1, 2}))
+
+	// wrong hash
+	witness := newWitness([]uint64{1, 2, 3}, []uint64{4, 5}, []uint64{1, 2, 3, 4, 5}, []uint64{1, 2})
+	witness.HashA = frontend.Variable{}
+	witness.HashA.Assign(mimcHash(1, 2))
+	assert.SolvingFailed(r1cs, witness)
+
+	// non-zero padding
+	witness = newSlicesCircuit()
+	assign(&witness.A, 1, 2, 3, 9)
+	witness.A.Len = frontend.Variable{}
+	witness.A.Len.Assign(3)
+	assign(&witness.B, 4, 5)
+	assign(&witness.AB, 1, 2, 3, 4, 5)
+	assign(&witness.Prefix, 1, 2)
+	witness.HashA.Assign(mimcHash(1, 2, 3))
+	assert.SolvingFailed(r1cs, witness)
+}
+
+type concatEmptyCircuit struct {
+	A, B, AB Slice
+}
+
+func (circuit *concatEmptyCircuit) Define(curveID ecc.ID, cs *frontend.ConstraintSystem) error {
+	ab := Concat(cs, &circuit.A, &circuit.B)
+	ab.AssertIsEqual(cs, &circuit.AB)
+	return nil
+}
+
+func TestConcatEmpty(t *testing.T) {
+	assert := groth16.NewAssert(t)
+
+	newCircuit := func() *concatEmptyCircuit {
+		return &concatEmptyCircuit{
+			B:  Slice{Values: make([]frontend.Variable, 2)},
+			AB: Slice{Values: make([]frontend.Variable, 2)},
+		}
+	}
+
+	r1cs, err := frontend.Compile(ecc.BN254, backend.GROTH16, newCircuit())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newWitness := func(aLen int, b, ab []uint64) *concatEmptyCircuit {
+		witness := newCircuit()
+		witness.A.Len.Assign(aLen)
+		assign(&witness.B, b...)
+		assign(&witness.AB, ab...)
+		return witness
+	}
+
+	assert.SolvingSucceeded(r1cs, newWitness(0, []uint64{4, 5}, []uint64{4, 5}))
+	assert.SolvingSucceeded(r1cs, newWitness(0, nil, nil))
+
+	// a has no capacity, so its length must be 0
+	assert.SolvingFailed(r1cs, newWitness(1, []uint64{4}, []uint64{4}))
+	assert.SolvingFailed(r1cs, newWitness(0, []uint64{4, 5}, []uint64{5, 4}))
+}